@@ -0,0 +1,363 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"doclific/internal/core"
+	"doclific/internal/openapi"
+
+	"github.com/spf13/cobra"
+)
+
+var lintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Check docs for broken links",
+	Long:  `Check every doc for cross-doc links (doc:<uuid> or doc:<slug/path>) that point at deleted or moved docs.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println("🔍 Checking doc links...")
+
+		graph, err := core.BuildLinkGraph()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		broken := graph.BrokenLinks()
+		for _, link := range broken {
+			fmt.Printf("   %s (%s:%d) → doc:%s: %s\n", link.SourceTitle, link.Source, link.Line, link.Target, link.Reason)
+		}
+
+		if len(broken) > 0 {
+			fmt.Fprintf(os.Stderr, "❌ Found %d broken link(s) in %d link(s)\n", len(broken), len(graph.Links))
+			os.Exit(1)
+		}
+
+		fmt.Printf("✅ All %d link(s) resolve\n", len(graph.Links))
+	},
+}
+
+var staleCmd = &cobra.Command{
+	Use:   "stale",
+	Short: "List docs whose referenced code has changed",
+	Long:  `List every doc embedding a CodebaseSnippet whose file has changed since the snippet's baseCommit, ranked by how much the code changed.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		staleDocs, err := core.GetStaleDocs()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(staleDocs) == 0 {
+			fmt.Println("✅ No docs reference code that changed since their snippets were synced")
+			return
+		}
+
+		fmt.Printf("📋 %d doc(s) reference changed code:\n\n", len(staleDocs))
+		for _, doc := range staleDocs {
+			fmt.Printf("%s (%s) — %d line(s) changed in %d commit(s)\n", doc.Title, doc.FilePath, doc.Score, doc.Commits)
+			if len(doc.Owners) > 0 {
+				fmt.Printf("   owners: %s\n", strings.Join(doc.Owners, ", "))
+			}
+			for _, snippet := range doc.Snippets {
+				if snippet.Error != "" {
+					fmt.Printf("   ⚠️  %s: %s\n", snippet.FilePath, snippet.Error)
+					continue
+				}
+				fmt.Printf("   %s:%s-%s  +%d -%d in %d commit(s) since %.7s\n",
+					snippet.FilePath, snippet.LineStart, snippet.LineEnd,
+					snippet.LinesAdded, snippet.LinesDeleted, snippet.Commits, snippet.BaseCommit)
+			}
+			fmt.Println()
+		}
+	},
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import <dir>",
+	Short: "Import a folder of Markdown files as docs",
+	Long: `Walk a directory of .md/.mdx files (such as docs/ or a wiki export) and create the matching doc tree. Folders become parent docs, titles come from front matter, the first heading or the file name, and file order is preserved.
+
+Use doclific import openapi <spec> to generate docs from an OpenAPI spec instead.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		parent, _ := cmd.Flags().GetString("parent")
+		snippets, _ := cmd.Flags().GetBool("snippets")
+
+		fmt.Printf("📥 Importing %s...\n", args[0])
+
+		result, err := core.ImportMarkdown(core.ImportOptions{
+			SourceDir:      args[0],
+			ParentPath:     parent,
+			DetectSnippets: snippets,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		for _, doc := range result.Docs {
+			source := doc.Source
+			if source == "" {
+				source = "(folder)"
+			}
+			fmt.Printf("   %s → %s (%s)\n", source, doc.Title, doc.FilePath)
+		}
+
+		fmt.Printf("✅ Imported %d doc(s)", len(result.Docs))
+		if snippets {
+			fmt.Printf(", converted %d code block(s) to CodebaseSnippets", result.Snippets)
+		}
+		fmt.Println()
+	},
+}
+
+var importOpenAPICmd = &cobra.Command{
+	Use:   "openapi <spec>",
+	Short: "Import an OpenAPI spec as HttpRequest docs",
+	Long: `Generate a doc subtree from an OpenAPI 3 spec in YAML or JSON. The root page describes the API, its servers and authentication. Below it, each tag gets a page holding its operations (--per tag) or a child page per operation (--per operation).
+
+Every operation is documented with its description, parameter and response tables and a prefilled <HttpRequest> block. The method, URL, query parameters, headers, JSON body example and auth type come from the spec, and the block's expectStatus is the first documented 2xx status so the pages can run with doclific http run.
+
+URLs start with the first server's URL, or {{baseUrl}} when it is relative. Credentials are {{token}}, {{username}}/{{password}} or {{apiKey}} variables, and path parameters without an example are {{name}} variables, all resolved from the active environment.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		parent, _ := cmd.Flags().GetString("parent")
+		per, _ := cmd.Flags().GetString("per")
+		baseURL, _ := cmd.Flags().GetString("base-url")
+
+		fmt.Printf("📥 Importing %s...\n", args[0])
+
+		result, err := openapi.Import(openapi.ImportOptions{
+			SpecPath:   args[0],
+			ParentPath: parent,
+			Per:        per,
+			BaseURL:    baseURL,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		for _, doc := range result.Docs {
+			fmt.Printf("   %s (%s)\n", doc.Title, doc.FilePath)
+		}
+		fmt.Printf("✅ Imported %d doc(s) with %d request(s)\n", len(result.Docs), result.Requests)
+	},
+}
+
+var newCmd = &cobra.Command{
+	Use:   "new",
+	Short: "Create a new doc",
+	Long:  `Create a new doc under a parent given as a folder path, UUID, slug path or title path. The icon must be one of the names in skills/create-new-doclific-doc/all-icons.json.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		parentRef, _ := cmd.Flags().GetString("parent")
+		title, _ := cmd.Flags().GetString("title")
+		iconName, _ := cmd.Flags().GetString("icon")
+		templateName, _ := cmd.Flags().GetString("template")
+
+		if strings.TrimSpace(title) == "" {
+			fmt.Fprintln(os.Stderr, "❌ Error: --title is required")
+			os.Exit(1)
+		}
+
+		var icon *string
+		if iconName != "" {
+			if err := core.ValidateIcon(iconName); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+				os.Exit(1)
+			}
+			icon = &iconName
+		}
+
+		parent, err := core.ResolveDocRef(parentRef)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		result, err := core.CreateDoc(parent, title, icon, templateName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("✅ Created new document at: %s\n", result.FilePath)
+		fmt.Printf("   Title: %s\n", result.Title)
+		if icon != nil {
+			fmt.Printf("   Icon: %s\n", *icon)
+		}
+		fmt.Printf("   Path: %s\n", result.URL)
+	},
+}
+
+var pathCmd = &cobra.Command{
+	Use:   "path <title path>",
+	Short: "Print the folder of a doc given its title path",
+	Long:  `Resolve a title path such as "Architecture Overview/Backend Development" to the doc's folder. Titles are matched ignoring case; a path that does not start at the root may name a doc by its last titles. Use "\/" for a slash inside a title.`,
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		result, err := core.ResolveTitlePath(strings.Join(args, " "))
+		if err != nil {
+			var ambiguous *core.AmbiguousTitleError
+			if errors.As(err, &ambiguous) {
+				fmt.Fprintf(os.Stderr, "❌ Error: %q matches %d docs:\n", ambiguous.TitlePath, len(ambiguous.Matches))
+				for _, match := range ambiguous.Matches {
+					fmt.Fprintf(os.Stderr, "   %s (doclific/%s)\n", match.TitlePath, match.FilePath)
+				}
+				os.Exit(1)
+			}
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Println(filepath.ToSlash(filepath.Join("doclific", result.FilePath)))
+	},
+}
+
+var mvCmd = &cobra.Command{
+	Use:   "mv <doc>",
+	Short: "Move or reorder a doc",
+	Long:  `Move a doc under a new parent (--to) and/or position it before or after a sibling. Docs and parents may be given as folder paths, UUIDs, slug paths or title paths; siblings as folder names or titles. Use --to / to move a doc to the root.`,
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		before, _ := cmd.Flags().GetString("before")
+		after, _ := cmd.Flags().GetString("after")
+
+		var parent *string
+		if cmd.Flags().Changed("to") {
+			to, _ := cmd.Flags().GetString("to")
+			parent = &to
+		}
+
+		if err := core.MoveDoc(strings.Join(args, " "), parent, before, after); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Println("✅ Doc moved")
+	},
+}
+
+var lsCmd = &cobra.Command{
+	Use:   "ls [doc]",
+	Short: "List the doc tree",
+	Long:  `Print the doc tree in sidebar order with each doc's order, icon and folder name. Pass a folder path, UUID, slug path or title path to list only that doc's children.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		docs, err := core.GetDocs()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(args) > 0 {
+			filePath, err := core.ResolveDocRef(strings.Join(args, " "))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+				os.Exit(1)
+			}
+			if filePath != "" {
+				found := false
+				core.WalkDocs(docs, func(path string, doc core.FolderStructure) error {
+					if path == filePath {
+						docs = doc.Children
+						found = true
+					}
+					return nil
+				})
+				if !found {
+					fmt.Fprintf(os.Stderr, "❌ Error: doc not found: %s\n", filePath)
+					os.Exit(1)
+				}
+			}
+		}
+
+		if asJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(docs); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+
+		if len(docs) == 0 {
+			fmt.Println("No docs yet")
+			return
+		}
+		printDocTree(docs, "")
+	},
+}
+
+// printDocTree prints docs as an indented tree with their order, icon and folder name
+func printDocTree(docs []core.FolderStructure, prefix string) {
+	for i, doc := range docs {
+		branch, childPrefix := "├── ", "│   "
+		if i == len(docs)-1 {
+			branch, childPrefix = "└── ", "    "
+		}
+
+		icon := ""
+		if doc.Icon != nil && *doc.Icon != "" {
+			icon = fmt.Sprintf("  [%s]", *doc.Icon)
+		}
+		fmt.Printf("%s%s%d. %s%s  %s\n", prefix, branch, doc.Order, doc.Title, icon, doc.Name)
+		printDocTree(doc.Children, prefix+childPrefix)
+	}
+}
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the doc tree for problems and optionally repair them",
+	Long:  `Report docs with a missing or invalid config.json, docs without content.mdx, a content.mdx outside any doc, siblings sharing the same order, and folders not named by UUID. With --fix, rebuild configs (keeping invalid ones as config.json.bak), create missing content, move stray content into a new doc, renumber orders and rename folders to UUIDs, updating doc: links that used the old name.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		fix, _ := cmd.Flags().GetBool("fix")
+
+		fmt.Println("🔍 Checking the doc tree...")
+
+		problems, err := core.Diagnose()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(problems) == 0 {
+			fmt.Println("✅ No problems found")
+			return
+		}
+
+		var repairErr error
+		if fix {
+			repairErr = core.RepairDocs(problems)
+		}
+
+		for _, problem := range problems {
+			location := problem.FilePath
+			if location == "" {
+				location = "doclific/"
+			}
+			status := ""
+			if problem.Repaired {
+				status = " ✔ fixed"
+			}
+			fmt.Printf("   [%s] %s: %s%s\n", problem.Kind, location, problem.Message, status)
+		}
+
+		if repairErr != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", repairErr)
+			os.Exit(1)
+		}
+		if !fix {
+			fmt.Fprintf(os.Stderr, "❌ Found %d problem(s); run doclific doctor --fix to repair them\n", len(problems))
+			os.Exit(1)
+		}
+		fmt.Printf("✅ Repaired %d problem(s)\n", len(problems))
+	},
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"doclific/internal/core"
+	"doclific/internal/erd"
+
+	"github.com/spf13/cobra"
+)
+
+var erdCmd = &cobra.Command{
+	Use:   "erd",
+	Short: "Generate, sync and export ERD blocks",
+	Long:  `Generate <ERD> blocks for docs from database schema sources, keep them in sync with those sources and export them to other formats.`,
+}
+
+var erdFromSQLCmd = &cobra.Command{
+	Use:   "from-sql <files...>",
+	Short: "Generate an ERD from PostgreSQL DDL",
+	Long: `Read CREATE TABLE, ALTER TABLE, CREATE UNIQUE INDEX and DROP TABLE statements from SQL files and print an <ERD> block with the resulting tables and foreign key relationships, laid out automatically.
+
+Directories are searched for .sql files, which are applied in migration order; down migrations (*.down.sql, goose "-- +goose Down" and dbmate "-- migrate:down" sections) are skipped.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		schema, err := erd.ParseSQLFiles(args)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+		if len(schema.Tables) == 0 {
+			fmt.Fprintf(os.Stderr, "❌ Error: no CREATE TABLE statements found\n")
+			os.Exit(1)
+		}
+
+		printDiagram(erd.Build(schema), asJSON)
+	},
+}
+
+var erdFromGoCmd = &cobra.Command{
+	Use:   "from-go [packages...]",
+	Short: "Generate an ERD from GORM models or ent schemas",
+	Long: `Read Go structs carrying GORM tags (or embedding gorm.Model) and ent schemas, and print an <ERD> block with their tables, primary keys, nullability and relationships.
+
+Directories are read as a single package; end a path with /... to include subdirectories. Without arguments, every Go file in the repository that mentions GORM or ent is read.
+
+Foreign keys come from association fields (belongs-to, has-one, has-many and many2many tags), ent edges, and fields named after another model such as AuthorID. Pointer and sql.Null* fields are nullable.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		paths := args
+		if len(paths) == 0 {
+			files, err := erd.GoModelFiles()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+				os.Exit(1)
+			}
+			if len(files) == 0 {
+				fmt.Fprintf(os.Stderr, "❌ Error: no Go files using GORM or ent found\n")
+				os.Exit(1)
+			}
+			paths = files
+		}
+
+		schema, err := erd.ParseGoModels(paths)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+		if len(schema.Tables) == 0 {
+			fmt.Fprintf(os.Stderr, "❌ Error: no GORM models or ent schemas found\n")
+			os.Exit(1)
+		}
+
+		printDiagram(erd.Build(schema), asJSON)
+	},
+}
+
+var erdSyncCmd = &cobra.Command{
+	Use:   "sync [doc]",
+	Short: "Check ERD blocks against their schema source",
+	Long: `Regenerate every <ERD> block that declares a source attribute, such as <ERD source="db/migrations" ...> or <ERD source="internal/models" ...>, and report added, removed and changed tables, columns and relationships. Sources are comma-separated repository paths, read like from-sql when they hold .sql files and like from-go otherwise.
+
+With --apply, out-of-date blocks are rewritten in place. Tables that already exist keep their IDs and canvas positions; new tables are placed below them. Pass a folder path, UUID, slug path or title path to check a single doc.
+
+Exits with an error when a block is out of date and --apply is not given, so it can run in CI.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		apply, _ := cmd.Flags().GetBool("apply")
+
+		filePath := ""
+		if len(args) > 0 {
+			var err error
+			filePath, err = core.ResolveDocRef(strings.Join(args, " "))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		fmt.Println("🔍 Checking ERD blocks against their sources...")
+
+		results, err := erd.SyncDocs(filePath, apply)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		outdated, failed := 0, 0
+		for _, result := range results {
+			if result.Error != "" {
+				failed++
+				fmt.Printf("   ⚠️  %s (%s:%d) ← %s: %s\n", result.Title, result.FilePath, result.Line, result.Source, result.Error)
+				continue
+			}
+			if len(result.Changes) == 0 {
+				continue
+			}
+			outdated++
+			status := ""
+			if result.Applied {
+				status = " ✔ updated"
+			}
+			fmt.Printf("   %s (%s:%d) ← %s%s\n", result.Title, result.FilePath, result.Line, result.Source, status)
+			for _, change := range result.Changes {
+				fmt.Printf("      %s\n", change)
+			}
+		}
+
+		if len(results) == 0 {
+			fmt.Println("✅ No ERD blocks declare a source")
+			return
+		}
+		if failed > 0 {
+			fmt.Fprintf(os.Stderr, "❌ Could not read the source of %d ERD block(s)\n", failed)
+			os.Exit(1)
+		}
+		if outdated == 0 {
+			fmt.Printf("✅ All %d ERD block(s) match their sources\n", len(results))
+			return
+		}
+		if !apply {
+			fmt.Fprintf(os.Stderr, "❌ %d of %d ERD block(s) are out of date; run doclific erd sync --apply to update them\n", outdated, len(results))
+			os.Exit(1)
+		}
+		fmt.Printf("✅ Updated %d ERD block(s)\n", outdated)
+	},
+}
+
+var erdExportCmd = &cobra.Command{
+	Use:   "export <doc>",
+	Short: "Export an ERD block as SQL, Mermaid, DBML or DOT",
+	Long: `Print an <ERD> block of a doc in another format: PostgreSQL DDL (sql) to start a migration from a diagram drafted in a design doc, or a Mermaid erDiagram (mermaid), DBML (dbml) or Graphviz graph (dot) to render it in other tools.
+
+The doc may be given as a folder path, UUID, slug path or title path. --index picks the block when the doc has more than one.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		format, _ := cmd.Flags().GetString("format")
+		index, _ := cmd.Flags().GetInt("index")
+
+		filePath, err := core.ResolveDocRef(strings.Join(args, " "))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		diagram, err := erd.DocDiagram(filePath, index)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		output, err := diagram.Export(format)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Print(output)
+	},
+}
+
+// printDiagram prints a diagram as an <ERD> block (or JSON) on stdout and a summary on stderr,
+// keeping stdout clean for piping into a doc
+func printDiagram(diagram *erd.Diagram, asJSON bool) {
+	if asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(diagram)
+	} else {
+		mdx, err := diagram.MDX()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(mdx)
+	}
+
+	fmt.Fprintf(os.Stderr, "✅ Generated %d table(s) and %d relationship(s)\n", len(diagram.Tables), len(diagram.Relationships))
+}
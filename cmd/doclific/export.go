@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"doclific/internal/export"
+
+	"github.com/spf13/cobra"
+)
+
+var buildCmd = &cobra.Command{
+	Use:   "build",
+	Short: "Build a static HTML site from the docs",
+	Long:  `Render every doc to a self-contained static website with a navigation sidebar. CodebaseSnippets are resolved and inlined as highlighted code at build time.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		outDir, _ := cmd.Flags().GetString("out")
+		title, _ := cmd.Flags().GetString("title")
+
+		fmt.Printf("🔨 Building static site into %s...\n", outDir)
+
+		result, err := export.BuildSite(export.SiteOptions{OutDir: outDir, Title: title})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("✅ Built %d page(s) into %s\n", result.Pages, result.OutDir)
+	},
+}
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the docs to another format",
+	Long: `Export the docs to another format.
+
+With --format md, every doc is written as plain CommonMark into a directory mirroring the doc tree: CodebaseSnippets become fenced code blocks with a link to the file, ERDs become Mermaid erDiagrams and HttpRequests become curl examples.
+
+With --format print or --format pdf, a subtree of docs (--root, default all docs) is concatenated in sidebar order into one printable HTML document or PDF with a table of contents and a page break before each doc.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		format, _ := cmd.Flags().GetString("format")
+		out, _ := cmd.Flags().GetString("out")
+		root, _ := cmd.Flags().GetString("root")
+		title, _ := cmd.Flags().GetString("title")
+
+		switch format {
+		case "md", "markdown":
+			if out == "" {
+				out = "export"
+			}
+			fmt.Printf("📦 Exporting Markdown into %s...\n", out)
+
+			result, err := export.ExportMarkdown(export.MarkdownOptions{OutDir: out})
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			fmt.Printf("✅ Exported %d doc(s) into %s\n", result.Files, result.OutDir)
+		case "print", "pdf":
+			pdf := format == "pdf"
+			if out == "" {
+				out = "handbook.html"
+				if pdf {
+					out = "handbook.pdf"
+				}
+			}
+
+			fmt.Printf("📦 Exporting print bundle to %s...\n", out)
+
+			result, err := export.BuildPrintBundle(export.PrintOptions{Root: root, OutFile: out, Title: title, PDF: pdf})
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			fmt.Printf("✅ Exported %d doc(s) to %s\n", result.Docs, result.OutFile)
+		default:
+			fmt.Fprintf(os.Stderr, "❌ Error: unsupported format %q (supported: md, print, pdf)\n", format)
+			os.Exit(1)
+		}
+	},
+}
@@ -0,0 +1,408 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"doclific/internal/core"
+	"doclific/internal/httpreq"
+
+	"github.com/spf13/cobra"
+)
+
+var httpCmd = &cobra.Command{
+	Use:   "http",
+	Short: "Run, export and record HttpRequest blocks and manage their environments",
+	Long:  `Run HttpRequest blocks as API smoke tests, export them to other API tools, record them from real traffic and manage the environments they resolve {{variables}} against.`,
+}
+
+var httpRunCmd = &cobra.Command{
+	Use:   "run [doc]",
+	Short: "Run HttpRequest blocks as API tests",
+	Long: `Send every <HttpRequest> block and check its assertions. Pass a folder path, UUID, slug path or title path to run a single doc and the docs nested under it.
+
+Assertions are optional attributes of the block:
+  expectStatus="200"                         codes or classes, such as "2xx" or "200,204"
+  expectJson='{"$.user.name": "Ada"}'        JSONPath expressions and the JSON value each must equal
+
+Without expectStatus, any status below 400 passes. Paths support .name, ['name'], [index] (negative counts from the end) and .length.
+
+Variables are resolved against --env, or the active environment when it is not given. Unlike the editor, the CLI is not limited to HTTP_ALLOWED_HOSTS. Exits with an error when any block fails, so it can run in CI.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		envName, _ := cmd.Flags().GetString("env")
+		junit, _ := cmd.Flags().GetString("junit")
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+
+		filePath := ""
+		if len(args) > 0 {
+			var err error
+			filePath, err = core.ResolveDocRef(strings.Join(args, " "))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		var variables map[string]string
+		var err error
+		if envName != "" {
+			variables, err = httpreq.EnvironmentVariables(envName)
+		} else {
+			variables, err = httpreq.ActiveVariables()
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		// With the report on stdout, progress goes to stderr so the XML can be redirected
+		out := os.Stdout
+		if junit == "-" {
+			out = os.Stderr
+		}
+		fmt.Fprintln(out, "🚀 Running HttpRequest blocks...")
+
+		executor := &httpreq.Executor{AllowedHosts: []string{"*"}, Timeout: timeout, Variables: variables}
+		results, err := httpreq.RunDocs(context.Background(), filePath, executor)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		passed, failed, skipped := 0, 0, 0
+		for _, result := range results {
+			location := fmt.Sprintf("%s (%s:%d)", result.Name, result.FilePath, result.Line)
+			switch {
+			case result.Skipped != "":
+				skipped++
+				fmt.Fprintf(out, "   ⏭️  %s: skipped, %s\n", location, result.Skipped)
+			case result.Error != "":
+				failed++
+				fmt.Fprintf(out, "   ⚠️  %s: %s\n", location, result.Error)
+			case len(result.Failures) > 0:
+				failed++
+				fmt.Fprintf(out, "   ❌ %s → %d\n", location, result.Status)
+				for _, failure := range result.Failures {
+					fmt.Fprintf(out, "      %s\n", failure)
+				}
+			default:
+				passed++
+				fmt.Fprintf(out, "   ✔ %s → %d in %dms\n", location, result.Status, result.Duration.Milliseconds())
+			}
+		}
+
+		if junit != "" {
+			report, err := httpreq.JUnitReport(results)
+			if err == nil {
+				if junit == "-" {
+					_, err = os.Stdout.Write(report)
+				} else {
+					err = os.WriteFile(junit, report, 0644)
+				}
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		if len(results) == 0 {
+			fmt.Fprintln(out, "✅ No HttpRequest blocks found")
+			return
+		}
+		if failed > 0 {
+			fmt.Fprintf(os.Stderr, "❌ %d of %d requests failed\n", failed, len(results))
+			os.Exit(1)
+		}
+		fmt.Fprintf(out, "✅ %d passed, %d skipped\n", passed, skipped)
+	},
+}
+
+var httpExportCmd = &cobra.Command{
+	Use:   "export [doc]",
+	Short: "Export HttpRequest blocks as a Postman or Insomnia collection or a curl script",
+	Long: `Collect every <HttpRequest> block, grouped in folders by doc hierarchy, so the documented requests can be reused in other tools. Pass a folder path, UUID, slug path or title path to export a single doc and the docs nested under it.
+
+Formats:
+  postman    Postman collection v2.1
+  insomnia   Insomnia export (v4)
+  curl       shell script with a curl command per request
+
+Auth settings are kept as the collection's auth rather than headers. The variables of --env, or of the active environment, become collection variables (for curl, shell variables that can be overridden when running the script). Insomnia exports also get a sub-environment per environment. Any other {{variable}} the requests use is exported empty, and secrets are exported by name only.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		format, _ := cmd.Flags().GetString("format")
+		out, _ := cmd.Flags().GetString("out")
+		envName, _ := cmd.Flags().GetString("env")
+
+		filePath := ""
+		if len(args) > 0 {
+			var err error
+			filePath, err = core.ResolveDocRef(strings.Join(args, " "))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		collection, err := httpreq.LoadCollection(filePath, envName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		output, err := collection.Export(format)
+		if err == nil {
+			if out == "" {
+				_, err = fmt.Print(output)
+			} else {
+				err = os.WriteFile(out, []byte(output), 0644)
+			}
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		requests := 0
+		collection.WalkRequests(func(httpreq.CollectionRequest) { requests++ })
+		// The summary goes to stderr so stdout can be redirected to a file
+		fmt.Fprintf(os.Stderr, "✅ Exported %d request(s) and %d variable(s) as %s\n", requests, len(collection.Variables), format)
+	},
+}
+
+var httpRecordCmd = &cobra.Command{
+	Use:   "record",
+	Short: "Record API traffic through a proxy to document it",
+	Long: `Run a reverse proxy on --listen that forwards every request to --target and records the exchange. Point your app or browser at the proxy, click through the flows to document, then stop with Ctrl+C.
+
+Pick the exchanges to keep with doclific http record insert <doc> --pick 1,3-5, which appends them to the doc as <HttpRequest> blocks with the recorded response as an example. Use doclific http record list to see the last recording again.
+
+Recordings hold real credentials, so they are kept in your user config directory (~/.config/doclific/recording.jsonl) unless --file is given. Each recording replaces the previous one.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		listen, _ := cmd.Flags().GetString("listen")
+		target, _ := cmd.Flags().GetString("target")
+		file := recordingFile(cmd)
+
+		if target == "" {
+			fmt.Fprintln(os.Stderr, "❌ Error: --target is required")
+			os.Exit(1)
+		}
+
+		recorder, err := httpreq.NewRecorder(target, file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+		exchanges := 0
+		recorder.OnExchange = func(exchange httpreq.Exchange) {
+			exchanges++
+			fmt.Printf("   %s\n", exchange.Summary())
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		server := &http.Server{Addr: listen, Handler: recorder}
+		serveErr := make(chan error, 1)
+		go func() { serveErr <- server.ListenAndServe() }()
+
+		fmt.Printf("🎙️  Recording %s through http://%s (Ctrl+C to stop)\n", target, displayAddr(listen))
+		select {
+		case err := <-serveErr:
+			recorder.Close()
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			os.Exit(1)
+		case <-ctx.Done():
+		}
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+		if err := recorder.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("\n✅ Recorded %d exchange(s) to %s\n", exchanges, file)
+		if exchanges > 0 {
+			fmt.Println("   Insert them into a doc with doclific http record insert <doc> --pick 1,3-5")
+		}
+	},
+}
+
+var httpRecordListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the exchanges of the last recording",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		exchanges, err := httpreq.ReadRecording(recordingFile(cmd))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+		if len(exchanges) == 0 {
+			fmt.Println("No exchanges recorded")
+			return
+		}
+		for _, exchange := range exchanges {
+			fmt.Println(exchange.Summary())
+		}
+	},
+}
+
+var httpRecordInsertCmd = &cobra.Command{
+	Use:   "insert <doc>",
+	Short: "Insert recorded exchanges into a doc as HttpRequest blocks",
+	Long: `Append exchanges of the last recording to the end of a doc, each under a "## METHOD /path" heading as an <HttpRequest> block. The recorded response is kept as the block's example response and its status as expectStatus.
+
+The doc may be given as a folder path, UUID, slug path or title path. --pick selects exchanges by the numbers shown while recording, such as 1,3-5; without it, every exchange that got a response is inserted.
+
+Bearer and basic credentials become {{token}} or {{username}}/{{password}} variables and cookies are dropped, so they are not written to the docs. Use --base-url "{{baseUrl}}" to replace the recorded origin with an environment variable.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		pick, _ := cmd.Flags().GetString("pick")
+		baseURL, _ := cmd.Flags().GetString("base-url")
+
+		filePath, err := core.ResolveDocRef(strings.Join(args, " "))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		exchanges, err := httpreq.ReadRecording(recordingFile(cmd))
+		if err == nil {
+			exchanges, err = httpreq.SelectExchanges(exchanges, pick)
+		}
+		if err == nil && len(exchanges) == 0 {
+			err = fmt.Errorf("no recorded exchanges to insert")
+		}
+		if err == nil {
+			err = httpreq.InsertExchanges(filePath, exchanges, baseURL)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		for _, exchange := range exchanges {
+			fmt.Printf("   %s\n", exchange.Summary())
+		}
+		fmt.Printf("✅ Inserted %d request(s) into %s\n", len(exchanges), filePath)
+	},
+}
+
+// recordingFile returns the --file flag, or the default recording in the user config dir
+func recordingFile(cmd *cobra.Command) string {
+	file, _ := cmd.Flags().GetString("file")
+	if file != "" {
+		return file
+	}
+	file, err := httpreq.DefaultRecordingPath()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+	return file
+}
+
+// displayAddr turns a listen address such as :8089 into one a browser can open
+func displayAddr(addr string) string {
+	if strings.HasPrefix(addr, ":") {
+		return "localhost" + addr
+	}
+	return addr
+}
+
+var httpEnvCmd = &cobra.Command{
+	Use:   "env",
+	Short: "List environments",
+	Long: `List the environments HttpRequest blocks can resolve {{variables}} against, marking the active one.
+
+Shared variables live in doclific/.environments/<name>.json and are committed with the docs. Secrets such as tokens are stored per project in ~/.config/doclific/environments.json and are never written to the repository.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		environments, err := httpreq.ListEnvironments()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+		if len(environments.Environments) == 0 {
+			fmt.Printf("No environments found; add one in doclific/%s/<name>.json\n", httpreq.EnvironmentsDirName)
+			return
+		}
+
+		for _, environment := range environments.Environments {
+			marker := " "
+			if environment.Name == environments.Active {
+				marker = "*"
+			}
+			fmt.Printf("%s %s\n", marker, environment.Name)
+
+			keys := make([]string, 0, len(environment.Variables))
+			for key := range environment.Variables {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				fmt.Printf("    %s = %s\n", key, environment.Variables[key])
+			}
+			for _, key := range environment.Secrets {
+				fmt.Printf("    %s = ******** (secret)\n", key)
+			}
+		}
+	},
+}
+
+var httpEnvUseCmd = &cobra.Command{
+	Use:   "use [name]",
+	Short: "Select the active environment",
+	Long:  `Select the environment HttpRequest blocks resolve against. Without a name, the selection is cleared and requests using {{variables}} will fail.`,
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := ""
+		if len(args) == 1 {
+			name = args[0]
+		}
+
+		if err := httpreq.SetActiveEnvironment(name); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+		if name == "" {
+			fmt.Println("✅ Cleared the active environment")
+			return
+		}
+		fmt.Printf("✅ Using environment %s\n", name)
+	},
+}
+
+var httpEnvSecretCmd = &cobra.Command{
+	Use:   "secret <environment> <name> [value]",
+	Short: "Set or remove a secret variable",
+	Long:  `Store a secret variable, such as an API token, for an environment in your user config directory. Secrets override shared variables with the same name. Without a value, the secret is removed.`,
+	Args:  cobra.RangeArgs(2, 3),
+	Run: func(cmd *cobra.Command, args []string) {
+		value := ""
+		if len(args) == 3 {
+			value = args[2]
+		}
+
+		if err := httpreq.SetSecret(args[0], args[1], value); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+		if value == "" {
+			fmt.Printf("✅ Removed secret %s from %s\n", args[1], args[0])
+			return
+		}
+		fmt.Printf("✅ Set secret %s for %s\n", args[1], args[0])
+	},
+}
@@ -6,6 +6,7 @@ import (
 
 	"doclific/internal/config"
 	"doclific/internal/core"
+	"doclific/internal/httpreq"
 	"doclific/internal/server"
 
 	"github.com/spf13/cobra"
@@ -59,7 +60,7 @@ var initCmd = &cobra.Command{
 
 var getCmd = &cobra.Command{
 	Use:   "get [key]",
-	Short: "Get configuration value (DEEPLINK_PREFIX)",
+	Short: "Get configuration value (DEEPLINK_PREFIX, HTTP_ALLOWED_HOSTS)",
 	Long:  `Get a configuration value from the Doclific config.`,
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
@@ -147,12 +148,73 @@ var updateCmd = &cobra.Command{
 
 func init() {
 	rootCmd.Flags().IntP("port", "p", 6767, "port to listen on")
+	buildCmd.Flags().StringP("out", "o", "dist", "output directory")
+	buildCmd.Flags().String("title", "", "site title (defaults to the repository name)")
+	exportCmd.Flags().StringP("format", "f", "md", "export format (md, print, pdf)")
+	exportCmd.Flags().StringP("out", "o", "", "output directory for md, or output file for print and pdf (default: export, handbook.html or handbook.pdf)")
+	exportCmd.Flags().String("root", "", "folder or slug path of the subtree to include in a print bundle (default: all docs)")
+	exportCmd.Flags().String("title", "", "print bundle title (defaults to the root doc's title or the repository name)")
+	importCmd.Flags().String("parent", "", "folder path of the doc to import under (default: the root)")
+	importCmd.Flags().Bool("snippets", false, "turn fenced code blocks that match repository files verbatim into CodebaseSnippets")
+	importOpenAPICmd.Flags().String("parent", "", "folder path of the doc to import under (default: the root)")
+	importOpenAPICmd.Flags().String("per", "tag", "page layout: tag (a page per tag) or operation (a page per operation)")
+	importOpenAPICmd.Flags().String("base-url", "", "prefix for request URLs, such as {{baseUrl}} (default: the spec's first server)")
+	newCmd.Flags().String("parent", "", "parent doc as a folder path, UUID, slug path or title path (default: the root)")
+	newCmd.Flags().String("title", "", "title of the new doc")
+	newCmd.Flags().String("icon", "", "icon name from all-icons.json")
+	newCmd.Flags().String("template", "", "template to create the doc from")
+	mvCmd.Flags().String("to", "", "new parent as a folder path, UUID, slug path or title path (/ for the root)")
+	mvCmd.Flags().String("before", "", "sibling to place the doc before (folder name or title)")
+	mvCmd.Flags().String("after", "", "sibling to place the doc after (folder name or title)")
+	lsCmd.Flags().Bool("json", false, "print the tree as JSON")
+	doctorCmd.Flags().Bool("fix", false, "repair the problems found")
+	erdFromSQLCmd.Flags().Bool("json", false, "print the tables and relationships as JSON instead of an <ERD> block")
+	erdFromGoCmd.Flags().Bool("json", false, "print the tables and relationships as JSON instead of an <ERD> block")
+	erdSyncCmd.Flags().Bool("apply", false, "rewrite out-of-date ERD blocks")
+	erdExportCmd.Flags().StringP("format", "f", "sql", "export format (sql, mermaid, dbml, dot)")
+	erdExportCmd.Flags().Int("index", 0, "which ERD block of the doc to export, counting from 0")
+	httpRunCmd.Flags().StringP("env", "e", "", "environment to resolve variables against (defaults to the active environment)")
+	httpRunCmd.Flags().String("junit", "", "write a JUnit XML report to this file (- for stdout)")
+	httpRunCmd.Flags().Duration("timeout", httpreq.DefaultTimeout, "timeout for each request")
+	httpExportCmd.Flags().StringP("format", "f", "postman", "export format (postman, insomnia, curl)")
+	httpExportCmd.Flags().StringP("out", "o", "", "file to write the export to (default: stdout)")
+	httpExportCmd.Flags().StringP("env", "e", "", "environment to export variables from (defaults to the active environment)")
+	httpRecordCmd.Flags().String("listen", ":8089", "address for the recording proxy to listen on")
+	httpRecordCmd.Flags().String("target", "", "URL of the API to forward requests to, such as http://localhost:3000 (required)")
+	httpRecordCmd.PersistentFlags().String("file", "", "recording file (default: recording.jsonl in the user config directory)")
+	httpRecordInsertCmd.Flags().String("pick", "", "exchanges to insert, such as 1,3-5 (default: every exchange with a response)")
+	httpRecordInsertCmd.Flags().String("base-url", "", "replace the recorded origin, such as {{baseUrl}}")
 	// Add commands to root
 	rootCmd.AddCommand(initCmd)
 	rootCmd.AddCommand(getCmd)
 	rootCmd.AddCommand(setCmd)
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(updateCmd)
+	rootCmd.AddCommand(lintCmd)
+	rootCmd.AddCommand(staleCmd)
+	rootCmd.AddCommand(buildCmd)
+	rootCmd.AddCommand(exportCmd)
+	importCmd.AddCommand(importOpenAPICmd)
+	rootCmd.AddCommand(importCmd)
+	rootCmd.AddCommand(newCmd)
+	rootCmd.AddCommand(pathCmd)
+	rootCmd.AddCommand(mvCmd)
+	rootCmd.AddCommand(lsCmd)
+	rootCmd.AddCommand(doctorCmd)
+	erdCmd.AddCommand(erdFromSQLCmd)
+	erdCmd.AddCommand(erdFromGoCmd)
+	erdCmd.AddCommand(erdSyncCmd)
+	erdCmd.AddCommand(erdExportCmd)
+	rootCmd.AddCommand(erdCmd)
+	httpCmd.AddCommand(httpRunCmd)
+	httpCmd.AddCommand(httpExportCmd)
+	httpRecordCmd.AddCommand(httpRecordListCmd)
+	httpRecordCmd.AddCommand(httpRecordInsertCmd)
+	httpCmd.AddCommand(httpRecordCmd)
+	httpEnvCmd.AddCommand(httpEnvUseCmd)
+	httpEnvCmd.AddCommand(httpEnvSecretCmd)
+	httpCmd.AddCommand(httpEnvCmd)
+	rootCmd.AddCommand(httpCmd)
 }
 
 // maskAPIKey masks an API key for display (shows first 4 and last 4 characters)
@@ -3,11 +3,16 @@ module doclific
 go 1.25
 
 require (
+	github.com/alecthomas/chroma/v2 v2.27.0
+	github.com/awalterschulze/gographviz v2.0.3+incompatible
+	github.com/go-pdf/fpdf v0.9.0
 	github.com/google/generative-ai-go v0.20.1
 	github.com/google/uuid v1.6.0
 	github.com/sabhiram/go-gitignore v0.0.0-20210923224102-525f6e181f06
 	github.com/spf13/cobra v1.8.1
+	github.com/yuin/goldmark v1.8.6
 	google.golang.org/api v0.259.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -18,7 +23,7 @@ require (
 	cloud.google.com/go/compute/metadata v0.9.0 // indirect
 	cloud.google.com/go/longrunning v0.7.0 // indirect
 	github.com/a2aproject/a2a-go v0.3.3 // indirect
-	github.com/awalterschulze/gographviz v2.0.3+incompatible // indirect
+	github.com/dlclark/regexp2/v2 v2.2.1 // indirect
 	github.com/felixge/httpsnoop v1.0.4 // indirect
 	github.com/go-logr/logr v1.4.3 // indirect
 	github.com/go-logr/stdr v1.2.2 // indirect
@@ -14,7 +14,8 @@ const (
 
 // Config represents the application configuration
 type Config struct {
-	DeeplinkPrefix string `json:"DEEPLINK_PREFIX,omitempty"`
+	DeeplinkPrefix   string `json:"DEEPLINK_PREFIX,omitempty"`
+	HTTPAllowedHosts string `json:"HTTP_ALLOWED_HOSTS,omitempty"` // comma-separated hosts the HttpRequest proxy may call
 }
 
 // GetConfigDir returns the configuration directory path (~/.config/doclific)
@@ -35,6 +36,54 @@ func GetConfigPath() (string, error) {
 	return filepath.Join(configDir, ConfigFileName), nil
 }
 
+// ReadUserFile reads a file from the config directory, returning nil data if it does not exist
+func ReadUserFile(name string) ([]byte, error) {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(configDir, name))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	return data, nil
+}
+
+// WriteUserFile writes a file to the config directory through a temporary file renamed into place,
+// so the file always ends up with perm even if it existed before with looser permissions
+func WriteUserFile(name string, data []byte, perm os.FileMode) error {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	// CreateTemp opens the file as 0600, so its contents are never readable by others
+	tmp, err := os.CreateTemp(configDir, "."+name+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	tmpPath := tmp.Name()
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Chmod(perm)
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, filepath.Join(configDir, name))
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
 // LoadConfig loads configuration from the config file, merging with environment variables
 func LoadConfig() (*Config, error) {
 	configPath, err := GetConfigPath()
@@ -60,33 +109,21 @@ func LoadConfig() (*Config, error) {
 	if envVal := os.Getenv("DEEPLINK_PREFIX"); envVal != "" {
 		cfg.DeeplinkPrefix = envVal
 	}
+	if envVal := os.Getenv("HTTP_ALLOWED_HOSTS"); envVal != "" {
+		cfg.HTTPAllowedHosts = envVal
+	}
 
 	return cfg, nil
 }
 
 // SaveConfig saves the configuration to the config file
 func SaveConfig(cfg *Config) error {
-	configPath, err := GetConfigPath()
-	if err != nil {
-		return err
-	}
-
-	// Create config directory if it doesn't exist
-	configDir := filepath.Dir(configPath)
-	if err := os.MkdirAll(configDir, 0755); err != nil {
-		return fmt.Errorf("failed to create config directory: %w", err)
-	}
-
 	data, err := json.MarshalIndent(cfg, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
 
-	if err := os.WriteFile(configPath, data, 0644); err != nil {
-		return fmt.Errorf("failed to write config file: %w", err)
-	}
-
-	return nil
+	return WriteUserFile(ConfigFileName, data, 0644)
 }
 
 // GetConfigValue returns a configuration value by key
@@ -99,6 +136,8 @@ func GetConfigValue(key string) (string, error) {
 	switch key {
 	case "DEEPLINK_PREFIX":
 		return cfg.DeeplinkPrefix, nil
+	case "HTTP_ALLOWED_HOSTS":
+		return cfg.HTTPAllowedHosts, nil
 	default:
 		return "", fmt.Errorf("unknown config key: %s", key)
 	}
@@ -115,6 +154,8 @@ func SetConfigValue(key, value string) error {
 	switch key {
 	case "DEEPLINK_PREFIX":
 		cfg.DeeplinkPrefix = value
+	case "HTTP_ALLOWED_HOSTS":
+		cfg.HTTPAllowedHosts = value
 	default:
 		return fmt.Errorf("unknown config key: %s", key)
 	}
@@ -180,3 +180,45 @@ func TestSaveConfig(t *testing.T) {
 		t.Errorf("SaveConfig() did not create config file at %q", configPath)
 	}
 }
+
+func TestWriteUserFile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	if data, err := ReadUserFile("secrets.json"); err != nil || data != nil {
+		t.Fatalf("ReadUserFile() of a missing file = %q, %v, want nil data and no error", data, err)
+	}
+
+	configDir, err := GetConfigDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(configDir, "secrets.json")
+	if err := os.WriteFile(path, []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := WriteUserFile("secrets.json", []byte("new"), 0600); err != nil {
+		t.Fatalf("WriteUserFile() error = %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("file mode = %v, want 0600", info.Mode().Perm())
+	}
+	if data, err := ReadUserFile("secrets.json"); err != nil || string(data) != "new" {
+		t.Errorf("ReadUserFile() = %q, %v, want new", data, err)
+	}
+
+	entries, err := os.ReadDir(configDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("config directory has %d entries, want only the written file", len(entries))
+	}
+}
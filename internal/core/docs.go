@@ -4,30 +4,75 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"sort"
+	"strings"
 
 	"github.com/google/uuid"
 )
 
 // FolderStructure represents a folder in the documentation structure
 type FolderStructure struct {
-	Name     string            `json:"name"`
-	Title    string            `json:"title"`
-	Icon     *string           `json:"icon,omitempty"`
-	Order    int               `json:"order"`
+	Name  string  `json:"name"`
+	Title string  `json:"title"`
+	Icon  *string `json:"icon,omitempty"`
+	Order int     `json:"order"`
+	Slug  string  `json:"slug,omitempty"`
+	DocMetadata
 	Children []FolderStructure `json:"children"`
 }
 
 // Config represents the config.json file structure
 type Config struct {
-	Title string  `json:"title"`
-	Icon  *string `json:"icon,omitempty"`
-	Order int     `json:"order"`
+	Title         string   `json:"title"`
+	Icon          *string  `json:"icon,omitempty"`
+	Order         int      `json:"order"`
+	Slug          string   `json:"slug,omitempty"`
+	PreviousSlugs []string `json:"previousSlugs,omitempty"` // kept so links using an old slug still resolve
+	DocMetadata
+}
+
+// readConfig reads and parses the config.json of a doc folder
+func readConfig(dirPath string) (*Config, error) {
+	configFile, err := os.ReadFile(filepath.Join(dirPath, "config.json"))
+	if err != nil {
+		return nil, fmt.Errorf("config file not found for %s: %w", filepath.Base(dirPath), err)
+	}
+
+	var config Config
+	if err := json.Unmarshal(configFile, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse config.json for %s: %w", filepath.Base(dirPath), err)
+	}
+
+	return &config, nil
 }
 
-// scanDirectory recursively scans a directory and builds the folder structure
-func scanDirectory(dirPath string) ([]FolderStructure, error) {
+// writeConfig atomically writes config.json for a doc folder
+// Every config write goes through here so a crash can never leave a truncated config
+func writeConfig(dirPath string, config *Config) error {
+	configJSON, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	if err := writeFileAtomic(filepath.Join(dirPath, "config.json"), configJSON, 0644); err != nil {
+		return fmt.Errorf("failed to write config.json for %s: %w", filepath.Base(dirPath), err)
+	}
+
+	return nil
+}
+
+// isDocDir reports whether a directory entry is a doc folder
+// Dot-directories such as .templates hold doclific metadata rather than docs
+func isDocDir(entry os.DirEntry) bool {
+	return entry.IsDir() && !strings.HasPrefix(entry.Name(), ".")
+}
+
+// scanDirectory reads the doc tree under dirPath, sorted by order
+// Folders with a missing or unreadable config.json are left out of the tree and reported in
+// problems instead of failing the whole scan, along with the docs nested under them
+func scanDirectory(dirPath string, relPath string, problems *[]DocProblem) ([]FolderStructure, error) {
 	entries, err := os.ReadDir(dirPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read directory %s: %w", dirPath, err)
@@ -36,47 +81,39 @@ func scanDirectory(dirPath string) ([]FolderStructure, error) {
 	folders := []FolderStructure{}
 
 	for _, entry := range entries {
-		if entry.IsDir() {
+		if isDocDir(entry) {
 			fullPath := filepath.Join(dirPath, entry.Name())
-			children, err := scanDirectory(fullPath)
-			if err != nil {
-				return nil, err
-			}
-
-			// Default config
-			config := Config{
-				Title: entry.Name(),
-				Icon:  nil,
+			filePath := entry.Name()
+			if relPath != "" {
+				filePath = relPath + "/" + entry.Name()
 			}
 
-			// Try to read config.json file
-			configPath := filepath.Join(fullPath, "config.json")
-			configFile, err := os.ReadFile(configPath)
+			config, err := readConfig(fullPath)
 			if err != nil {
-				return nil, fmt.Errorf("config file not found for %s: %w", entry.Name(), err)
-			}
-
-			if err := json.Unmarshal(configFile, &config); err != nil {
-				return nil, fmt.Errorf("failed to parse config.json for %s: %w", entry.Name(), err)
+				*problems = append(*problems, configProblem(filePath, err))
+				hiddenDocs(fullPath, filePath, problems)
+				continue
 			}
 
-			// Ensure children is always an empty array, not nil
-			if children == nil {
-				children = []FolderStructure{}
+			children, err := scanDirectory(fullPath, filePath, problems)
+			if err != nil {
+				return nil, err
 			}
 
 			folders = append(folders, FolderStructure{
-				Name:     entry.Name(),
-				Title:    config.Title,
-				Icon:     config.Icon,
-				Order:    config.Order,
-				Children: children,
+				Name:        entry.Name(),
+				Title:       config.Title,
+				Icon:        config.Icon,
+				Order:       config.Order,
+				Slug:        config.Slug,
+				DocMetadata: config.DocMetadata,
+				Children:    children,
 			})
 		}
 	}
 
 	// Sort folders by order
-	sort.Slice(folders, func(i, j int) bool {
+	sort.SliceStable(folders, func(i, j int) bool {
 		return folders[i].Order < folders[j].Order
 	})
 
@@ -84,21 +121,55 @@ func scanDirectory(dirPath string) ([]FolderStructure, error) {
 }
 
 // GetDocs scans the doclific folder and returns the folder structure
+// Docs with broken configs are skipped; use GetDocsWithProblems to see them
 func GetDocs() ([]FolderStructure, error) {
+	docs, _, err := GetDocsWithProblems()
+	return docs, err
+}
+
+// GetDocsWithProblems scans the doclific folder and returns the valid tree plus the folders that were skipped
+func GetDocsWithProblems() ([]FolderStructure, []DocProblem, error) {
 	// Get current working directory
 	cwd, err := os.Getwd()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get current working directory: %w", err)
+		return nil, nil, fmt.Errorf("failed to get current working directory: %w", err)
 	}
 
 	docsFolder := filepath.Join(cwd, "doclific")
 
 	// Check if doclific folder exists
 	if _, err := os.Stat(docsFolder); os.IsNotExist(err) {
-		return []FolderStructure{}, nil
+		return []FolderStructure{}, []DocProblem{}, nil
+	}
+
+	problems := []DocProblem{}
+	docs, err := scanDirectory(docsFolder, "", &problems)
+	if err != nil {
+		return nil, nil, err
 	}
+	return docs, problems, nil
+}
 
-	return scanDirectory(docsFolder)
+// WalkDocs calls fn for every doc in the tree in sidebar order, parents before children
+// filePath is the doc's folder path relative to doclific, using forward slashes
+func WalkDocs(docs []FolderStructure, fn func(filePath string, doc FolderStructure) error) error {
+	return walkDocs(docs, "", fn)
+}
+
+func walkDocs(docs []FolderStructure, parentPath string, fn func(filePath string, doc FolderStructure) error) error {
+	for _, doc := range docs {
+		filePath := doc.Name
+		if parentPath != "" {
+			filePath = parentPath + "/" + doc.Name
+		}
+		if err := fn(filePath, doc); err != nil {
+			return err
+		}
+		if err := walkDocs(doc.Children, filePath, fn); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // CreateDocResponse represents the response from CreateDoc
@@ -145,7 +216,7 @@ func UpdateDoc(filePath string, content string) error {
 	}
 
 	contentPath := filepath.Join(fullPath, "content.mdx")
-	if err := os.WriteFile(contentPath, []byte(content), 0644); err != nil {
+	if err := writeFileAtomic(contentPath, []byte(content), 0644); err != nil {
 		return fmt.Errorf("failed to write content.mdx: %w", err)
 	}
 
@@ -153,24 +224,44 @@ func UpdateDoc(filePath string, content string) error {
 }
 
 // CreateDoc creates a new documentation folder with a UUID name
-func CreateDoc(filePath string, title string, icon *string) (*CreateDocResponse, error) {
+// If templateName is set, content.mdx is rendered from that template instead of a bare heading
+func CreateDoc(filePath string, title string, icon *string, templateName string) (*CreateDocResponse, error) {
 	fullPath, err := getDoclificPath(filePath)
 	if err != nil {
 		return nil, err
 	}
 
+	content := fmt.Sprintf("# %s\n", title)
+	if templateName != "" {
+		tmpl, err := loadTemplate(templateName)
+		if err != nil {
+			return nil, err
+		}
+		content = renderTemplate(tmpl.Content, templateVariables(title))
+		// Fall back to the template's icon when none was chosen
+		if icon == nil {
+			icon = tmpl.Icon
+		}
+	}
+
 	// Generate a new UUID for the folder name
 	newFolderName := uuid.New().String()
 	newFolderPath := filepath.Join(fullPath, newFolderName)
 
-	// Create the directory (recursive)
-	if err := os.MkdirAll(newFolderPath, 0755); err != nil {
+	// Build the doc in a hidden staging folder and rename it into place, so a crash never
+	// leaves a doc folder without its config.json
+	if err := os.MkdirAll(fullPath, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+	stagingPath := filepath.Join(fullPath, "."+newFolderName+".tmp")
+	if err := os.Mkdir(stagingPath, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create directory: %w", err)
 	}
 
 	// Create content.mdx file
-	contentPath := filepath.Join(newFolderPath, "content.mdx")
-	if err := os.WriteFile(contentPath, []byte(fmt.Sprintf("# %s\n", title)), 0644); err != nil {
+	contentPath := filepath.Join(stagingPath, "content.mdx")
+	if err := writeFileAtomic(contentPath, []byte(content), 0644); err != nil {
+		os.RemoveAll(stagingPath)
 		return nil, fmt.Errorf("failed to create content.mdx: %w", err)
 	}
 
@@ -179,14 +270,14 @@ func CreateDoc(filePath string, title string, icon *string) (*CreateDocResponse,
 		Title: title,
 		Icon:  icon,
 	}
-	configJSON, err := json.MarshalIndent(config, "", "  ")
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	if err := writeConfig(stagingPath, &config); err != nil {
+		os.RemoveAll(stagingPath)
+		return nil, err
 	}
 
-	configPath := filepath.Join(newFolderPath, "config.json")
-	if err := os.WriteFile(configPath, configJSON, 0644); err != nil {
-		return nil, fmt.Errorf("failed to create config.json: %w", err)
+	if err := os.Rename(stagingPath, newFolderPath); err != nil {
+		os.RemoveAll(stagingPath)
+		return nil, fmt.Errorf("failed to create directory: %w", err)
 	}
 
 	// Build the URL path (normalize path separators for URL - use forward slashes)
@@ -235,7 +326,7 @@ func findDocParentPath(dirPath string, name string, relativePath string) (string
 	}
 
 	for _, entry := range entries {
-		if entry.IsDir() {
+		if isDocDir(entry) {
 			if entry.Name() == name {
 				// Found it - return the parent's relative path
 				return relativePath, true, nil
@@ -260,111 +351,92 @@ func findDocParentPath(dirPath string, name string, relativePath string) (string
 	return "", false, nil // Not found at this level
 }
 
-// normalizeOrdersInDir reads all subdirectories, sorts them by current order, and renumbers them 0, 1, 2, ...
-func normalizeOrdersInDir(dirPath string) error {
+// readSiblingOrders reads the config of every doc folder in dirPath except skip, sorted by current order
+// Unlike scanning, a missing or invalid config is an error, since renumbering without it would
+// silently give two docs the same order
+func readSiblingOrders(dirPath string, skip string) ([]string, map[string]*Config, error) {
 	entries, err := os.ReadDir(dirPath)
 	if err != nil {
-		return fmt.Errorf("failed to read directory %s: %w", dirPath, err)
-	}
-
-	// Collect all subdirectories with their current order
-	type dirOrder struct {
-		name  string
-		order int
+		return nil, nil, fmt.Errorf("failed to read directory %s: %w", dirPath, err)
 	}
-	var dirs []dirOrder
 
+	var names []string
+	configs := map[string]*Config{}
 	for _, entry := range entries {
-		if entry.IsDir() {
-			configPath := filepath.Join(dirPath, entry.Name(), "config.json")
-			configFile, err := os.ReadFile(configPath)
-			if err != nil {
-				continue // Skip if no config
-			}
-			var config Config
-			if err := json.Unmarshal(configFile, &config); err != nil {
-				continue
-			}
-			dirs = append(dirs, dirOrder{name: entry.Name(), order: config.Order})
+		if !isDocDir(entry) || entry.Name() == skip {
+			continue
 		}
+		config, err := readConfig(filepath.Join(dirPath, entry.Name()))
+		if err != nil {
+			return nil, nil, err
+		}
+		names = append(names, entry.Name())
+		configs[entry.Name()] = config
 	}
 
-	// Sort by current order
-	sort.Slice(dirs, func(i, j int) bool {
-		return dirs[i].order < dirs[j].order
+	sort.SliceStable(names, func(i, j int) bool {
+		return configs[names[i]].Order < configs[names[j]].Order
 	})
 
-	// Renumber 0, 1, 2, ...
-	for i, dir := range dirs {
-		configPath := filepath.Join(dirPath, dir.name, "config.json")
-		configFile, err := os.ReadFile(configPath)
-		if err != nil {
-			continue
-		}
-		var config Config
-		if err := json.Unmarshal(configFile, &config); err != nil {
+	return names, configs, nil
+}
+
+// applyOrders renumbers the docs in dirPath 0, 1, 2, ... in the given order as one transaction
+// Only configs whose order changes are written; if any write fails, the others are rolled back
+func applyOrders(dirPath string, names []string, configs map[string]*Config) error {
+	var tx configTransaction
+	for i, name := range names {
+		config := configs[name]
+		if config.Order == i {
 			continue
 		}
-		config.Order = i
-		configJSON, err := json.MarshalIndent(config, "", "  ")
-		if err != nil {
-			continue
+		updated := *config
+		updated.Order = i
+		if err := tx.Set(filepath.Join(dirPath, name), &updated); err != nil {
+			return err
 		}
-		os.WriteFile(configPath, configJSON, 0644)
 	}
+	return tx.Commit()
+}
 
-	return nil
+// normalizeOrdersInDir reads all subdirectories, sorts them by current order, and renumbers them 0, 1, 2, ...
+func normalizeOrdersInDir(dirPath string) error {
+	names, configs, err := readSiblingOrders(dirPath, "")
+	if err != nil {
+		return err
+	}
+	return applyOrders(dirPath, names, configs)
 }
 
 // reorderDocInDir positions a doc relative to siblings and renumbers all docs in the directory
 func reorderDocInDir(dirPath string, docName string, beforeSibling string, afterSibling string) error {
-	entries, err := os.ReadDir(dirPath)
+	// Collect all siblings with their current order, excluding the doc being moved
+	names, configs, err := readSiblingOrders(dirPath, docName)
 	if err != nil {
-		return fmt.Errorf("failed to read directory %s: %w", dirPath, err)
-	}
-
-	// Collect all subdirectories with their current order, excluding the doc being moved
-	type dirOrder struct {
-		name  string
-		order int
+		return err
 	}
-	var dirs []dirOrder
 
-	for _, entry := range entries {
-		if entry.IsDir() && entry.Name() != docName {
-			configPath := filepath.Join(dirPath, entry.Name(), "config.json")
-			configFile, err := os.ReadFile(configPath)
-			if err != nil {
-				continue
-			}
-			var config Config
-			if err := json.Unmarshal(configFile, &config); err != nil {
-				continue
-			}
-			dirs = append(dirs, dirOrder{name: entry.Name(), order: config.Order})
-		}
+	docConfig, err := readConfig(filepath.Join(dirPath, docName))
+	if err != nil {
+		return err
 	}
-
-	// Sort by current order
-	sort.Slice(dirs, func(i, j int) bool {
-		return dirs[i].order < dirs[j].order
-	})
+	configs[docName] = docConfig
 
 	// Find insertion position based on siblings
-	insertIdx := len(dirs) // Default: append to end
+	insertIdx := len(names) // Default: append to end
 
 	if afterSibling != "" {
 		// Insert after the afterSibling
-		for i, dir := range dirs {
-			if dir.name == afterSibling {
+		for i, name := range names {
+			if name == afterSibling {
 				insertIdx = i + 1
 				break
 			}
 		}
 	} else if beforeSibling != "" {
 		// Insert before the beforeSibling
-		for i, dir := range dirs {
-			if dir.name == beforeSibling {
+		for i, name := range names {
+			if name == beforeSibling {
 				insertIdx = i
 				break
 			}
@@ -372,31 +444,12 @@ func reorderDocInDir(dirPath string, docName string, beforeSibling string, after
 	}
 
 	// Insert the doc at the correct position
-	newDirs := make([]dirOrder, 0, len(dirs)+1)
-	newDirs = append(newDirs, dirs[:insertIdx]...)
-	newDirs = append(newDirs, dirOrder{name: docName, order: 0})
-	newDirs = append(newDirs, dirs[insertIdx:]...)
-
-	// Write new orders to all config files
-	for i, dir := range newDirs {
-		configPath := filepath.Join(dirPath, dir.name, "config.json")
-		configFile, err := os.ReadFile(configPath)
-		if err != nil {
-			continue
-		}
-		var config Config
-		if err := json.Unmarshal(configFile, &config); err != nil {
-			continue
-		}
-		config.Order = i
-		configJSON, err := json.MarshalIndent(config, "", "  ")
-		if err != nil {
-			continue
-		}
-		os.WriteFile(configPath, configJSON, 0644)
-	}
+	ordered := make([]string, 0, len(names)+1)
+	ordered = append(ordered, names[:insertIdx]...)
+	ordered = append(ordered, docName)
+	ordered = append(ordered, names[insertIdx:]...)
 
-	return nil
+	return applyOrders(dirPath, ordered, configs)
 }
 
 // UpdateDocOrder finds the doc by name, moves it if path differs, positions it relative to siblings, and normalizes orders
@@ -427,6 +480,23 @@ func UpdateDocOrder(payload UpdateDocOrderRequestPayload) error {
 	// If path differs, move the folder
 	pathChanged := currentParentPath != updatedParentPath
 	if pathChanged {
+		// Slugs must stay unique among siblings, so a doc cannot join a parent whose child uses its slug
+		config, err := readConfig(currentFullPath)
+		if err != nil {
+			return err
+		}
+		if config.Slug != "" {
+			if _, err := os.Stat(destParentFullPath); err == nil {
+				sibling, err := findSlugInDir(destParentFullPath, config.Slug, payload.Name)
+				if err != nil {
+					return err
+				}
+				if sibling != nil {
+					return fmt.Errorf("cannot move doc: its slug %q is already used by %q in the destination; change one of the slugs first", config.Slug, sibling.Title)
+				}
+			}
+		}
+
 		// Ensure the destination parent directory exists
 		if err := os.MkdirAll(destParentFullPath, 0755); err != nil {
 			return fmt.Errorf("failed to create parent directory: %w", err)
@@ -440,6 +510,12 @@ func UpdateDocOrder(payload UpdateDocOrderRequestPayload) error {
 
 	// Reorder the doc in the destination directory based on siblings
 	if err := reorderDocInDir(destParentFullPath, payload.Name, payload.BeforeSibling, payload.AfterSibling); err != nil {
+		// Put the folder back so the doc is not left unordered in its new parent
+		if pathChanged {
+			if rollbackErr := os.Rename(updatedFullPath, currentFullPath); rollbackErr != nil {
+				return fmt.Errorf("failed to reorder doc: %w (and failed to move it back: %v)", err, rollbackErr)
+			}
+		}
 		return fmt.Errorf("failed to reorder doc: %w", err)
 	}
 
@@ -452,3 +528,193 @@ func UpdateDocOrder(payload UpdateDocOrderRequestPayload) error {
 
 	return nil
 }
+
+// resolveChildRef resolves a sibling reference (folder name or title) to a folder name inside parentPath
+func resolveChildRef(parentPath string, ref string) (string, error) {
+	fullPath, err := getDoclificPath(parentPath)
+	if err != nil {
+		return "", err
+	}
+
+	entries, err := os.ReadDir(fullPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read directory %s: %w", fullPath, err)
+	}
+
+	var matches []string
+	for _, entry := range entries {
+		if !isDocDir(entry) {
+			continue
+		}
+		if entry.Name() == ref {
+			return ref, nil
+		}
+		config, err := readConfig(filepath.Join(fullPath, entry.Name()))
+		if err != nil {
+			continue
+		}
+		if strings.EqualFold(strings.TrimSpace(config.Title), strings.TrimSpace(ref)) {
+			matches = append(matches, entry.Name())
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no sibling doc found for %q", ref)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("%q matches %d sibling docs (%s); use a folder name instead", ref, len(matches), strings.Join(matches, ", "))
+	}
+}
+
+// MoveDoc moves a doc under a new parent and positions it before or after a sibling
+// Docs and parents may be given as folder paths, UUIDs, slug paths or title paths; siblings as
+// folder names or titles. A nil parentRef keeps the current parent and only reorders
+func MoveDoc(docRef string, parentRef *string, beforeRef string, afterRef string) error {
+	if beforeRef != "" && afterRef != "" {
+		return fmt.Errorf("only one of before and after can be set")
+	}
+
+	filePath, err := ResolveDocRef(docRef)
+	if err != nil {
+		return err
+	}
+	if filePath == "" {
+		return fmt.Errorf("doc is required")
+	}
+
+	parentPath := path.Dir(filePath)
+	if parentPath == "." {
+		parentPath = ""
+	}
+	if parentRef != nil {
+		if parentPath, err = ResolveDocRef(*parentRef); err != nil {
+			return err
+		}
+	}
+	if parentPath == filePath || strings.HasPrefix(parentPath, filePath+"/") {
+		return fmt.Errorf("cannot move a doc into itself")
+	}
+
+	payload := UpdateDocOrderRequestPayload{
+		Name:        path.Base(filePath),
+		UpdatedPath: parentPath,
+	}
+	if beforeRef != "" {
+		if payload.BeforeSibling, err = resolveChildRef(parentPath, beforeRef); err != nil {
+			return err
+		}
+	}
+	if afterRef != "" {
+		if payload.AfterSibling, err = resolveChildRef(parentPath, afterRef); err != nil {
+			return err
+		}
+	}
+	if payload.BeforeSibling == payload.Name || payload.AfterSibling == payload.Name {
+		return fmt.Errorf("cannot position a doc relative to itself")
+	}
+
+	return UpdateDocOrder(payload)
+}
+
+// copyDocTree copies a doc folder into destPath, giving every nested doc folder a new UUID
+// Files are copied verbatim; dot-directories are skipped since they are not part of a doc.
+// destPath is expected to be a staging folder that is renamed into place once the copy is complete
+func copyDocTree(srcPath string, destPath string) error {
+	if err := os.MkdirAll(destPath, 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	entries, err := os.ReadDir(srcPath)
+	if err != nil {
+		return fmt.Errorf("failed to read directory %s: %w", srcPath, err)
+	}
+
+	for _, entry := range entries {
+		entrySrcPath := filepath.Join(srcPath, entry.Name())
+
+		if entry.IsDir() {
+			if !isDocDir(entry) {
+				continue
+			}
+			if err := copyDocTree(entrySrcPath, filepath.Join(destPath, uuid.New().String())); err != nil {
+				return err
+			}
+			continue
+		}
+
+		data, err := os.ReadFile(entrySrcPath)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", entrySrcPath, err)
+		}
+		if err := os.WriteFile(filepath.Join(destPath, entry.Name()), data, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", entry.Name(), err)
+		}
+	}
+
+	return nil
+}
+
+// DuplicateDoc deep-copies a doc and all of its children under new UUIDs
+// The copy's title gets a " (copy)" suffix and it is placed directly after the original
+func DuplicateDoc(filePath string) (*CreateDocResponse, error) {
+	if filePath == "" {
+		return nil, fmt.Errorf("filePath is required")
+	}
+
+	fullPath, err := getDoclificPath(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := readConfig(fullPath)
+	if err != nil {
+		return nil, err
+	}
+
+	parentFullPath := filepath.Dir(fullPath)
+	originalName := filepath.Base(fullPath)
+	newFolderName := uuid.New().String()
+	newFolderPath := filepath.Join(parentFullPath, newFolderName)
+
+	// Copy into a hidden staging folder and rename it into place, so a failure never
+	// leaves a half-copied doc in the tree
+	stagingPath := filepath.Join(parentFullPath, "."+newFolderName+".tmp")
+	if err := copyDocTree(fullPath, stagingPath); err != nil {
+		os.RemoveAll(stagingPath)
+		return nil, fmt.Errorf("failed to copy doc: %w", err)
+	}
+
+	// The copy's slug would collide with the original, so it starts without one
+	config.Title = config.Title + " (copy)"
+	config.Slug = ""
+	config.PreviousSlugs = nil
+	if err := writeConfig(stagingPath, config); err != nil {
+		os.RemoveAll(stagingPath)
+		return nil, err
+	}
+
+	if err := os.Rename(stagingPath, newFolderPath); err != nil {
+		os.RemoveAll(stagingPath)
+		return nil, fmt.Errorf("failed to copy doc: %w", err)
+	}
+
+	// Place the copy right after the original
+	if err := reorderDocInDir(parentFullPath, newFolderName, "", originalName); err != nil {
+		return nil, fmt.Errorf("failed to reorder doc: %w", err)
+	}
+
+	// Build the URL path from the parent of the original (forward slashes for URL)
+	url := newFolderName
+	if parentPath := filepath.ToSlash(filepath.Dir(filepath.FromSlash(filePath))); parentPath != "." {
+		url = parentPath + "/" + newFolderName
+	}
+
+	return &CreateDocResponse{
+		FilePath: newFolderPath,
+		URL:      url,
+		Title:    config.Title,
+		Icon:     config.Icon,
+	}, nil
+}
@@ -3,6 +3,7 @@ package core
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -186,7 +187,7 @@ func TestCreateDoc(t *testing.T) {
 
 	// Test CreateDoc at root level
 	icon := "test-icon"
-	response, err := CreateDoc("", "Test Document", &icon)
+	response, err := CreateDoc("", "Test Document", &icon, "")
 	if err != nil {
 		t.Fatalf("CreateDoc() error = %v", err)
 	}
@@ -229,7 +230,7 @@ func TestCreateDoc(t *testing.T) {
 	}
 
 	// Test CreateDoc with nested path
-	nestedResponse, err := CreateDoc("parent-folder", "Nested Document", nil)
+	nestedResponse, err := CreateDoc("parent-folder", "Nested Document", nil, "")
 	if err != nil {
 		t.Fatalf("CreateDoc() error = %v", err)
 	}
@@ -339,3 +340,193 @@ func TestGetDocsWithNestedFolders(t *testing.T) {
 		t.Errorf("GetDocs() child title = %q, want %q", docs[0].Children[0].Title, "Child Folder")
 	}
 }
+
+func TestDuplicateDoc(t *testing.T) {
+	originalDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get current directory: %v", err)
+	}
+
+	tmpDir := t.TempDir()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("failed to change directory: %v", err)
+	}
+	defer os.Chdir(originalDir)
+
+	// Build root docs a, b with a nested child under a
+	doclificDir := filepath.Join(tmpDir, "doclific")
+	docs := map[string]string{
+		"a":       `{"title": "Section", "icon": "Book", "order": 0}`,
+		"b":       `{"title": "Other", "order": 1}`,
+		"a/child": `{"title": "Child", "order": 0}`,
+	}
+	for path, config := range docs {
+		dir := filepath.Join(doclificDir, filepath.FromSlash(path))
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", path, err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(config), 0644); err != nil {
+			t.Fatalf("failed to write config.json: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "content.mdx"), []byte("# "+path), 0644); err != nil {
+			t.Fatalf("failed to write content.mdx: %v", err)
+		}
+	}
+
+	response, err := DuplicateDoc("a")
+	if err != nil {
+		t.Fatalf("DuplicateDoc() error = %v", err)
+	}
+	if response.Title != "Section (copy)" {
+		t.Errorf("DuplicateDoc() title = %q, want %q", response.Title, "Section (copy)")
+	}
+	if response.Icon == nil || *response.Icon != "Book" {
+		t.Errorf("DuplicateDoc() icon = %v, want %q", response.Icon, "Book")
+	}
+
+	tree, err := GetDocs()
+	if err != nil {
+		t.Fatalf("GetDocs() error = %v", err)
+	}
+	if len(tree) != 3 {
+		t.Fatalf("GetDocs() returned %d docs, want 3", len(tree))
+	}
+
+	// The copy sits between the original and its next sibling
+	wantTitles := []string{"Section", "Section (copy)", "Other"}
+	for i, want := range wantTitles {
+		if tree[i].Title != want {
+			t.Errorf("GetDocs()[%d].Title = %q, want %q", i, tree[i].Title, want)
+		}
+	}
+
+	cp := tree[1]
+	if cp.Name == "a" || cp.Name != response.URL {
+		t.Errorf("DuplicateDoc() copy name = %q, URL = %q", cp.Name, response.URL)
+	}
+	if len(cp.Children) != 1 {
+		t.Fatalf("copy has %d children, want 1", len(cp.Children))
+	}
+	if cp.Children[0].Name == "child" {
+		t.Error("DuplicateDoc() did not give the child a new UUID")
+	}
+	if cp.Children[0].Title != "Child" {
+		t.Errorf("copy child title = %q, want %q", cp.Children[0].Title, "Child")
+	}
+
+	content, err := GetDoc(cp.Name + "/" + cp.Children[0].Name)
+	if err != nil {
+		t.Fatalf("GetDoc() error = %v", err)
+	}
+	if content != "# a/child" {
+		t.Errorf("copied child content = %q, want %q", content, "# a/child")
+	}
+
+	// Duplicating a nested doc keeps it under the same parent
+	nested, err := DuplicateDoc("a/child")
+	if err != nil {
+		t.Fatalf("DuplicateDoc() nested error = %v", err)
+	}
+	if !strings.HasPrefix(nested.URL, "a/") {
+		t.Errorf("DuplicateDoc() nested URL = %q, want prefix %q", nested.URL, "a/")
+	}
+
+	if _, err := DuplicateDoc("missing"); err == nil {
+		t.Error("DuplicateDoc() with missing doc should return error")
+	}
+
+	// A copy that fails partway leaves nothing behind in the tree
+	if err := os.Symlink("missing.png", filepath.Join(doclificDir, "b", "broken.png")); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+	if _, err := DuplicateDoc("b"); err == nil {
+		t.Fatal("DuplicateDoc() with an unreadable file should return error")
+	}
+	entries, err := os.ReadDir(doclificDir)
+	if err != nil {
+		t.Fatalf("failed to read doclific directory: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Errorf("failed DuplicateDoc() left %d entries in the tree, want 3", len(entries))
+	}
+}
+
+func TestMoveDoc(t *testing.T) {
+	originalDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get current directory: %v", err)
+	}
+
+	tmpDir := t.TempDir()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("failed to change directory: %v", err)
+	}
+	defer os.Chdir(originalDir)
+
+	doclificDir := filepath.Join(tmpDir, "doclific")
+	docs := map[string]string{
+		"guides":          `{"title": "Guides", "order": 0}`,
+		"guides/setup":    `{"title": "Setup", "order": 0}`,
+		"guides/deploy":   `{"title": "Deploy", "order": 1}`,
+		"reference":       `{"title": "Reference", "order": 1}`,
+		"reference/api":   `{"title": "API", "order": 0}`,
+		"reference/howto": `{"title": "How To", "order": 1}`,
+	}
+	for path, config := range docs {
+		dir := filepath.Join(doclificDir, filepath.FromSlash(path))
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", path, err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(config), 0644); err != nil {
+			t.Fatalf("failed to write config.json: %v", err)
+		}
+	}
+
+	titles := func() string {
+		t.Helper()
+		tree, err := GetDocs()
+		if err != nil {
+			t.Fatalf("GetDocs() error = %v", err)
+		}
+		var result []string
+		WalkDocs(tree, func(filePath string, doc FolderStructure) error {
+			result = append(result, strings.Repeat("-", strings.Count(filePath, "/"))+doc.Title)
+			return nil
+		})
+		return strings.Join(result, ",")
+	}
+
+	// Move by title path under a parent given by title, placed before a sibling given by title
+	to := "Guides"
+	if err := MoveDoc("Reference/How To", &to, "deploy", ""); err != nil {
+		t.Fatalf("MoveDoc() error = %v", err)
+	}
+	if got, want := titles(), "Guides,-Setup,-How To,-Deploy,Reference,-API"; got != want {
+		t.Errorf("after move tree = %s, want %s", got, want)
+	}
+
+	// Reorder in place by UUID
+	if err := MoveDoc("setup", nil, "", "Deploy"); err != nil {
+		t.Fatalf("MoveDoc() error = %v", err)
+	}
+	if got, want := titles(), "Guides,-How To,-Deploy,-Setup,Reference,-API"; got != want {
+		t.Errorf("after reorder tree = %s, want %s", got, want)
+	}
+
+	// Move to the root
+	root := "/"
+	if err := MoveDoc("API", &root, "Guides", ""); err != nil {
+		t.Fatalf("MoveDoc() error = %v", err)
+	}
+	if got, want := titles(), "API,Guides,-How To,-Deploy,-Setup,Reference"; got != want {
+		t.Errorf("after move to root tree = %s, want %s", got, want)
+	}
+
+	into := "Guides/Setup"
+	if err := MoveDoc("Guides", &into, "", ""); err == nil {
+		t.Error("MoveDoc() into its own child should fail")
+	}
+	if err := MoveDoc("Setup", nil, "Missing", ""); err == nil {
+		t.Error("MoveDoc() before a missing sibling should fail")
+	}
+}
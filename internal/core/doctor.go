@@ -0,0 +1,305 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Kinds of problems found in the doclific folder
+const (
+	ProblemMissingConfig  = "missing-config"
+	ProblemInvalidConfig  = "invalid-config"
+	ProblemMissingContent = "missing-content"
+	ProblemOrphanContent  = "orphan-content"
+	ProblemDuplicateOrder = "duplicate-order"
+	ProblemNonUUIDFolder  = "non-uuid-folder"
+	ProblemHiddenByParent = "hidden-by-parent"
+)
+
+// DocProblem is something wrong with a folder in the doclific tree
+type DocProblem struct {
+	FilePath string `json:"filePath"` // folder path relative to doclific; empty for the doclific folder itself
+	Kind     string `json:"kind"`
+	Message  string `json:"message"`
+	Repaired bool   `json:"repaired,omitempty"`
+}
+
+// configProblem describes why a folder's config.json could not be read
+func configProblem(filePath string, err error) DocProblem {
+	if errors.Is(err, fs.ErrNotExist) {
+		return DocProblem{FilePath: filePath, Kind: ProblemMissingConfig, Message: "config.json is missing"}
+	}
+	return DocProblem{FilePath: filePath, Kind: ProblemInvalidConfig, Message: err.Error()}
+}
+
+// hiddenDocs appends a problem for every doc nested under a folder left out of the tree, since
+// the scan cannot reach them either
+func hiddenDocs(dirPath string, relPath string, problems *[]DocProblem) {
+	entries, err := os.ReadDir(dirPath)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if !isDocDir(entry) {
+			continue
+		}
+		filePath := relPath + "/" + entry.Name()
+		*problems = append(*problems, DocProblem{
+			FilePath: filePath,
+			Kind:     ProblemHiddenByParent,
+			Message:  fmt.Sprintf("hidden because the config.json of %s is missing or invalid", relPath),
+		})
+		hiddenDocs(filepath.Join(dirPath, entry.Name()), filePath, problems)
+	}
+}
+
+// diagnoseDir appends the problems found in a doc folder's children to problems
+func diagnoseDir(dirPath string, relPath string, problems *[]DocProblem) error {
+	entries, err := os.ReadDir(dirPath)
+	if err != nil {
+		return fmt.Errorf("failed to read directory %s: %w", dirPath, err)
+	}
+
+	orders := map[int][]string{}
+	for _, entry := range entries {
+		if !isDocDir(entry) {
+			continue
+		}
+		fullPath := filepath.Join(dirPath, entry.Name())
+		filePath := entry.Name()
+		if relPath != "" {
+			filePath = relPath + "/" + entry.Name()
+		}
+
+		if config, err := readConfig(fullPath); err != nil {
+			*problems = append(*problems, configProblem(filePath, err))
+		} else {
+			orders[config.Order] = append(orders[config.Order], entry.Name())
+		}
+
+		if _, err := os.Stat(filepath.Join(fullPath, "content.mdx")); errors.Is(err, fs.ErrNotExist) {
+			*problems = append(*problems, DocProblem{FilePath: filePath, Kind: ProblemMissingContent, Message: "content.mdx is missing"})
+		}
+		if _, err := uuid.Parse(entry.Name()); err != nil {
+			*problems = append(*problems, DocProblem{FilePath: filePath, Kind: ProblemNonUUIDFolder, Message: fmt.Sprintf("folder name %q is not a UUID", entry.Name())})
+		}
+
+		if err := diagnoseDir(fullPath, filePath, problems); err != nil {
+			return err
+		}
+	}
+
+	var duplicates []string
+	for order, names := range orders {
+		if len(names) > 1 {
+			sort.Strings(names)
+			duplicates = append(duplicates, fmt.Sprintf("%s share order %d", strings.Join(names, ", "), order))
+		}
+	}
+	if len(duplicates) > 0 {
+		sort.Strings(duplicates)
+		*problems = append(*problems, DocProblem{FilePath: relPath, Kind: ProblemDuplicateOrder, Message: strings.Join(duplicates, "; ")})
+	}
+
+	return nil
+}
+
+// Diagnose checks the doclific folder for missing or invalid configs, docs without content,
+// a stray content.mdx outside any doc, siblings sharing an order, and folders not named by UUID
+func Diagnose() ([]DocProblem, error) {
+	doclificPath, err := getDoclificPath("")
+	if err != nil {
+		return nil, err
+	}
+
+	problems := []DocProblem{}
+	if _, err := os.Stat(doclificPath); errors.Is(err, fs.ErrNotExist) {
+		return problems, nil
+	}
+
+	if _, err := os.Stat(filepath.Join(doclificPath, "content.mdx")); err == nil {
+		problems = append(problems, DocProblem{Kind: ProblemOrphanContent, Message: "content.mdx is not inside a doc folder"})
+	}
+
+	if err := diagnoseDir(doclificPath, "", &problems); err != nil {
+		return nil, err
+	}
+	return problems, nil
+}
+
+// recoveredTitle picks a title for a doc without a usable config from its content's first heading
+func recoveredTitle(dirPath string, fallback string) string {
+	if content, err := os.ReadFile(filepath.Join(dirPath, "content.mdx")); err == nil {
+		if match := headingRegex.FindStringSubmatch(string(content)); match != nil {
+			return strings.TrimSpace(match[1])
+		}
+	}
+	return fallback
+}
+
+// nextOrder returns an order after every sibling with a readable config
+func nextOrder(parentPath string, skip string) int {
+	entries, err := os.ReadDir(parentPath)
+	if err != nil {
+		return 0
+	}
+	next := 0
+	for _, entry := range entries {
+		if !isDocDir(entry) || entry.Name() == skip {
+			continue
+		}
+		if config, err := readConfig(filepath.Join(parentPath, entry.Name())); err == nil && config.Order >= next {
+			next = config.Order + 1
+		}
+	}
+	return next
+}
+
+// repairConfig writes a fresh config.json, keeping an invalid one as config.json.bak
+func repairConfig(fullPath string, invalid bool) error {
+	if invalid {
+		if err := os.Rename(filepath.Join(fullPath, "config.json"), filepath.Join(fullPath, "config.json.bak")); err != nil {
+			return fmt.Errorf("failed to back up config.json: %w", err)
+		}
+	}
+	config := &Config{
+		Title: recoveredTitle(fullPath, filepath.Base(fullPath)),
+		Order: nextOrder(filepath.Dir(fullPath), filepath.Base(fullPath)),
+	}
+	return writeConfig(fullPath, config)
+}
+
+// repairOrphanContent moves a stray content.mdx at the doclific root into a new doc
+func repairOrphanContent(doclificPath string) error {
+	name := uuid.New().String()
+	stagingPath := filepath.Join(doclificPath, "."+name+".tmp")
+	if err := os.Mkdir(stagingPath, 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := os.Rename(filepath.Join(doclificPath, "content.mdx"), filepath.Join(stagingPath, "content.mdx")); err != nil {
+		os.RemoveAll(stagingPath)
+		return fmt.Errorf("failed to move content.mdx: %w", err)
+	}
+	config := &Config{
+		Title: recoveredTitle(stagingPath, "Recovered doc"),
+		Order: nextOrder(doclificPath, ""),
+	}
+	if err := writeConfig(stagingPath, config); err == nil {
+		err = os.Rename(stagingPath, filepath.Join(doclificPath, name))
+		if err == nil {
+			return nil
+		}
+	}
+	// Put the content back where it was rather than losing it in a hidden folder
+	os.Rename(filepath.Join(stagingPath, "content.mdx"), filepath.Join(doclificPath, "content.mdx"))
+	os.RemoveAll(stagingPath)
+	return fmt.Errorf("failed to create doc for orphan content.mdx")
+}
+
+// renameDocFolder gives a doc folder a UUID name and rewrites doc: links that use the old folder name
+func renameDocFolder(doclificPath string, filePath string) (string, error) {
+	oldName := path.Base(filePath)
+	newName := uuid.New().String()
+	parentFull := filepath.Dir(filepath.Join(doclificPath, filepath.FromSlash(filePath)))
+
+	if err := os.Rename(filepath.Join(parentFull, oldName), filepath.Join(parentFull, newName)); err != nil {
+		return "", fmt.Errorf("failed to rename folder: %w", err)
+	}
+
+	segment := regexp.MustCompile(`(\]\(doc:(?:[^)\s]*/)?)` + regexp.QuoteMeta(oldName) + `([/#)])`)
+	err := filepath.WalkDir(doclificPath, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || d.Name() != "content.mdx" {
+			return err
+		}
+		content, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		updated := segment.ReplaceAllString(string(content), "${1}"+newName+"${2}")
+		if updated == string(content) {
+			return nil
+		}
+		return writeFileAtomic(p, []byte(updated), 0644)
+	})
+	if err != nil {
+		return newName, fmt.Errorf("failed to update links to %s: %w", oldName, err)
+	}
+	return newName, nil
+}
+
+// RepairDocs fixes the given problems in place and marks each one it repaired
+// Configs are rebuilt first so that renumbering and renames see every doc
+func RepairDocs(problems []DocProblem) error {
+	doclificPath, err := getDoclificPath("")
+	if err != nil {
+		return err
+	}
+
+	rank := map[string]int{
+		ProblemMissingConfig:  0,
+		ProblemInvalidConfig:  0,
+		ProblemMissingContent: 1,
+		ProblemOrphanContent:  2,
+		ProblemDuplicateOrder: 3,
+		ProblemNonUUIDFolder:  4,
+	}
+	indexes := make([]int, len(problems))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	// Rename the deepest folders first so the paths of the remaining problems stay valid
+	sort.SliceStable(indexes, func(i, j int) bool {
+		a, b := problems[indexes[i]], problems[indexes[j]]
+		if rank[a.Kind] != rank[b.Kind] {
+			return rank[a.Kind] < rank[b.Kind]
+		}
+		return strings.Count(a.FilePath, "/") > strings.Count(b.FilePath, "/")
+	})
+
+	var errs []error
+	for _, i := range indexes {
+		problem := &problems[i]
+		fullPath := filepath.Join(doclificPath, filepath.FromSlash(problem.FilePath))
+
+		var err error
+		switch problem.Kind {
+		case ProblemMissingConfig, ProblemInvalidConfig:
+			err = repairConfig(fullPath, problem.Kind == ProblemInvalidConfig)
+		case ProblemMissingContent:
+			title := filepath.Base(fullPath)
+			if config, readErr := readConfig(fullPath); readErr == nil {
+				title = config.Title
+			}
+			err = writeFileAtomic(filepath.Join(fullPath, "content.mdx"), []byte(fmt.Sprintf("# %s\n", title)), 0644)
+		case ProblemOrphanContent:
+			err = repairOrphanContent(doclificPath)
+		case ProblemDuplicateOrder:
+			err = normalizeOrdersInDir(fullPath)
+		case ProblemNonUUIDFolder:
+			var newName string
+			newName, err = renameDocFolder(doclificPath, problem.FilePath)
+			if newName != "" {
+				problem.Message += fmt.Sprintf(" (renamed to %s)", newName)
+			}
+		default:
+			continue
+		}
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s %s: %w", problem.Kind, problem.FilePath, err))
+			continue
+		}
+		problem.Repaired = true
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,123 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestDoctor(t *testing.T) {
+	originalDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get current directory: %v", err)
+	}
+
+	tmpDir := t.TempDir()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("failed to change directory: %v", err)
+	}
+	defer os.Chdir(originalDir)
+
+	good := "11111111-1111-1111-1111-111111111111"
+	dup := "22222222-2222-2222-2222-222222222222"
+	broken := "33333333-3333-3333-3333-333333333333"
+	noConfig := "44444444-4444-4444-4444-444444444444"
+	hidden := broken + "/55555555-5555-5555-5555-555555555555"
+	files := map[string]string{
+		"doclific/content.mdx":                  "# Stray\n",
+		"doclific/" + good + "/config.json":     `{"title": "Good", "order": 0}`,
+		"doclific/" + good + "/content.mdx":     "See [legacy](doc:legacy#intro).\n",
+		"doclific/" + dup + "/config.json":      `{"title": "Dup", "order": 0}`,
+		"doclific/" + broken + "/config.json":   `{"title": "Broken"`,
+		"doclific/" + broken + "/content.mdx":   "# Broken Doc\n",
+		"doclific/" + noConfig + "/content.mdx": "# No Config\n",
+		"doclific/" + hidden + "/config.json":   `{"title": "Hidden", "order": 0}`,
+		"doclific/" + hidden + "/content.mdx":   "# Hidden\n",
+		"doclific/legacy/config.json":           `{"title": "Legacy", "order": 1}`,
+		"doclific/legacy/content.mdx":           "# Legacy\n",
+	}
+	for path, content := range files {
+		fullPath := filepath.Join(tmpDir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", path, err)
+		}
+	}
+
+	// Broken folders are skipped instead of failing the whole tree
+	docs, scanProblems, err := GetDocsWithProblems()
+	if err != nil {
+		t.Fatalf("GetDocsWithProblems() error = %v", err)
+	}
+	if len(docs) != 3 {
+		t.Errorf("GetDocsWithProblems() returned %d docs, want 3", len(docs))
+	}
+	if len(scanProblems) != 3 || scanProblems[1] != (DocProblem{FilePath: hidden, Kind: ProblemHiddenByParent, Message: "hidden because the config.json of " + broken + " is missing or invalid"}) {
+		t.Errorf("GetDocsWithProblems() returned %d problems, want 3 including the broken doc's child: %+v", len(scanProblems), scanProblems)
+	}
+
+	problems, err := Diagnose()
+	if err != nil {
+		t.Fatalf("Diagnose() error = %v", err)
+	}
+	var kinds []string
+	for _, problem := range problems {
+		kinds = append(kinds, problem.Kind+" "+problem.FilePath)
+	}
+	sort.Strings(kinds)
+	want := []string{
+		"duplicate-order ",
+		"invalid-config " + broken,
+		"missing-config " + noConfig,
+		"missing-content " + dup,
+		"non-uuid-folder legacy",
+		"orphan-content ",
+	}
+	if strings.Join(kinds, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Diagnose() = \n%s\nwant\n%s", strings.Join(kinds, "\n"), strings.Join(want, "\n"))
+	}
+
+	if err := RepairDocs(problems); err != nil {
+		t.Fatalf("RepairDocs() error = %v", err)
+	}
+	for _, problem := range problems {
+		if !problem.Repaired {
+			t.Errorf("problem %s %s was not repaired", problem.Kind, problem.FilePath)
+		}
+	}
+
+	remaining, err := Diagnose()
+	if err != nil {
+		t.Fatalf("Diagnose() error = %v", err)
+	}
+	if len(remaining) != 0 {
+		t.Errorf("Diagnose() after repair = %+v, want no problems", remaining)
+	}
+
+	docs, err = GetDocs()
+	if err != nil {
+		t.Fatalf("GetDocs() error = %v", err)
+	}
+	var titles []string
+	for _, doc := range docs {
+		titles = append(titles, doc.Title)
+	}
+	sort.Strings(titles)
+	if got := strings.Join(titles, ","); got != "Broken Doc,Dup,Good,Legacy,No Config,Stray" {
+		t.Errorf("titles after repair = %s", got)
+	}
+
+	if _, err := os.Stat(filepath.Join(tmpDir, "doclific", broken, "config.json.bak")); err != nil {
+		t.Errorf("invalid config was not backed up: %v", err)
+	}
+
+	// Links using the old folder name follow the rename
+	content, _ := GetDoc(good)
+	if strings.Contains(content, "doc:legacy") || !strings.Contains(content, "#intro)") {
+		t.Errorf("link to renamed folder was not updated: %q", content)
+	}
+}
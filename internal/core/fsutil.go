@@ -0,0 +1,89 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// writeFileAtomic writes data to a temporary file in the same directory and renames it over path,
+// so readers never see a partially written file even if the process dies mid-write
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+
+	cleanup := func(err error) error {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		return cleanup(err)
+	}
+	if err := tmp.Sync(); err != nil {
+		return cleanup(err)
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		return cleanup(err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	// Persist the rename itself; not every platform supports syncing a directory
+	if dirFile, err := os.Open(dir); err == nil {
+		dirFile.Sync()
+		dirFile.Close()
+	}
+	return nil
+}
+
+// configWrite is a pending config.json update within a configTransaction
+type configWrite struct {
+	dirPath  string
+	original []byte
+	config   *Config
+}
+
+// configTransaction writes a set of config.json files so that either all of them are
+// updated or, if any write fails, the ones already written are restored
+type configTransaction struct {
+	writes []configWrite
+}
+
+// Set queues config to be written to dirPath, remembering the current file for rollback
+func (tx *configTransaction) Set(dirPath string, config *Config) error {
+	original, err := os.ReadFile(filepath.Join(dirPath, "config.json"))
+	if err != nil {
+		return fmt.Errorf("config file not found for %s: %w", filepath.Base(dirPath), err)
+	}
+	tx.writes = append(tx.writes, configWrite{dirPath: dirPath, original: original, config: config})
+	return nil
+}
+
+// Commit writes every queued config, rolling back the written ones on the first failure
+func (tx *configTransaction) Commit() error {
+	for i, write := range tx.writes {
+		if err := writeConfig(write.dirPath, write.config); err != nil {
+			var rollbackErrs []error
+			for _, done := range tx.writes[:i] {
+				if rollbackErr := writeFileAtomic(filepath.Join(done.dirPath, "config.json"), done.original, 0644); rollbackErr != nil {
+					rollbackErrs = append(rollbackErrs, fmt.Errorf("failed to restore config.json for %s: %w", filepath.Base(done.dirPath), rollbackErr))
+				}
+			}
+			return errors.Join(append([]error{err}, rollbackErrs...)...)
+		}
+	}
+	return nil
+}
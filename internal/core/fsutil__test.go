@@ -0,0 +1,106 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "config.json")
+
+	if err := os.WriteFile(path, []byte("old content that is longer"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if err := writeFileAtomic(path, []byte("new"), 0644); err != nil {
+		t.Fatalf("writeFileAtomic() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+	if string(data) != "new" {
+		t.Errorf("writeFileAtomic() content = %q, want %q", data, "new")
+	}
+
+	entries, _ := os.ReadDir(tmpDir)
+	if len(entries) != 1 {
+		t.Errorf("writeFileAtomic() left %d files behind, want 1", len(entries))
+	}
+
+	if err := writeFileAtomic(filepath.Join(tmpDir, "missing", "config.json"), []byte("x"), 0644); err == nil {
+		t.Error("writeFileAtomic() into a missing directory should fail")
+	}
+}
+
+func TestConfigTransactionRollback(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, name := range []string{"a", "b"} {
+		dir := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", name, err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{"title": "`+name+`", "order": 5}`), 0644); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+	}
+
+	var tx configTransaction
+	if err := tx.Set(filepath.Join(tmpDir, "a"), &Config{Title: "a", Order: 0}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := tx.Set(filepath.Join(tmpDir, "b"), &Config{Title: "b", Order: 1}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	// Removing b after queuing makes its write fail, so a must be restored
+	if err := os.RemoveAll(filepath.Join(tmpDir, "b")); err != nil {
+		t.Fatalf("failed to remove b: %v", err)
+	}
+	if err := tx.Commit(); err == nil {
+		t.Fatal("Commit() should fail when a write fails")
+	}
+
+	config, err := readConfig(filepath.Join(tmpDir, "a"))
+	if err != nil {
+		t.Fatalf("readConfig() error = %v", err)
+	}
+	if config.Order != 5 {
+		t.Errorf("a order = %d after rollback, want 5", config.Order)
+	}
+}
+
+func TestReorderSurfacesInvalidConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	configs := map[string]string{
+		"a": `{"title": "A", "order": 0}`,
+		"b": `{"title": "B", "order": 1`,
+		"c": `{"title": "C", "order": 2}`,
+	}
+	for name, config := range configs {
+		dir := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", name, err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(config), 0644); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+	}
+
+	err := reorderDocInDir(tmpDir, "c", "a", "")
+	if err == nil || !strings.Contains(err.Error(), "failed to parse config.json for b") {
+		t.Errorf("reorderDocInDir() error = %v, want the invalid config to be reported", err)
+	}
+
+	// Nothing was renumbered
+	config, err := readConfig(filepath.Join(tmpDir, "c"))
+	if err != nil {
+		t.Fatalf("readConfig() error = %v", err)
+	}
+	if config.Order != 2 {
+		t.Errorf("c order = %d, want 2", config.Order)
+	}
+}
@@ -2,6 +2,7 @@ package core
 
 import (
 	"bufio"
+	"fmt"
 	"os/exec"
 	"regexp"
 	"strconv"
@@ -218,3 +219,51 @@ func calculateLineRangeFromDiff(diffOutput string, oldStart, oldEnd int) (newSta
 
 	return newStart, newEnd, nil
 }
+
+// FileChangeStats summarizes how much a file changed across a range of commits
+type FileChangeStats struct {
+	Commits      int `json:"commits"`
+	LinesAdded   int `json:"linesAdded"`
+	LinesDeleted int `json:"linesDeleted"`
+}
+
+// commitHashPattern matches an abbreviated or full commit hash, as written to a snippet's baseCommit
+var commitHashPattern = regexp.MustCompile(`^[0-9a-f]{7,40}$`)
+
+// GetFileChangesSince counts the commits and changed lines for a file between fromCommit and HEAD
+// It runs git log fromCommit..HEAD --numstat -- filePath. fromCommit comes from doc content,
+// so anything other than a commit hash is rejected rather than handed to git
+func GetFileChangesSince(filePath, fromCommit string) (FileChangeStats, error) {
+	var stats FileChangeStats
+
+	if !commitHashPattern.MatchString(fromCommit) {
+		return stats, fmt.Errorf("invalid base commit %q: expected a commit hash", fromCommit)
+	}
+
+	cmd := exec.Command("git", "log", "--numstat", "--format=commit %H", "--end-of-options", fromCommit+"..HEAD", "--", filePath)
+	output, err := cmd.Output()
+	if err != nil {
+		return stats, fmt.Errorf("failed to read git history for %s since %s: %w", filePath, fromCommit, err)
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(string(output)))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "commit ") {
+			stats.Commits++
+			continue
+		}
+
+		// numstat lines: added<TAB>deleted<TAB>path ("-" for binary files)
+		fields := strings.Split(line, "\t")
+		if len(fields) < 3 {
+			continue
+		}
+		added, _ := strconv.Atoi(fields[0])
+		deleted, _ := strconv.Atoi(fields[1])
+		stats.LinesAdded += added
+		stats.LinesDeleted += deleted
+	}
+
+	return stats, nil
+}
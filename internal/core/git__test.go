@@ -2,6 +2,8 @@ package core
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -36,3 +38,23 @@ func TestGetGitEmail(t *testing.T) {
 	}
 	fmt.Println(email)
 }
+
+func TestGetFileChangesSinceRejectsOptions(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "x")
+	for _, baseCommit := range []string{"--output=" + output, "-p", "HEAD~1", "abc"} {
+		if _, err := GetFileChangesSince("README.md", baseCommit); err == nil {
+			t.Errorf("GetFileChangesSince(%q) should fail", baseCommit)
+		}
+	}
+	if _, err := os.Stat(output); !os.IsNotExist(err) {
+		t.Errorf("git wrote %s, stat error = %v", output, err)
+	}
+
+	head, err := GetCurrentCommit()
+	if err != nil {
+		t.Fatalf("failed to get current commit: %v", err)
+	}
+	if _, err := GetFileChangesSince("README.md", head); err != nil {
+		t.Errorf("GetFileChangesSince(HEAD commit) error = %v", err)
+	}
+}
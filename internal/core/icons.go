@@ -0,0 +1,41 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"doclific/skills"
+)
+
+// iconNames loads the icon names from the embedded all-icons.json once
+var iconNames = sync.OnceValues(func() (map[string]bool, error) {
+	var names []string
+	if err := json.Unmarshal(skills.AllIconsJSON, &names); err != nil {
+		return nil, fmt.Errorf("failed to parse all-icons.json: %w", err)
+	}
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		set[name] = true
+	}
+	return set, nil
+})
+
+// ValidateIcon returns an error if icon is not one of the icons in all-icons.json
+func ValidateIcon(icon string) error {
+	names, err := iconNames()
+	if err != nil {
+		return err
+	}
+	if names[icon] {
+		return nil
+	}
+
+	for name := range names {
+		if strings.EqualFold(name, icon) {
+			return fmt.Errorf("icon %q not found, did you mean %q?", icon, name)
+		}
+	}
+	return fmt.Errorf("icon %q not found in all-icons.json", icon)
+}
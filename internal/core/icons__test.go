@@ -0,0 +1,21 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateIcon(t *testing.T) {
+	if err := ValidateIcon("Rocket"); err != nil {
+		t.Errorf("ValidateIcon(Rocket) error = %v", err)
+	}
+
+	err := ValidateIcon("rocket")
+	if err == nil || !strings.Contains(err.Error(), `did you mean "Rocket"`) {
+		t.Errorf("ValidateIcon(rocket) error = %v, want a suggestion", err)
+	}
+
+	if err := ValidateIcon("NotAnIcon"); err == nil {
+		t.Error("ValidateIcon(NotAnIcon) should fail")
+	}
+}
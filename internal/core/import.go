@@ -0,0 +1,488 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// minSnippetLines is the fewest non-blank lines a fenced block needs before it is matched against repository files
+const minSnippetLines = 2
+
+// ImportOptions configures ImportMarkdown
+type ImportOptions struct {
+	SourceDir      string // directory of .md/.mdx files, relative to the working directory or absolute
+	ParentPath     string // doc folder path to import under; empty imports at the root
+	DetectSnippets bool   // turn fenced blocks that match repository files verbatim into CodebaseSnippets
+}
+
+// ImportedDoc is a doc created by ImportMarkdown
+type ImportedDoc struct {
+	Source   string `json:"source"` // source path relative to SourceDir, empty for folders without an index file
+	FilePath string `json:"filePath"`
+	Title    string `json:"title"`
+}
+
+// ImportResult summarizes an ImportMarkdown run
+type ImportResult struct {
+	Docs     []ImportedDoc `json:"docs"`
+	Snippets int           `json:"snippets"`
+}
+
+// importNode is a markdown file or folder to be created as a doc
+type importNode struct {
+	Name     string // file or folder name without extension
+	Source   string // absolute path of the markdown file, empty for a folder without an index file
+	Children []*importNode
+}
+
+// importIndexNames are files whose content becomes their folder's doc
+var importIndexNames = []string{"index", "readme", "_index"}
+
+var (
+	frontMatterRegex    = regexp.MustCompile(`(?s)\A---\r?\n(.*?)\r?\n---\r?\n?`)
+	frontMatterTitle    = regexp.MustCompile(`(?m)^title:\s*["']?(.*?)["']?\s*$`)
+	headingRegex        = regexp.MustCompile(`(?m)^#{1,6}[ \t]+(.+?)[ \t#]*$`)
+	orderPrefixRegex    = regexp.MustCompile(`^\d+[-_. ]+`)
+	markdownLinkRegex   = regexp.MustCompile(`\]\(([^)\s]+)\)`)
+	fencedCodeRegex     = regexp.MustCompile("(?ms)^(```+|~~~+)[^\\n]*\\n(.*?)\\n?^(```+|~~~+)[ \\t]*$")
+	naturalSortSegments = regexp.MustCompile(`\d+|\D+`)
+)
+
+// isMarkdownFile reports whether name has a .md or .mdx extension
+func isMarkdownFile(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	return ext == ".md" || ext == ".mdx"
+}
+
+// NaturalLess orders names so that numeric runs compare by value ("2-setup" before "10-deploy")
+func NaturalLess(a, b string) bool {
+	aParts := naturalSortSegments.FindAllString(strings.ToLower(a), -1)
+	bParts := naturalSortSegments.FindAllString(strings.ToLower(b), -1)
+	for i := 0; i < len(aParts) && i < len(bParts); i++ {
+		if aParts[i] == bParts[i] {
+			continue
+		}
+		aNum, aErr := strconv.Atoi(aParts[i])
+		bNum, bErr := strconv.Atoi(bParts[i])
+		if aErr == nil && bErr == nil && aNum != bNum {
+			return aNum < bNum
+		}
+		return aParts[i] < bParts[i]
+	}
+	return len(aParts) < len(bParts)
+}
+
+// scanImportDir builds the import tree for a directory in natural file name order
+// A folder's index file (or a sibling file with the folder's name) becomes the folder's doc
+func scanImportDir(dirPath string) (*importNode, error) {
+	entries, err := os.ReadDir(dirPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory %s: %w", dirPath, err)
+	}
+
+	node := &importNode{Name: filepath.Base(dirPath)}
+	byName := map[string]*importNode{}
+	var names []string
+
+	child := func(name string) *importNode {
+		key := strings.ToLower(name)
+		if existing, ok := byName[key]; ok {
+			return existing
+		}
+		created := &importNode{Name: name}
+		byName[key] = created
+		names = append(names, key)
+		return created
+	}
+
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		entryPath := filepath.Join(dirPath, entry.Name())
+
+		if entry.IsDir() {
+			dir, err := scanImportDir(entryPath)
+			if err != nil {
+				return nil, err
+			}
+			if dir.Source == "" && len(dir.Children) == 0 {
+				continue // No markdown inside
+			}
+			target := child(entry.Name())
+			target.Children = dir.Children
+			if dir.Source != "" {
+				target.Source = dir.Source
+			}
+			continue
+		}
+
+		if !isMarkdownFile(entry.Name()) {
+			continue
+		}
+		name := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+		if slices.ContainsFunc(importIndexNames, func(index string) bool { return strings.EqualFold(index, name) }) {
+			node.Source = entryPath
+			continue
+		}
+		target := child(name)
+		if target.Source == "" {
+			target.Source = entryPath
+		}
+	}
+
+	sort.SliceStable(names, func(i, j int) bool {
+		return NaturalLess(names[i], names[j])
+	})
+	for _, name := range names {
+		node.Children = append(node.Children, byName[name])
+	}
+
+	return node, nil
+}
+
+// titleFromFileName turns a file name such as "02-getting_started" into "Getting started"
+func titleFromFileName(name string) string {
+	title := orderPrefixRegex.ReplaceAllString(name, "")
+	title = strings.TrimSpace(strings.NewReplacer("-", " ", "_", " ").Replace(title))
+	if title == "" {
+		title = name
+	}
+	runes := []rune(title)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// prepareImportContent strips front matter and returns the content and the doc title
+// The title comes from front matter, then the first heading, then the file name
+// Content without a leading heading gets one, matching docs created in the editor
+func prepareImportContent(content string, name string) (string, string) {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+
+	title := ""
+	if match := frontMatterRegex.FindStringSubmatch(content); match != nil {
+		if titleMatch := frontMatterTitle.FindStringSubmatch(match[1]); titleMatch != nil {
+			title = strings.TrimSpace(titleMatch[1])
+		}
+		content = content[len(match[0]):]
+	}
+	content = strings.TrimLeft(content, "\n")
+
+	heading := headingRegex.FindStringSubmatchIndex(content)
+	if title == "" && heading != nil {
+		title = strings.TrimSpace(content[heading[2]:heading[3]])
+	}
+	if title == "" {
+		title = titleFromFileName(name)
+	}
+
+	if heading == nil || heading[0] != 0 {
+		content = fmt.Sprintf("# %s\n\n%s", title, content)
+	}
+	return content, title
+}
+
+// rewriteImportLinks turns relative links between imported files into doc: links
+// sources maps absolute source paths to the created doc's folder name
+func rewriteImportLinks(content string, sourcePath string, sources map[string]string) string {
+	return markdownLinkRegex.ReplaceAllStringFunc(content, func(match string) string {
+		target := markdownLinkRegex.FindStringSubmatch(match)[1]
+		if strings.Contains(target, "://") || strings.HasPrefix(target, "#") || strings.HasPrefix(target, DocLinkScheme) {
+			return match
+		}
+
+		linkPath, anchor, _ := strings.Cut(target, "#")
+		if !isMarkdownFile(linkPath) {
+			return match
+		}
+
+		resolved := filepath.Join(filepath.Dir(sourcePath), filepath.FromSlash(linkPath))
+		docName, ok := sources[resolved]
+		if !ok {
+			return match
+		}
+		if anchor != "" {
+			docName += "#" + anchor
+		}
+		return "](" + DocLinkScheme + docName + ")"
+	})
+}
+
+// repoFile is a text file of the repository that fenced blocks are matched against
+type repoFile struct {
+	Path    string
+	Content string
+}
+
+// loadRepoFiles reads the repository's text files, skipping docs and the import source
+func loadRepoFiles(excludeDirs ...string) ([]repoFile, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current working directory: %w", err)
+	}
+
+	files, err := GetFileListAndMetadata(cwd, nil, cwd, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []repoFile
+	for _, file := range files {
+		fullPath := filepath.Join(cwd, filepath.FromSlash(file.Path))
+		excluded := isMarkdownFile(file.Path)
+		for _, dir := range excludeDirs {
+			if strings.HasPrefix(fullPath, dir+string(filepath.Separator)) {
+				excluded = true
+			}
+		}
+		if excluded {
+			continue
+		}
+
+		data, err := os.ReadFile(fullPath)
+		if err != nil || strings.ContainsRune(string(data), 0) {
+			continue
+		}
+		result = append(result, repoFile{Path: file.Path, Content: strings.ReplaceAll(string(data), "\r\n", "\n")})
+	}
+	return result, nil
+}
+
+// findInRepoFiles returns the file and 1-indexed line range where code appears verbatim as whole lines
+func findInRepoFiles(files []repoFile, code string) (string, int, int, bool) {
+	for _, file := range files {
+		offset := 0
+		for {
+			idx := strings.Index(file.Content[offset:], code)
+			if idx < 0 {
+				break
+			}
+			start := offset + idx
+			end := start + len(code)
+			if (start == 0 || file.Content[start-1] == '\n') && (end == len(file.Content) || file.Content[end] == '\n') {
+				lineStart := strings.Count(file.Content[:start], "\n") + 1
+				return file.Path, lineStart, lineStart + strings.Count(code, "\n"), true
+			}
+			offset = start + 1
+		}
+	}
+	return "", 0, 0, false
+}
+
+// replaceFencedSnippets swaps fenced code blocks that match repository files for CodebaseSnippets
+func replaceFencedSnippets(content string, files []repoFile, baseCommit string) (string, int) {
+	count := 0
+	content = fencedCodeRegex.ReplaceAllStringFunc(content, func(block string) string {
+		match := fencedCodeRegex.FindStringSubmatch(block)
+		if match[1][0] != match[3][0] || len(match[3]) < len(match[1]) {
+			return block
+		}
+		code := match[2]
+
+		nonBlank := 0
+		for _, line := range strings.Split(code, "\n") {
+			if strings.TrimSpace(line) != "" {
+				nonBlank++
+			}
+		}
+		if nonBlank < minSnippetLines {
+			return block
+		}
+
+		filePath, lineStart, lineEnd, ok := findInRepoFiles(files, code)
+		if !ok {
+			return block
+		}
+
+		count++
+		attributes := fmt.Sprintf(`filePath="%s" lineStart="%d" lineEnd="%d"`, filePath, lineStart, lineEnd)
+		if baseCommit != "" {
+			attributes += fmt.Sprintf(` baseCommit="%s"`, baseCommit)
+		}
+		attributes += fmt.Sprintf(` contentHash="%s"`, HashContent(code))
+		return fmt.Sprintf("<%s %s>\n</%s>", ComponentCodebaseSnippet, attributes, ComponentCodebaseSnippet)
+	})
+	return content, count
+}
+
+// ImportMarkdown creates a doc tree from a directory of .md/.mdx files through CreateDoc
+// Folders become parent docs, file order is kept and relative links become doc: links
+func ImportMarkdown(options ImportOptions) (*ImportResult, error) {
+	sourceDir, err := filepath.Abs(options.SourceDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve source directory: %w", err)
+	}
+	if info, err := os.Stat(sourceDir); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("source directory not found: %s", options.SourceDir)
+	}
+
+	parentFullPath, err := getDoclificPath(options.ParentPath)
+	if err != nil {
+		return nil, err
+	}
+	if options.ParentPath != "" {
+		if _, err := os.Stat(parentFullPath); err != nil {
+			return nil, fmt.Errorf("parent doc not found: %s", options.ParentPath)
+		}
+	}
+
+	root, err := scanImportDir(sourceDir)
+	if err != nil {
+		return nil, err
+	}
+
+	// A top-level index file has no folder doc to attach to, so it is imported as the first doc
+	if root.Source != "" {
+		root.Children = append([]*importNode{{Name: root.Name, Source: root.Source}}, root.Children...)
+	}
+
+	type pendingDoc struct {
+		Source   string
+		FilePath string
+		Content  string
+	}
+	var pending []pendingDoc
+	sources := map[string]string{}
+	result := &ImportResult{}
+
+	// Create every doc first so links can point at docs that come later in the tree
+	var create func(nodes []*importNode, parentPath string) error
+	create = func(nodes []*importNode, parentPath string) error {
+		parentDir, err := getDoclificPath(parentPath)
+		if err != nil {
+			return err
+		}
+
+		for _, node := range nodes {
+			content, title := prepareImportContent("", node.Name)
+			if node.Source != "" {
+				data, err := os.ReadFile(node.Source)
+				if err != nil {
+					return fmt.Errorf("failed to read %s: %w", node.Source, err)
+				}
+				content, title = prepareImportContent(string(data), node.Name)
+			}
+
+			response, err := CreateDoc(parentPath, title, nil, "")
+			if err != nil {
+				return err
+			}
+			docName := filepath.Base(response.FilePath)
+			if err := reorderDocInDir(parentDir, docName, "", ""); err != nil {
+				return err
+			}
+
+			source := ""
+			if node.Source != "" {
+				sources[node.Source] = docName
+				source, _ = filepath.Rel(sourceDir, node.Source)
+				source = filepath.ToSlash(source)
+			}
+			result.Docs = append(result.Docs, ImportedDoc{Source: source, FilePath: response.URL, Title: title})
+			pending = append(pending, pendingDoc{Source: node.Source, FilePath: response.URL, Content: content})
+
+			if err := create(node.Children, response.URL); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := create(root.Children, options.ParentPath); err != nil {
+		return nil, err
+	}
+
+	var repoFiles []repoFile
+	baseCommit := ""
+	if options.DetectSnippets {
+		doclificPath, err := getDoclificPath("")
+		if err != nil {
+			return nil, err
+		}
+		repoFiles, err = loadRepoFiles(doclificPath, sourceDir)
+		if err != nil {
+			return nil, err
+		}
+		baseCommit, _ = GetCurrentCommit()
+	}
+
+	for _, doc := range pending {
+		content := doc.Content
+		if doc.Source != "" {
+			content = rewriteImportLinks(content, doc.Source, sources)
+		}
+		if options.DetectSnippets {
+			var count int
+			content, count = replaceFencedSnippets(content, repoFiles, baseCommit)
+			result.Snippets += count
+		}
+		if err := UpdateDoc(doc.FilePath, content); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// ImportNode is a doc for ImportTree to create, with its content and the docs nested under it
+type ImportNode struct {
+	Title    string
+	Content  string // full content.mdx; empty gives the doc just a heading
+	Source   string // reported back in ImportedDoc.Source
+	Children []ImportNode
+}
+
+// ImportTree creates docs under parentPath through CreateDoc, keeping the order of nodes
+// It is the building block for importers that generate content rather than read markdown files
+func ImportTree(parentPath string, nodes []ImportNode) ([]ImportedDoc, error) {
+	if parentPath != "" {
+		parentFullPath, err := getDoclificPath(parentPath)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := os.Stat(parentFullPath); err != nil {
+			return nil, fmt.Errorf("parent doc not found: %s", parentPath)
+		}
+	}
+
+	docs := []ImportedDoc{}
+	var create func(nodes []ImportNode, parentPath string) error
+	create = func(nodes []ImportNode, parentPath string) error {
+		parentDir, err := getDoclificPath(parentPath)
+		if err != nil {
+			return err
+		}
+
+		for _, node := range nodes {
+			response, err := CreateDoc(parentPath, node.Title, nil, "")
+			if err != nil {
+				return err
+			}
+			if err := reorderDocInDir(parentDir, filepath.Base(response.FilePath), "", ""); err != nil {
+				return err
+			}
+			if node.Content != "" {
+				if err := UpdateDoc(response.URL, node.Content); err != nil {
+					return err
+				}
+			}
+			docs = append(docs, ImportedDoc{Source: node.Source, FilePath: response.URL, Title: node.Title})
+
+			if err := create(node.Children, response.URL); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := create(nodes, parentPath); err != nil {
+		return nil, err
+	}
+
+	return docs, nil
+}
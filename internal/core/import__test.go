@@ -0,0 +1,139 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestImportMarkdown(t *testing.T) {
+	originalDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get current directory: %v", err)
+	}
+
+	tmpDir := t.TempDir()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("failed to change directory: %v", err)
+	}
+	defer os.Chdir(originalDir)
+
+	files := map[string]string{
+		"main.go":                        "package main\n\nfunc main() {\n\tprintln(\"hi\")\n}\n",
+		"docs/README.md":                 "# Project Docs\n\nStart with [setup](01-setup.md#install).\n",
+		"docs/01-setup.md":               "---\ntitle: Setup Guide\n---\nInstall it.\n\n```go\nfunc main() {\n\tprintln(\"hi\")\n}\n```\n",
+		"docs/10-faq.md":                 "Questions.\n",
+		"docs/2-architecture.md":         "# Architecture\n\nOverview.\n",
+		"docs/2-architecture/backend.md": "# Backend\n\nSee [faq](../10-faq.md).\n",
+		"docs/guides/deploy.mdx":         "## Deploying\n",
+		"docs/guides/notes.txt":          "ignored",
+	}
+	for path, content := range files {
+		fullPath := filepath.Join(tmpDir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", path, err)
+		}
+	}
+
+	result, err := ImportMarkdown(ImportOptions{SourceDir: "docs", DetectSnippets: true})
+	if err != nil {
+		t.Fatalf("ImportMarkdown() error = %v", err)
+	}
+	if result.Snippets != 1 {
+		t.Errorf("ImportMarkdown() converted %d snippets, want 1", result.Snippets)
+	}
+
+	docs, err := GetDocs()
+	if err != nil {
+		t.Fatalf("GetDocs() error = %v", err)
+	}
+
+	// Files keep their natural order; folders become parent docs
+	var titles []string
+	WalkDocs(docs, func(filePath string, doc FolderStructure) error {
+		titles = append(titles, strings.Repeat("-", strings.Count(filePath, "/"))+doc.Title)
+		return nil
+	})
+	want := []string{"Project Docs", "Setup Guide", "Architecture", "-Backend", "Faq", "Guides", "-Deploying"}
+	if strings.Join(titles, ",") != strings.Join(want, ",") {
+		t.Errorf("imported tree = %v, want %v", titles, want)
+	}
+
+	byTitle := map[string]ImportedDoc{}
+	for _, doc := range result.Docs {
+		byTitle[doc.Title] = doc
+	}
+
+	readme, _ := GetDoc(byTitle["Project Docs"].FilePath)
+	if want := "[setup](doc:" + filepath.Base(byTitle["Setup Guide"].FilePath) + "#install)"; !strings.Contains(readme, want) {
+		t.Errorf("README link not rewritten, want %q in:\n%s", want, readme)
+	}
+
+	setup, _ := GetDoc(byTitle["Setup Guide"].FilePath)
+	if !strings.HasPrefix(setup, "# Setup Guide\n\nInstall it.") {
+		t.Errorf("setup doc did not get a title heading without front matter:\n%s", setup)
+	}
+	if !strings.Contains(setup, `<CodebaseSnippet filePath="main.go" lineStart="3" lineEnd="5"`) || strings.Contains(setup, "```") {
+		t.Errorf("fenced block matching main.go was not turned into a CodebaseSnippet:\n%s", setup)
+	}
+
+	guides, _ := GetDoc(byTitle["Guides"].FilePath)
+	if guides != "# Guides\n\n" {
+		t.Errorf("folder without index content = %q, want a title heading", guides)
+	}
+
+	if _, err := ImportMarkdown(ImportOptions{SourceDir: "missing"}); err == nil {
+		t.Error("ImportMarkdown() with a missing directory should fail")
+	}
+}
+
+func TestImportTree(t *testing.T) {
+	originalDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get current directory: %v", err)
+	}
+
+	tmpDir := t.TempDir()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("failed to change directory: %v", err)
+	}
+	defer os.Chdir(originalDir)
+
+	docs, err := ImportTree("", []ImportNode{{
+		Title:   "API",
+		Content: "# API\n\nOverview.\n",
+		Source:  "openapi.yaml",
+		Children: []ImportNode{
+			{Title: "Users", Content: "# Users\n"},
+			{Title: "Orders"},
+		},
+	}})
+	if err != nil {
+		t.Fatalf("ImportTree() error = %v", err)
+	}
+	if len(docs) != 3 || docs[0].Source != "openapi.yaml" {
+		t.Fatalf("ImportTree() = %+v", docs)
+	}
+
+	tree, err := GetDocs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tree) != 1 || len(tree[0].Children) != 2 || tree[0].Children[0].Title != "Users" || tree[0].Children[1].Title != "Orders" {
+		t.Errorf("ImportTree() did not keep the node order: %+v", tree)
+	}
+
+	api, _ := GetDoc(docs[0].FilePath)
+	orders, _ := GetDoc(docs[2].FilePath)
+	if api != "# API\n\nOverview.\n" || orders != "# Orders\n" {
+		t.Errorf("ImportTree() content = %q, %q", api, orders)
+	}
+
+	if _, err := ImportTree("missing", nil); err == nil {
+		t.Error("ImportTree() under a missing parent should fail")
+	}
+}
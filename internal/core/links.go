@@ -0,0 +1,177 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// DocLinkScheme prefixes link targets that point at another doc, e.g. [Backend](doc:architecture/backend)
+// The target may be a doc's folder UUID, which survives moves, or a slug path
+const DocLinkScheme = "doc:"
+
+// ErrBrokenDocLink is returned when a doc: link target does not resolve to an existing doc
+var ErrBrokenDocLink = errors.New("broken doc link")
+
+// docLinkRegex matches markdown links using the doc: scheme
+var docLinkRegex = regexp.MustCompile(`\[([^\]]*)\]\(doc:([^)\s]+)\)`)
+
+// DocLink is a single cross-doc link found in a doc's content
+type DocLink struct {
+	Source      string `json:"source"` // folder path of the doc containing the link
+	SourceTitle string `json:"sourceTitle"`
+	Target      string `json:"target"` // raw link target after "doc:"
+	Text        string `json:"text"`
+	Line        int    `json:"line"`
+	Resolved    string `json:"resolved,omitempty"` // folder path of the linked doc, empty if broken
+	Broken      bool   `json:"broken"`
+	Reason      string `json:"reason,omitempty"`
+}
+
+// LinkGraph holds every cross-doc link in the doclific tree
+type LinkGraph struct {
+	Links []DocLink `json:"links"`
+}
+
+// parseDocLinks extracts doc: links from MDX content, skipping fenced code blocks
+func parseDocLinks(content string) []DocLink {
+	var links []DocLink
+	inFence := false
+
+	for i, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+
+		for _, match := range docLinkRegex.FindAllStringSubmatch(line, -1) {
+			links = append(links, DocLink{
+				Text:   match[1],
+				Target: match[2],
+				Line:   i + 1,
+			})
+		}
+	}
+
+	return links
+}
+
+// resolveDocLinkTarget resolves a link target to a folder path
+// byName maps every folder name in the tree to its folder path
+func resolveDocLinkTarget(target string, byName map[string]string) (string, string) {
+	target = strings.Trim(strings.SplitN(target, "#", 2)[0], "/")
+	if target == "" {
+		return "", "empty link target"
+	}
+
+	// A bare folder name (UUID) is found wherever the doc currently lives
+	if !strings.Contains(target, "/") {
+		if filePath, ok := byName[target]; ok {
+			return filePath, ""
+		}
+	}
+
+	if resolved, err := ResolveSlugPath(target); err == nil {
+		return resolved.FilePath, ""
+	}
+
+	// A folder path whose last segment still exists elsewhere means the doc was moved
+	if filePath, ok := byName[path.Base(target)]; ok {
+		return "", fmt.Sprintf("doc was moved to %s", filePath)
+	}
+
+	return "", "doc not found"
+}
+
+// docFolderPaths maps every folder name in the tree to its folder path
+func docFolderPaths(docs []FolderStructure) map[string]string {
+	byName := map[string]string{}
+	WalkDocs(docs, func(filePath string, doc FolderStructure) error {
+		byName[doc.Name] = filePath
+		return nil
+	})
+	return byName
+}
+
+// ResolveDocLink resolves a doc: link target to a folder path, the same way BuildLinkGraph does
+func ResolveDocLink(target string) (string, error) {
+	docs, err := GetDocs()
+	if err != nil {
+		return "", err
+	}
+
+	filePath, reason := resolveDocLinkTarget(strings.TrimPrefix(target, DocLinkScheme), docFolderPaths(docs))
+	if filePath == "" {
+		return "", fmt.Errorf("%w: %s", ErrBrokenDocLink, reason)
+	}
+	return filePath, nil
+}
+
+// BuildLinkGraph reads every doc and resolves its doc: links
+func BuildLinkGraph() (*LinkGraph, error) {
+	docs, err := GetDocs()
+	if err != nil {
+		return nil, err
+	}
+
+	byName := docFolderPaths(docs)
+
+	graph := &LinkGraph{Links: []DocLink{}}
+	err = WalkDocs(docs, func(filePath string, doc FolderStructure) error {
+		content, err := GetDoc(filePath)
+		if err != nil {
+			return err
+		}
+
+		for _, link := range parseDocLinks(content) {
+			link.Source = filePath
+			link.SourceTitle = doc.Title
+			link.Resolved, link.Reason = resolveDocLinkTarget(link.Target, byName)
+			link.Broken = link.Resolved == ""
+			graph.Links = append(graph.Links, link)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return graph, nil
+}
+
+// Backlinks returns the links pointing at the doc with the given folder path
+func (g *LinkGraph) Backlinks(filePath string) []DocLink {
+	filePath = strings.Trim(filePath, "/")
+	backlinks := []DocLink{}
+	for _, link := range g.Links {
+		if link.Resolved == filePath {
+			backlinks = append(backlinks, link)
+		}
+	}
+	return backlinks
+}
+
+// BrokenLinks returns the links that do not resolve to an existing doc
+func (g *LinkGraph) BrokenLinks() []DocLink {
+	broken := []DocLink{}
+	for _, link := range g.Links {
+		if link.Broken {
+			broken = append(broken, link)
+		}
+	}
+	return broken
+}
+
+// GetBacklinks returns every link in the tree that points at the given doc
+func GetBacklinks(filePath string) ([]DocLink, error) {
+	graph, err := BuildLinkGraph()
+	if err != nil {
+		return nil, err
+	}
+	return graph.Backlinks(filePath), nil
+}
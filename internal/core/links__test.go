@@ -0,0 +1,99 @@
+package core
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseDocLinks(t *testing.T) {
+	content := "# Title\n\nSee [Backend](doc:arch/backend) and [API](doc:1234#auth).\n\n```md\n[Ignored](doc:nope)\n```\n[External](https://example.com)\n"
+
+	links := parseDocLinks(content)
+	if len(links) != 2 {
+		t.Fatalf("parseDocLinks() returned %d links, want 2", len(links))
+	}
+	if links[0].Text != "Backend" || links[0].Target != "arch/backend" || links[0].Line != 3 {
+		t.Errorf("parseDocLinks()[0] = %+v", links[0])
+	}
+	if links[1].Target != "1234#auth" {
+		t.Errorf("parseDocLinks()[1].Target = %q, want %q", links[1].Target, "1234#auth")
+	}
+}
+
+func TestBuildLinkGraph(t *testing.T) {
+	originalDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get current directory: %v", err)
+	}
+
+	tmpDir := t.TempDir()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("failed to change directory: %v", err)
+	}
+	defer os.Chdir(originalDir)
+
+	doclificDir := filepath.Join(tmpDir, "doclific")
+	docs := map[string]struct {
+		config  string
+		content string
+	}{
+		"arch":         {`{"title": "Architecture", "slug": "architecture"}`, "# Architecture\n"},
+		"arch/backend": {`{"title": "Backend", "slug": "backend"}`, "# Backend\n"},
+		"guide":        {`{"title": "Guide", "order": 1}`, "[by uuid](doc:backend-uuid)\n[by slug](doc:/architecture/backend)\n[gone](doc:deleted-uuid)\n[moved](doc:guide/backend-uuid)\n"},
+		"backend-uuid": {`{"title": "Moved Doc", "order": 2}`, "[back](doc:arch)\n"},
+	}
+	for path, doc := range docs {
+		dir := filepath.Join(doclificDir, filepath.FromSlash(path))
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", path, err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(doc.config), 0644); err != nil {
+			t.Fatalf("failed to write config.json: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "content.mdx"), []byte(doc.content), 0644); err != nil {
+			t.Fatalf("failed to write content.mdx: %v", err)
+		}
+	}
+
+	graph, err := BuildLinkGraph()
+	if err != nil {
+		t.Fatalf("BuildLinkGraph() error = %v", err)
+	}
+	if len(graph.Links) != 5 {
+		t.Fatalf("BuildLinkGraph() found %d links, want 5", len(graph.Links))
+	}
+
+	broken := graph.BrokenLinks()
+	if len(broken) != 2 {
+		t.Fatalf("BrokenLinks() returned %d links, want 2", len(broken))
+	}
+	if broken[0].Target != "deleted-uuid" || broken[0].Reason != "doc not found" {
+		t.Errorf("BrokenLinks()[0] = %+v, want deleted doc", broken[0])
+	}
+	if broken[1].Target != "guide/backend-uuid" || broken[1].Reason != "doc was moved to backend-uuid" {
+		t.Errorf("BrokenLinks()[1] = %+v, want moved doc", broken[1])
+	}
+
+	backlinks := graph.Backlinks("arch/backend")
+	if len(backlinks) != 1 || backlinks[0].Source != "guide" {
+		t.Errorf("Backlinks(arch/backend) = %+v, want one link from guide", backlinks)
+	}
+
+	if filePath, err := ResolveDocLink("doc:backend-uuid"); err != nil || filePath != "backend-uuid" {
+		t.Errorf("ResolveDocLink(doc:backend-uuid) = %q, %v, want backend-uuid", filePath, err)
+	}
+	if _, err := ResolveDocLink("guide/backend-uuid"); !errors.Is(err, ErrBrokenDocLink) || !strings.Contains(err.Error(), "moved") {
+		t.Errorf("ResolveDocLink(guide/backend-uuid) error = %v, want a moved doc", err)
+	}
+
+	backlinks, err = GetBacklinks("/arch/")
+	if err != nil {
+		t.Fatalf("GetBacklinks() error = %v", err)
+	}
+	if len(backlinks) != 1 || backlinks[0].SourceTitle != "Moved Doc" {
+		t.Errorf("GetBacklinks(arch) = %+v, want one link from Moved Doc", backlinks)
+	}
+}
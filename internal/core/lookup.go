@@ -0,0 +1,189 @@
+package core
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// TitleResolution is the doc a title path resolved to
+type TitleResolution struct {
+	FilePath  string `json:"filePath"`  // folder path relative to doclific
+	TitlePath string `json:"titlePath"` // titles from the root down to the doc, joined by "/"
+}
+
+// AmbiguousTitleError is returned when a title path matches more than one doc
+type AmbiguousTitleError struct {
+	TitlePath string
+	Matches   []TitleResolution
+}
+
+func (e *AmbiguousTitleError) Error() string {
+	paths := make([]string, len(e.Matches))
+	for i, match := range e.Matches {
+		paths[i] = match.FilePath
+	}
+	return fmt.Sprintf("%q matches %d docs (%s); use a folder path instead", e.TitlePath, len(e.Matches), strings.Join(paths, ", "))
+}
+
+// splitTitlePath splits a title path on "/", keeping "\/" as a literal slash inside a title
+func splitTitlePath(titlePath string) []string {
+	var segments []string
+	var current strings.Builder
+	for i := 0; i < len(titlePath); i++ {
+		switch {
+		case titlePath[i] == '\\' && i+1 < len(titlePath) && titlePath[i+1] == '/':
+			current.WriteByte('/')
+			i++
+		case titlePath[i] == '/':
+			segments = append(segments, strings.TrimSpace(current.String()))
+			current.Reset()
+		default:
+			current.WriteByte(titlePath[i])
+		}
+	}
+	segments = append(segments, strings.TrimSpace(current.String()))
+
+	result := segments[:0]
+	for _, segment := range segments {
+		if segment != "" {
+			result = append(result, segment)
+		}
+	}
+	return result
+}
+
+// escapeTitle escapes slashes in a title for use in a title path
+func escapeTitle(title string) string {
+	return strings.ReplaceAll(title, "/", "\\/")
+}
+
+// ResolveTitlePath resolves a title path such as "Architecture Overview/Backend Development" to a doc
+// Titles are compared ignoring case. The path is matched from the root first; if nothing matches
+// there, it may also name a doc deeper in the tree by its last titles. An *AmbiguousTitleError
+// is returned when sibling titles collide
+func ResolveTitlePath(titlePath string) (*TitleResolution, error) {
+	segments := splitTitlePath(titlePath)
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("title path is required")
+	}
+
+	docs, err := GetDocs()
+	if err != nil {
+		return nil, err
+	}
+
+	// Titles of every doc from the root down, keyed by folder path
+	titlePaths := map[string][]string{}
+	var anchored, suffix []TitleResolution
+	WalkDocs(docs, func(filePath string, doc FolderStructure) error {
+		titles := []string{strings.TrimSpace(doc.Title)}
+		if parent := filepath.ToSlash(filepath.Dir(filePath)); parent != "." {
+			titles = append(slices.Clone(titlePaths[parent]), titles...)
+		}
+		titlePaths[filePath] = titles
+
+		if len(titles) < len(segments) {
+			return nil
+		}
+		tail := titles[len(titles)-len(segments):]
+		for i, segment := range segments {
+			if !strings.EqualFold(tail[i], segment) {
+				return nil
+			}
+		}
+
+		escaped := make([]string, len(titles))
+		for i, title := range titles {
+			escaped[i] = escapeTitle(title)
+		}
+		match := TitleResolution{FilePath: filePath, TitlePath: strings.Join(escaped, "/")}
+		if len(titles) == len(segments) {
+			anchored = append(anchored, match)
+		} else {
+			suffix = append(suffix, match)
+		}
+		return nil
+	})
+
+	matches := anchored
+	if len(matches) == 0 {
+		matches = suffix
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no doc found for title path %q", titlePath)
+	case 1:
+		return &matches[0], nil
+	default:
+		return nil, &AmbiguousTitleError{TitlePath: titlePath, Matches: matches}
+	}
+}
+
+// docFolderPath cleans ref and reports whether it is the folder path of a doc: a path inside
+// doclific whose every segment is a visible doc folder with a config.json
+func docFolderPath(doclificPath string, ref string) (string, bool) {
+	cleaned := path.Clean(ref)
+	if cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") || path.IsAbs(cleaned) {
+		return "", false
+	}
+
+	dirPath := doclificPath
+	for _, segment := range strings.Split(cleaned, "/") {
+		dirPath = filepath.Join(dirPath, segment)
+		info, err := os.Lstat(dirPath)
+		if err != nil || !isDocDir(fs.FileInfoToDirEntry(info)) {
+			return "", false
+		}
+		if _, err := os.Stat(filepath.Join(dirPath, "config.json")); err != nil {
+			return "", false
+		}
+	}
+	return cleaned, true
+}
+
+// ResolveDocRef resolves a reference to a doc's folder path relative to doclific
+// The reference may be a folder path, a doc's UUID, a slug path or a title path; empty means the root
+func ResolveDocRef(ref string) (string, error) {
+	ref = strings.Trim(strings.TrimPrefix(filepath.ToSlash(ref), "doclific/"), "/")
+	if ref == "" || ref == "doclific" {
+		return "", nil
+	}
+
+	doclificPath, err := getDoclificPath("")
+	if err != nil {
+		return "", err
+	}
+
+	// A folder path, relative to doclific
+	if filePath, ok := docFolderPath(doclificPath, ref); ok {
+		return filePath, nil
+	}
+
+	// A bare UUID anywhere in the tree
+	if !strings.Contains(ref, "/") {
+		parentPath, found, err := findDocParentPath(doclificPath, ref, "")
+		if err != nil {
+			return "", err
+		}
+		if found {
+			if filePath, ok := docFolderPath(doclificPath, path.Join(filepath.ToSlash(parentPath), ref)); ok {
+				return filePath, nil
+			}
+		}
+	}
+
+	if resolution, err := ResolveSlugPath(ref); err == nil {
+		return resolution.FilePath, nil
+	}
+
+	resolution, err := ResolveTitlePath(ref)
+	if err != nil {
+		return "", err
+	}
+	return resolution.FilePath, nil
+}
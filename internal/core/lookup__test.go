@@ -0,0 +1,151 @@
+package core
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolveDocRef(t *testing.T) {
+	originalDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get current directory: %v", err)
+	}
+
+	tmpDir := t.TempDir()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("failed to change directory: %v", err)
+	}
+	defer os.Chdir(originalDir)
+
+	configs := map[string]string{
+		"guide":           `{"title": "Getting Started", "slug": "start", "order": 0}`,
+		"guide/api-uuid":  `{"title": "API Reference", "order": 0}`,
+		"other":           `{"title": "Other", "order": 1}`,
+		"other/notes-one": `{"title": "Notes", "order": 0}`,
+		"other/notes-two": `{"title": "notes", "order": 1}`,
+	}
+	for dir, config := range configs {
+		fullPath := filepath.Join(tmpDir, "doclific", filepath.FromSlash(dir))
+		if err := os.MkdirAll(fullPath, 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", dir, err)
+		}
+		if err := os.WriteFile(filepath.Join(fullPath, "config.json"), []byte(config), 0644); err != nil {
+			t.Fatalf("failed to write config for %s: %v", dir, err)
+		}
+	}
+
+	tests := []struct {
+		ref  string
+		want string
+	}{
+		{"", ""},
+		{"doclific/", ""},
+		{"doclific/guide/api-uuid/", "guide/api-uuid"},
+		{"api-uuid", "guide/api-uuid"},
+		{"start", "guide"},
+		{"getting started", "guide"},
+		{"API Reference", "guide/api-uuid"},
+		{"guide/./api-uuid", "guide/api-uuid"},
+	}
+	for _, tt := range tests {
+		got, err := ResolveDocRef(tt.ref)
+		if err != nil {
+			t.Errorf("ResolveDocRef(%q) error = %v", tt.ref, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ResolveDocRef(%q) = %q, want %q", tt.ref, got, tt.want)
+		}
+	}
+
+	if _, err := ResolveDocRef("Notes"); err == nil || !strings.Contains(err.Error(), "matches 2 docs") {
+		t.Errorf("ResolveDocRef(Notes) error = %v, want an ambiguity error", err)
+	}
+	if _, err := ResolveDocRef("Missing"); err == nil {
+		t.Error("ResolveDocRef(Missing) should fail")
+	}
+
+	// Folders that are not docs must not resolve as folder paths
+	for _, dir := range []string{".templates/blank", ".environments", "no-config", "guide/no-config"} {
+		fullPath := filepath.Join(tmpDir, "doclific", filepath.FromSlash(dir))
+		if err := os.MkdirAll(fullPath, 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", dir, err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "doclific", ".templates", "blank", "config.json"), []byte(`{"title": "Blank"}`), 0644); err != nil {
+		t.Fatalf("failed to write template config: %v", err)
+	}
+	for _, ref := range []string{".templates", ".templates/blank", ".environments", "no-config", "guide/no-config", "..", "../..", "guide/../..", "/etc"} {
+		if got, err := ResolveDocRef(ref); err == nil {
+			t.Errorf("ResolveDocRef(%q) = %q, want an error", ref, got)
+		}
+	}
+}
+
+func TestResolveTitlePath(t *testing.T) {
+	originalDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get current directory: %v", err)
+	}
+
+	tmpDir := t.TempDir()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("failed to change directory: %v", err)
+	}
+	defer os.Chdir(originalDir)
+
+	configs := map[string]string{
+		"arch":              `{"title": "Architecture Overview", "order": 0}`,
+		"arch/backend":      `{"title": "Backend Development", "order": 0}`,
+		"arch/io":           `{"title": "Input/Output", "order": 1}`,
+		"guides":            `{"title": "Guides", "order": 1}`,
+		"guides/backend":    `{"title": "Backend Development", "order": 0}`,
+		"guides/setup":      `{"title": "Setup", "order": 1}`,
+		"guides/setup/deep": `{"title": "Setup", "order": 0}`,
+	}
+	for dir, config := range configs {
+		fullPath := filepath.Join(tmpDir, "doclific", filepath.FromSlash(dir))
+		if err := os.MkdirAll(fullPath, 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", dir, err)
+		}
+		if err := os.WriteFile(filepath.Join(fullPath, "config.json"), []byte(config), 0644); err != nil {
+			t.Fatalf("failed to write config for %s: %v", dir, err)
+		}
+	}
+
+	tests := []struct {
+		titlePath     string
+		wantFilePath  string
+		wantTitlePath string
+	}{
+		{"Architecture Overview/Backend Development", "arch/backend", "Architecture Overview/Backend Development"},
+		{"/architecture overview/ backend development /", "arch/backend", "Architecture Overview/Backend Development"},
+		{`Architecture Overview/Input\/Output`, "arch/io", `Architecture Overview/Input\/Output`},
+		{"Guides/Setup", "guides/setup", "Guides/Setup"},
+		{"Setup/Setup", "guides/setup/deep", "Guides/Setup/Setup"},
+	}
+	for _, tt := range tests {
+		got, err := ResolveTitlePath(tt.titlePath)
+		if err != nil {
+			t.Errorf("ResolveTitlePath(%q) error = %v", tt.titlePath, err)
+			continue
+		}
+		if got.FilePath != tt.wantFilePath || got.TitlePath != tt.wantTitlePath {
+			t.Errorf("ResolveTitlePath(%q) = %+v, want %s (%s)", tt.titlePath, got, tt.wantFilePath, tt.wantTitlePath)
+		}
+	}
+
+	// The same title under different parents is ambiguous without the parent's title
+	_, err = ResolveTitlePath("Backend Development")
+	var ambiguous *AmbiguousTitleError
+	if !errors.As(err, &ambiguous) || len(ambiguous.Matches) != 2 {
+		t.Errorf("ResolveTitlePath(Backend Development) error = %v, want an ambiguity error with 2 matches", err)
+	}
+
+	if _, err := ResolveTitlePath("Guides/Missing"); err == nil {
+		t.Error("ResolveTitlePath(Guides/Missing) should fail")
+	}
+}
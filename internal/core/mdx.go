@@ -0,0 +1,163 @@
+package core
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// MDX component names used by the editor
+const (
+	ComponentCodebaseSnippet = "CodebaseSnippet"
+	ComponentERD             = "ERD"
+	ComponentHttpRequest     = "HttpRequest"
+)
+
+// MDXComponent is a JSX element found in a doc's content.mdx
+type MDXComponent struct {
+	Name       string            `json:"name"`
+	Attributes map[string]string `json:"attributes"` // values are HTML-unescaped
+	Line       int               `json:"line"`       // 1-indexed line of the opening tag
+	Start      int               `json:"start"`      // byte offset of the opening "<"
+	End        int               `json:"end"`        // byte offset just past the closing tag
+}
+
+// ParseMDXComponents finds every <name ...> element in content, in document order
+// Both self-closing tags and tags followed by a matching </name> are supported
+func ParseMDXComponents(content string, name string) []MDXComponent {
+	var components []MDXComponent
+	openTag := "<" + name
+	closeTag := "</" + name + ">"
+
+	offset := 0
+	for {
+		idx := strings.Index(content[offset:], openTag)
+		if idx == -1 {
+			break
+		}
+		start := offset + idx
+		pos := start + len(openTag)
+
+		// Make sure we matched the whole tag name, not a prefix like <ERDiagram
+		if pos >= len(content) || !isTagBoundary(content[pos]) {
+			offset = pos
+			continue
+		}
+
+		attributes, tagEnd, selfClosing, ok := parseMDXAttributes(content, pos)
+		if !ok {
+			break
+		}
+
+		end := tagEnd
+		if !selfClosing {
+			if closeIdx := strings.Index(content[tagEnd:], closeTag); closeIdx != -1 {
+				end = tagEnd + closeIdx + len(closeTag)
+			}
+		}
+
+		components = append(components, MDXComponent{
+			Name:       name,
+			Attributes: attributes,
+			Line:       strings.Count(content[:start], "\n") + 1,
+			Start:      start,
+			End:        end,
+		})
+		offset = end
+	}
+
+	return components
+}
+
+// isTagBoundary reports whether c can follow a JSX tag name
+func isTagBoundary(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '>' || c == '/'
+}
+
+// parseMDXAttributes parses attributes starting at pos until the end of the opening tag
+// Returns the attributes, the offset just past the tag, whether it was self-closing and whether parsing succeeded
+func parseMDXAttributes(content string, pos int) (map[string]string, int, bool, bool) {
+	attributes := map[string]string{}
+
+	for pos < len(content) {
+		c := content[pos]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			pos++
+		case c == '>':
+			return attributes, pos + 1, false, true
+		case c == '/' && pos+1 < len(content) && content[pos+1] == '>':
+			return attributes, pos + 2, true, true
+		default:
+			nameStart := pos
+			for pos < len(content) && !isTagBoundary(content[pos]) && content[pos] != '=' {
+				pos++
+			}
+			attrName := content[nameStart:pos]
+			if attrName == "" {
+				return nil, 0, false, false
+			}
+
+			if pos >= len(content) || content[pos] != '=' {
+				attributes[attrName] = "true" // boolean attribute
+				continue
+			}
+			pos++ // skip '='
+
+			if pos >= len(content) {
+				return nil, 0, false, false
+			}
+
+			switch quote := content[pos]; quote {
+			case '"', '\'':
+				valueEnd := strings.IndexByte(content[pos+1:], quote)
+				if valueEnd == -1 {
+					return nil, 0, false, false
+				}
+				attributes[attrName] = html.UnescapeString(content[pos+1 : pos+1+valueEnd])
+				pos += valueEnd + 2
+			case '{':
+				// JSX expression: keep the raw text between balanced braces
+				depth := 0
+				exprStart := pos
+				for pos < len(content) {
+					if content[pos] == '{' {
+						depth++
+					} else if content[pos] == '}' {
+						depth--
+						if depth == 0 {
+							break
+						}
+					}
+					pos++
+				}
+				if pos >= len(content) {
+					return nil, 0, false, false
+				}
+				attributes[attrName] = strings.TrimSpace(content[exprStart+1 : pos])
+				pos++
+			default:
+				return nil, 0, false, false
+			}
+		}
+	}
+
+	return nil, 0, false, false
+}
+
+// EncodeAttribute HTML-encodes a value for an MDX attribute the way he.encode does,
+// so generated blocks match the ones produced by the skills
+func EncodeAttribute(value string) string {
+	var b strings.Builder
+	for _, r := range value {
+		switch {
+		case r == '&' || r == '"' || r == '\'' || r == '<' || r == '>' || r == '`':
+			fmt.Fprintf(&b, "&#x%X;", r)
+		case r > 0x7e:
+			fmt.Fprintf(&b, "&#x%X;", r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,55 @@
+package core
+
+import (
+	"testing"
+)
+
+func TestParseMDXComponents(t *testing.T) {
+	content := `# Doc
+
+<CodebaseSnippet filePath="go.mod" lineStart="1" lineEnd="5" baseCommit="abc" needsReview="false">
+</CodebaseSnippet>
+
+<ERDiagram tables="ignored" />
+
+<HttpRequest method="POST" url="https://example.com" headers="[{&#x22;key&#x22;:&#x22;Accept&#x22;}]" auth='{"type":"none"}' />
+
+<CodebaseSnippet
+  filePath="main.go"
+  lineStart={10}
+  collapsed
+/>
+`
+
+	snippets := ParseMDXComponents(content, ComponentCodebaseSnippet)
+	if len(snippets) != 2 {
+		t.Fatalf("ParseMDXComponents() returned %d snippets, want 2", len(snippets))
+	}
+	if snippets[0].Attributes["filePath"] != "go.mod" || snippets[0].Attributes["baseCommit"] != "abc" {
+		t.Errorf("snippets[0].Attributes = %v", snippets[0].Attributes)
+	}
+	if snippets[0].Line != 3 {
+		t.Errorf("snippets[0].Line = %d, want 3", snippets[0].Line)
+	}
+	if got := content[snippets[0].Start:snippets[0].End]; got[len(got)-len("</CodebaseSnippet>"):] != "</CodebaseSnippet>" {
+		t.Errorf("snippets[0] span = %q, want it to include the closing tag", got)
+	}
+	if snippets[1].Attributes["lineStart"] != "10" || snippets[1].Attributes["collapsed"] != "true" {
+		t.Errorf("snippets[1].Attributes = %v", snippets[1].Attributes)
+	}
+
+	if erds := ParseMDXComponents(content, ComponentERD); len(erds) != 0 {
+		t.Errorf("ParseMDXComponents(ERD) returned %d components, want 0", len(erds))
+	}
+
+	requests := ParseMDXComponents(content, ComponentHttpRequest)
+	if len(requests) != 1 {
+		t.Fatalf("ParseMDXComponents(HttpRequest) returned %d components, want 1", len(requests))
+	}
+	if requests[0].Attributes["headers"] != `[{"key":"Accept"}]` {
+		t.Errorf("headers = %q, want HTML-unescaped JSON", requests[0].Attributes["headers"])
+	}
+	if requests[0].Attributes["auth"] != `{"type":"none"}` {
+		t.Errorf("auth = %q, want single-quoted value", requests[0].Attributes["auth"])
+	}
+}
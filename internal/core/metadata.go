@@ -0,0 +1,146 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Doc statuses
+const (
+	DocStatusDraft      = "draft"
+	DocStatusPublished  = "published"
+	DocStatusDeprecated = "deprecated"
+)
+
+// reviewedAtLayout is the date format used for DocMetadata.ReviewedAt
+const reviewedAtLayout = "2006-01-02"
+
+// ErrInvalidMetadata is returned by UpdateDocMetadata when the status or review date is not valid
+var ErrInvalidMetadata = errors.New("invalid doc metadata")
+
+// DocMetadata holds ownership and review information stored in a doc's config.json
+type DocMetadata struct {
+	Tags       []string `json:"tags,omitempty"`
+	Owners     []string `json:"owners,omitempty"` // git emails
+	Status     string   `json:"status,omitempty"` // "draft", "published" or "deprecated"
+	ReviewedAt string   `json:"reviewedAt,omitempty"`
+}
+
+// DocFilter selects docs by metadata; empty fields match every doc
+type DocFilter struct {
+	Tag            string `json:"tag"`
+	Owner          string `json:"owner"` // "me" matches the current git email
+	Status         string `json:"status"`
+	ReviewedBefore string `json:"reviewedBefore"` // also matches docs that were never reviewed
+}
+
+// DocSummary is a flat view of a doc returned by FilterDocs
+type DocSummary struct {
+	FilePath string  `json:"filePath"`
+	Title    string  `json:"title"`
+	Icon     *string `json:"icon,omitempty"`
+	DocMetadata
+}
+
+// validate checks the status and review date of the metadata
+func (m DocMetadata) validate() error {
+	switch m.Status {
+	case "", DocStatusDraft, DocStatusPublished, DocStatusDeprecated:
+	default:
+		return fmt.Errorf("%w: status %q must be draft, published or deprecated", ErrInvalidMetadata, m.Status)
+	}
+
+	if m.ReviewedAt != "" {
+		if _, err := time.Parse(reviewedAtLayout, m.ReviewedAt); err != nil {
+			return fmt.Errorf("%w: reviewedAt %q must be a YYYY-MM-DD date", ErrInvalidMetadata, m.ReviewedAt)
+		}
+	}
+
+	return nil
+}
+
+// UpdateDocMetadata replaces the tags, owners, status and review date of a doc
+func UpdateDocMetadata(filePath string, metadata DocMetadata) error {
+	if err := metadata.validate(); err != nil {
+		return err
+	}
+
+	fullPath, err := getDoclificPath(filePath)
+	if err != nil {
+		return err
+	}
+
+	config, err := readConfig(fullPath)
+	if err != nil {
+		return err
+	}
+
+	config.DocMetadata = metadata
+	return writeConfig(fullPath, config)
+}
+
+// matches reports whether a doc's metadata passes the filter
+func (f DocFilter) matches(metadata DocMetadata) bool {
+	if f.Tag != "" && !slices.ContainsFunc(metadata.Tags, func(tag string) bool {
+		return strings.EqualFold(tag, f.Tag)
+	}) {
+		return false
+	}
+
+	if f.Owner != "" && !slices.ContainsFunc(metadata.Owners, func(owner string) bool {
+		return strings.EqualFold(owner, f.Owner)
+	}) {
+		return false
+	}
+
+	if f.Status != "" && metadata.Status != f.Status {
+		return false
+	}
+
+	// Dates use a fixed-width layout, so string comparison orders them correctly
+	if f.ReviewedBefore != "" && metadata.ReviewedAt != "" && metadata.ReviewedAt >= f.ReviewedBefore {
+		return false
+	}
+
+	return true
+}
+
+// FilterDocs returns every doc whose metadata matches the filter, in sidebar order
+func FilterDocs(filter DocFilter) ([]DocSummary, error) {
+	if filter.Owner == "me" {
+		email, err := GetGitEmail()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get git email for owner=me: %w", err)
+		}
+		filter.Owner = email
+	}
+
+	if filter.ReviewedBefore != "" {
+		if _, err := time.Parse(reviewedAtLayout, filter.ReviewedBefore); err != nil {
+			return nil, fmt.Errorf("invalid reviewedBefore %q: must be a YYYY-MM-DD date", filter.ReviewedBefore)
+		}
+	}
+
+	docs, err := GetDocs()
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := []DocSummary{}
+	WalkDocs(docs, func(filePath string, doc FolderStructure) error {
+		if filter.matches(doc.DocMetadata) {
+			summaries = append(summaries, DocSummary{
+				FilePath:    filePath,
+				Title:       doc.Title,
+				Icon:        doc.Icon,
+				DocMetadata: doc.DocMetadata,
+			})
+		}
+		return nil
+	})
+
+	return summaries, nil
+}
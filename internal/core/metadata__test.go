@@ -0,0 +1,95 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestUpdateDocMetadataAndFilter(t *testing.T) {
+	originalDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get current directory: %v", err)
+	}
+
+	tmpDir := t.TempDir()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("failed to change directory: %v", err)
+	}
+	defer os.Chdir(originalDir)
+
+	doclificDir := filepath.Join(tmpDir, "doclific")
+	for i, name := range []string{"runbook", "adr", "legacy"} {
+		dir := filepath.Join(doclificDir, name)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", name, err)
+		}
+		config := []byte(fmt.Sprintf(`{"title": %q, "order": %d}`, name, i))
+		if err := os.WriteFile(filepath.Join(dir, "config.json"), config, 0644); err != nil {
+			t.Fatalf("failed to write config.json: %v", err)
+		}
+	}
+
+	updates := map[string]DocMetadata{
+		"runbook": {Tags: []string{"ops"}, Owners: []string{"ana@example.com"}, Status: DocStatusPublished, ReviewedAt: "2026-03-01"},
+		"adr":     {Tags: []string{"Architecture", "ops"}, Owners: []string{"bo@example.com"}, Status: DocStatusDraft},
+		"legacy":  {Status: DocStatusDeprecated, ReviewedAt: "2024-05-10"},
+	}
+	for name, metadata := range updates {
+		if err := UpdateDocMetadata(name, metadata); err != nil {
+			t.Fatalf("UpdateDocMetadata(%s) error = %v", name, err)
+		}
+	}
+
+	if err := UpdateDocMetadata("adr", DocMetadata{Status: "archived"}); !errors.Is(err, ErrInvalidMetadata) {
+		t.Error("UpdateDocMetadata() with invalid status should return error")
+	}
+	if err := UpdateDocMetadata("adr", DocMetadata{ReviewedAt: "last week"}); !errors.Is(err, ErrInvalidMetadata) {
+		t.Error("UpdateDocMetadata() with invalid date should return error")
+	}
+
+	// Metadata is returned with the tree
+	docs, err := GetDocs()
+	if err != nil {
+		t.Fatalf("GetDocs() error = %v", err)
+	}
+	if docs[0].Title != "runbook" || docs[0].Status != DocStatusPublished || docs[0].ReviewedAt != "2026-03-01" {
+		t.Errorf("GetDocs()[0] = %+v, want runbook metadata", docs[0])
+	}
+
+	tests := []struct {
+		name   string
+		filter DocFilter
+		want   []string
+	}{
+		{"no filter", DocFilter{}, []string{"runbook", "adr", "legacy"}},
+		{"tag is case-insensitive", DocFilter{Tag: "OPS"}, []string{"runbook", "adr"}},
+		{"owner", DocFilter{Owner: "bo@example.com"}, []string{"adr"}},
+		{"status", DocFilter{Status: DocStatusDraft}, []string{"adr"}},
+		{"reviewed before", DocFilter{ReviewedBefore: "2026-01-01"}, []string{"adr", "legacy"}},
+		{"combined", DocFilter{Tag: "ops", ReviewedBefore: "2026-01-01"}, []string{"adr"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summaries, err := FilterDocs(tt.filter)
+			if err != nil {
+				t.Fatalf("FilterDocs() error = %v", err)
+			}
+			if len(summaries) != len(tt.want) {
+				t.Fatalf("FilterDocs() returned %d docs, want %d", len(summaries), len(tt.want))
+			}
+			for i, want := range tt.want {
+				if summaries[i].FilePath != want {
+					t.Errorf("FilterDocs()[%d].FilePath = %q, want %q", i, summaries[i].FilePath, want)
+				}
+			}
+		})
+	}
+
+	if _, err := FilterDocs(DocFilter{ReviewedBefore: "soon"}); err == nil {
+		t.Error("FilterDocs() with invalid reviewedBefore should return error")
+	}
+}
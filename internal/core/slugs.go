@@ -0,0 +1,171 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// slugRegex matches lowercase words separated by single dashes, e.g. "backend-development"
+var slugRegex = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+
+// SlugResolution is the result of resolving a slug path to a doc folder
+type SlugResolution struct {
+	FilePath   string `json:"filePath"`   // folder path relative to doclific, e.g. "4e5e.../db89..."
+	SlugPath   string `json:"slugPath"`   // canonical slug path, e.g. "architecture/backend"
+	Redirected bool   `json:"redirected"` // true if any segment matched an old slug or a folder name
+}
+
+// Slugify converts a title into a slug, e.g. "Backend Development" -> "backend-development"
+func Slugify(title string) string {
+	var b strings.Builder
+	lastDash := true
+	for _, r := range strings.ToLower(title) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			lastDash = false
+		} else if !lastDash {
+			b.WriteRune('-')
+			lastDash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
+
+// findSlugInDir returns the config of the doc in dirPath using slug, other than the doc named skip
+func findSlugInDir(dirPath string, slug string, skip string) (*Config, error) {
+	entries, err := os.ReadDir(dirPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory %s: %w", dirPath, err)
+	}
+	for _, entry := range entries {
+		if !isDocDir(entry) || entry.Name() == skip {
+			continue
+		}
+		config, err := readConfig(filepath.Join(dirPath, entry.Name()))
+		if err != nil {
+			continue
+		}
+		if config.Slug == slug {
+			return config, nil
+		}
+	}
+	return nil, nil
+}
+
+// SetDocSlug sets the slug of a doc, which must be unique among its siblings
+// The previous slug is remembered so existing links keep resolving; an empty slug clears it
+func SetDocSlug(filePath string, slug string) error {
+	if slug != "" && !slugRegex.MatchString(slug) {
+		return fmt.Errorf("invalid slug %q: use lowercase letters, digits and single dashes", slug)
+	}
+
+	fullPath, err := getDoclificPath(filePath)
+	if err != nil {
+		return err
+	}
+
+	config, err := readConfig(fullPath)
+	if err != nil {
+		return err
+	}
+
+	if config.Slug == slug {
+		return nil
+	}
+
+	if slug != "" {
+		sibling, err := findSlugInDir(filepath.Dir(fullPath), slug, filepath.Base(fullPath))
+		if err != nil {
+			return err
+		}
+		if sibling != nil {
+			return fmt.Errorf("slug %q is already used by sibling %q", slug, sibling.Title)
+		}
+	}
+
+	// Remember the old slug for redirects and drop the new one if it is being reclaimed
+	previous := []string{}
+	for _, old := range append(config.PreviousSlugs, config.Slug) {
+		if old != "" && old != slug && !slices.Contains(previous, old) {
+			previous = append(previous, old)
+		}
+	}
+	config.PreviousSlugs = previous
+	config.Slug = slug
+
+	return writeConfig(fullPath, config)
+}
+
+// ResolveSlugPath maps a slug path such as "/architecture/backend" to the doc's folder path
+// Each segment may be a current slug, a previous slug or the folder name itself
+func ResolveSlugPath(slugPath string) (*SlugResolution, error) {
+	doclificPath, err := getDoclificPath("")
+	if err != nil {
+		return nil, err
+	}
+
+	segments := strings.Split(strings.Trim(slugPath, "/"), "/")
+	if len(segments) == 1 && segments[0] == "" {
+		return nil, fmt.Errorf("slug path is required")
+	}
+
+	dirPath := doclificPath
+	folderNames := make([]string, 0, len(segments))
+	canonical := make([]string, 0, len(segments))
+	redirected := false
+
+	for _, segment := range segments {
+		entries, err := os.ReadDir(dirPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read directory %s: %w", dirPath, err)
+		}
+
+		match := ""
+		var matchConfig *Config
+
+		for _, entry := range entries {
+			if !isDocDir(entry) {
+				continue
+			}
+			config, err := readConfig(filepath.Join(dirPath, entry.Name()))
+			if err != nil {
+				continue
+			}
+
+			// A current slug always wins over an old slug or folder name
+			if config.Slug == segment {
+				match, matchConfig = entry.Name(), config
+				break
+			}
+			if match == "" && (entry.Name() == segment || slices.Contains(config.PreviousSlugs, segment)) {
+				match, matchConfig = entry.Name(), config
+			}
+		}
+
+		if match == "" {
+			return nil, fmt.Errorf("no doc found for slug path %s", slugPath)
+		}
+
+		segmentCanonical := matchConfig.Slug
+		if segmentCanonical == "" {
+			segmentCanonical = match
+		}
+		if segmentCanonical != segment {
+			redirected = true
+		}
+
+		folderNames = append(folderNames, match)
+		canonical = append(canonical, segmentCanonical)
+		dirPath = filepath.Join(dirPath, match)
+	}
+
+	return &SlugResolution{
+		FilePath:   strings.Join(folderNames, "/"),
+		SlugPath:   strings.Join(canonical, "/"),
+		Redirected: redirected,
+	}, nil
+}
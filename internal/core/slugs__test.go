@@ -0,0 +1,134 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"Backend Development", "backend-development"},
+		{"  API Reference (v2)  ", "api-reference-v2"},
+		{"CLI -- Reference", "cli-reference"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := Slugify(tt.title); got != tt.want {
+			t.Errorf("Slugify(%q) = %q, want %q", tt.title, got, tt.want)
+		}
+	}
+}
+
+func TestSetDocSlugAndResolve(t *testing.T) {
+	originalDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get current directory: %v", err)
+	}
+
+	tmpDir := t.TempDir()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("failed to change directory: %v", err)
+	}
+	defer os.Chdir(originalDir)
+
+	doclificDir := filepath.Join(tmpDir, "doclific")
+	for _, path := range []string{"arch", "arch/backend", "arch/frontend"} {
+		dir := filepath.Join(doclificDir, filepath.FromSlash(path))
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", path, err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{"title": "`+path+`"}`), 0644); err != nil {
+			t.Fatalf("failed to write config.json: %v", err)
+		}
+	}
+
+	if err := SetDocSlug("arch", "architecture"); err != nil {
+		t.Fatalf("SetDocSlug() error = %v", err)
+	}
+	if err := SetDocSlug("arch/backend", "backend"); err != nil {
+		t.Fatalf("SetDocSlug() error = %v", err)
+	}
+
+	// Invalid and duplicate slugs are rejected
+	if err := SetDocSlug("arch/frontend", "Not A Slug"); err == nil {
+		t.Error("SetDocSlug() with invalid slug should return error")
+	}
+	if err := SetDocSlug("arch/frontend", "backend"); err == nil {
+		t.Error("SetDocSlug() with sibling's slug should return error")
+	}
+
+	resolved, err := ResolveSlugPath("/architecture/backend")
+	if err != nil {
+		t.Fatalf("ResolveSlugPath() error = %v", err)
+	}
+	if resolved.FilePath != "arch/backend" || resolved.Redirected {
+		t.Errorf("ResolveSlugPath() = %+v, want arch/backend without redirect", resolved)
+	}
+
+	// Folder names still work for docs without a slug
+	resolved, err = ResolveSlugPath("architecture/frontend")
+	if err != nil {
+		t.Fatalf("ResolveSlugPath() error = %v", err)
+	}
+	if resolved.FilePath != "arch/frontend" || resolved.SlugPath != "architecture/frontend" || resolved.Redirected {
+		t.Errorf("ResolveSlugPath() = %+v, want arch/frontend without redirect", resolved)
+	}
+
+	// Renaming a slug keeps the old one working as a redirect
+	if err := SetDocSlug("arch/backend", "server"); err != nil {
+		t.Fatalf("SetDocSlug() rename error = %v", err)
+	}
+	resolved, err = ResolveSlugPath("architecture/backend")
+	if err != nil {
+		t.Fatalf("ResolveSlugPath() old slug error = %v", err)
+	}
+	if resolved.FilePath != "arch/backend" || resolved.SlugPath != "architecture/server" || !resolved.Redirected {
+		t.Errorf("ResolveSlugPath() = %+v, want redirect to architecture/server", resolved)
+	}
+
+	// A sibling taking over an old slug wins over the redirect
+	if err := SetDocSlug("arch/frontend", "backend"); err != nil {
+		t.Fatalf("SetDocSlug() reuse of old slug error = %v", err)
+	}
+	resolved, err = ResolveSlugPath("architecture/backend")
+	if err != nil {
+		t.Fatalf("ResolveSlugPath() error = %v", err)
+	}
+	if resolved.FilePath != "arch/frontend" || resolved.Redirected {
+		t.Errorf("ResolveSlugPath() = %+v, want arch/frontend without redirect", resolved)
+	}
+
+	if _, err := ResolveSlugPath("architecture/missing"); err == nil {
+		t.Error("ResolveSlugPath() with unknown slug should return error")
+	}
+
+	docs, err := GetDocs()
+	if err != nil {
+		t.Fatalf("GetDocs() error = %v", err)
+	}
+	if docs[0].Slug != "architecture" {
+		t.Errorf("GetDocs() slug = %q, want %q", docs[0].Slug, "architecture")
+	}
+
+	// Moving a doc under a parent whose child already uses its slug is rejected
+	if err := os.MkdirAll(filepath.Join(doclificDir, "ops"), 0755); err != nil {
+		t.Fatalf("failed to create ops: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(doclificDir, "ops", "config.json"), []byte(`{"title": "ops", "order": 1, "slug": "server"}`), 0644); err != nil {
+		t.Fatalf("failed to write config.json: %v", err)
+	}
+	if err := UpdateDocOrder(UpdateDocOrderRequestPayload{Name: "backend", UpdatedPath: ""}); err == nil {
+		t.Error("UpdateDocOrder() onto a sibling's slug should return error")
+	}
+	if _, err := os.Stat(filepath.Join(doclificDir, "arch", "backend")); err != nil {
+		t.Errorf("rejected move should leave the doc in place: %v", err)
+	}
+	if err := UpdateDocOrder(UpdateDocOrderRequestPayload{Name: "frontend", UpdatedPath: ""}); err != nil {
+		t.Errorf("UpdateDocOrder() with a free slug error = %v", err)
+	}
+}
@@ -0,0 +1,126 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// snippetSearchDistance is how far (in lines) a snippet is searched for when it no longer matches its hash
+const snippetSearchDistance = 100
+
+// SnippetResult is the current state of a CodebaseSnippet in the working directory
+type SnippetResult struct {
+	Contents      string `json:"contents"`
+	FullPath      string `json:"fullPath"`
+	LineStart     int    `json:"lineStart"`
+	LineEnd       int    `json:"lineEnd"`
+	BaseCommit    string `json:"baseCommit"`
+	ContentHash   string `json:"contentHash"`
+	NeedsReview   bool   `json:"needsReview"`
+	LinesAdjusted bool   `json:"linesAdjusted"`
+}
+
+// ExtractLines extracts lines from content between start and end (1-indexed, inclusive)
+// An end below 1 or past the last line selects through the end of the content
+func ExtractLines(content string, start, end int) string {
+	lines := strings.Split(content, "\n")
+
+	if start < 1 {
+		start = 1
+	}
+	if end < 1 || end > len(lines) {
+		end = len(lines)
+	}
+	if start > len(lines) {
+		return ""
+	}
+
+	// Convert to 0-indexed
+	startIdx := start - 1
+	endIdx := end
+
+	if endIdx > len(lines) {
+		endIdx = len(lines)
+	}
+
+	return strings.Join(lines[startIdx:endIdx], "\n")
+}
+
+// ResolveSnippet reads a snippet's lines and checks them against the stored content hash
+// If the content moved (lines added or removed above it), the new line range is returned
+// If it cannot be found nearby, the original lines are returned flagged for review
+func ResolveSnippet(filePath string, lineStart, lineEnd int, storedHash string) (*SnippetResult, error) {
+	fullContents, err := GetFileContents(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	fullPath := filepath.Join(cwd, filePath)
+	currentCommit, _ := GetCurrentCommit()
+
+	originalSnippetContent := ExtractLines(fullContents, lineStart, lineEnd)
+	originalHash := HashContent(originalSnippetContent)
+
+	result := &SnippetResult{
+		Contents:    originalSnippetContent,
+		FullPath:    fullPath,
+		LineStart:   lineStart,
+		LineEnd:     lineEnd,
+		BaseCommit:  currentCommit,
+		ContentHash: originalHash,
+	}
+
+	// If no stored hash, this is a new/initializing snippet; the user selected these lines as-is
+	// Otherwise, if the content matches at the original lines, no adjustment is needed
+	if storedHash == "" || storedHash == originalHash {
+		return result, nil
+	}
+
+	// Content doesn't match at original lines - search for where it moved
+	// Check line ranges shifted up and down (up to 100 lines in each direction)
+	snippetLength := lineEnd - lineStart
+	totalLines := len(strings.Split(fullContents, "\n"))
+
+	for offset := 1; offset <= snippetSearchDistance; offset++ {
+		// Check shifted down (lines added above)
+		downStart := lineStart + offset
+		downEnd := downStart + snippetLength
+		if downEnd <= totalLines {
+			downContent := ExtractLines(fullContents, downStart, downEnd)
+			if storedHash == HashContent(downContent) {
+				result.Contents = downContent
+				result.LineStart = downStart
+				result.LineEnd = downEnd
+				result.ContentHash = storedHash
+				result.LinesAdjusted = true
+				return result, nil
+			}
+		}
+
+		// Check shifted up (lines removed above)
+		upStart := lineStart - offset
+		upEnd := upStart + snippetLength
+		if upStart >= 1 {
+			upContent := ExtractLines(fullContents, upStart, upEnd)
+			if storedHash == HashContent(upContent) {
+				result.Contents = upContent
+				result.LineStart = upStart
+				result.LineEnd = upEnd
+				result.ContentHash = storedHash
+				result.LinesAdjusted = true
+				return result, nil
+			}
+		}
+	}
+
+	// Content not found at any nearby position - it has been modified
+	// Return original lines but flag for review
+	result.NeedsReview = true
+	return result, nil
+}
@@ -0,0 +1,107 @@
+package core
+
+import (
+	"sort"
+)
+
+// SnippetChange describes how much a CodebaseSnippet's file changed since the snippet's baseCommit
+type SnippetChange struct {
+	FilePath   string `json:"filePath"`
+	LineStart  string `json:"lineStart"`
+	LineEnd    string `json:"lineEnd"`
+	BaseCommit string `json:"baseCommit"`
+	Line       int    `json:"line"` // line of the snippet in content.mdx
+	FileChangeStats
+	Error string `json:"error,omitempty"` // set when git could not compare, e.g. baseCommit is unknown
+}
+
+// StaleDoc is a doc whose referenced code changed since its snippets were last synced
+type StaleDoc struct {
+	FilePath string          `json:"filePath"`
+	Title    string          `json:"title"`
+	Owners   []string        `json:"owners,omitempty"`
+	Score    int             `json:"score"` // total lines added and deleted across snippet files
+	Commits  int             `json:"commits"`
+	Snippets []SnippetChange `json:"snippets"`
+}
+
+// GetStaleDocs lists docs embedding CodebaseSnippets whose files changed since each snippet's baseCommit
+// Docs are ranked by how much the referenced code changed, even if the snippet lines still hash-match
+func GetStaleDocs() ([]StaleDoc, error) {
+	docs, err := GetDocs()
+	if err != nil {
+		return nil, err
+	}
+
+	// Many snippets point at the same file from the same commit, so only ask git once per pair
+	type statsKey struct{ filePath, baseCommit string }
+	type statsResult struct {
+		stats FileChangeStats
+		err   error
+	}
+	cache := map[statsKey]statsResult{}
+
+	staleDocs := []StaleDoc{}
+	err = WalkDocs(docs, func(filePath string, doc FolderStructure) error {
+		content, err := GetDoc(filePath)
+		if err != nil {
+			return err
+		}
+
+		staleDoc := StaleDoc{
+			FilePath: filePath,
+			Title:    doc.Title,
+			Owners:   doc.Owners,
+			Snippets: []SnippetChange{},
+		}
+
+		for _, snippet := range ParseMDXComponents(content, ComponentCodebaseSnippet) {
+			change := SnippetChange{
+				FilePath:   snippet.Attributes["filePath"],
+				LineStart:  snippet.Attributes["lineStart"],
+				LineEnd:    snippet.Attributes["lineEnd"],
+				BaseCommit: snippet.Attributes["baseCommit"],
+				Line:       snippet.Line,
+			}
+			if change.FilePath == "" || change.BaseCommit == "" {
+				continue // Snippets that were never synced have nothing to compare against
+			}
+
+			key := statsKey{change.FilePath, change.BaseCommit}
+			result, ok := cache[key]
+			if !ok {
+				stats, err := GetFileChangesSince(change.FilePath, change.BaseCommit)
+				result = statsResult{stats, err}
+				cache[key] = result
+			}
+
+			if result.err != nil {
+				change.Error = result.err.Error()
+			} else if result.stats.Commits == 0 {
+				continue
+			}
+			change.FileChangeStats = result.stats
+
+			staleDoc.Score += change.LinesAdded + change.LinesDeleted
+			staleDoc.Commits += change.Commits
+			staleDoc.Snippets = append(staleDoc.Snippets, change)
+		}
+
+		if len(staleDoc.Snippets) > 0 {
+			staleDocs = append(staleDocs, staleDoc)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(staleDocs, func(i, j int) bool {
+		if staleDocs[i].Score != staleDocs[j].Score {
+			return staleDocs[i].Score > staleDocs[j].Score
+		}
+		return staleDocs[i].Commits > staleDocs[j].Commits
+	})
+
+	return staleDocs, nil
+}
@@ -0,0 +1,87 @@
+package core
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// runGit runs a git command in dir with a fixed identity
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-c", "user.name=Test", "-c", "user.email=test@example.com"}, args...)...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+func TestGetStaleDocs(t *testing.T) {
+	originalDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get current directory: %v", err)
+	}
+
+	tmpDir := t.TempDir()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("failed to change directory: %v", err)
+	}
+	defer os.Chdir(originalDir)
+
+	runGit(t, tmpDir, "init", "-q")
+	writeFile := func(path, content string) {
+		t.Helper()
+		fullPath := filepath.Join(tmpDir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", path, err)
+		}
+	}
+
+	writeFile("a.go", "package a\n")
+	writeFile("b.go", "package b\n")
+	runGit(t, tmpDir, "add", "-A")
+	runGit(t, tmpDir, "commit", "-q", "-m", "initial")
+	base := runGit(t, tmpDir, "rev-parse", "HEAD")
+
+	writeFile("a.go", "package a\n\nfunc A() {}\nfunc B() {}\n")
+	runGit(t, tmpDir, "commit", "-q", "-am", "change a")
+	writeFile("b.go", "package b\n\nfunc C() {}\n")
+	runGit(t, tmpDir, "commit", "-q", "-am", "change b")
+	head := runGit(t, tmpDir, "rev-parse", "HEAD")
+
+	snippet := func(file, commit string) string {
+		return `<CodebaseSnippet filePath="` + file + `" lineStart="1" lineEnd="1" baseCommit="` + commit + `" contentHash="x"></CodebaseSnippet>` + "\n"
+	}
+	writeFile("doclific/small/config.json", `{"title": "Small", "owners": ["o@example.com"]}`)
+	writeFile("doclific/small/content.mdx", snippet("b.go", base))
+	writeFile("doclific/big/config.json", `{"title": "Big", "order": 1}`)
+	writeFile("doclific/big/content.mdx", snippet("a.go", base)+snippet("b.go", head))
+	writeFile("doclific/fresh/config.json", `{"title": "Fresh", "order": 2}`)
+	writeFile("doclific/fresh/content.mdx", snippet("a.go", head)+`<CodebaseSnippet filePath="a.go" lineStart="" lineEnd=""></CodebaseSnippet>`)
+
+	staleDocs, err := GetStaleDocs()
+	if err != nil {
+		t.Fatalf("GetStaleDocs() error = %v", err)
+	}
+	if len(staleDocs) != 2 {
+		t.Fatalf("GetStaleDocs() returned %d docs, want 2", len(staleDocs))
+	}
+
+	// a.go gained 3 lines, b.go gained 2, so Big ranks first
+	if staleDocs[0].Title != "Big" || staleDocs[0].Score != 3 || len(staleDocs[0].Snippets) != 1 {
+		t.Errorf("GetStaleDocs()[0] = %+v, want Big with score 3 and one changed snippet", staleDocs[0])
+	}
+	if staleDocs[1].Title != "Small" || staleDocs[1].Score != 2 || staleDocs[1].Commits != 1 {
+		t.Errorf("GetStaleDocs()[1] = %+v, want Small with score 2 in 1 commit", staleDocs[1])
+	}
+	if len(staleDocs[1].Owners) != 1 || staleDocs[1].Owners[0] != "o@example.com" {
+		t.Errorf("GetStaleDocs()[1].Owners = %v, want [o@example.com]", staleDocs[1].Owners)
+	}
+}
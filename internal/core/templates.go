@@ -0,0 +1,190 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"doclific/internal/config"
+)
+
+const (
+	// TemplatesDirName is the folder inside doclific/ (and the user config dir) holding doc templates
+	TemplatesDirName = ".templates"
+
+	templateSourceRepo = "repo"
+	templateSourceUser = "user"
+)
+
+// Template describes a doc template available to CreateDoc
+type Template struct {
+	Name        string  `json:"name"`
+	Title       string  `json:"title"`
+	Description string  `json:"description,omitempty"`
+	Icon        *string `json:"icon,omitempty"`
+	Source      string  `json:"source"` // "repo" or "user"
+}
+
+// loadedTemplate is a template together with its raw content.mdx
+type loadedTemplate struct {
+	Template
+	Content string
+}
+
+// templateDir is a directory that may contain templates, tagged with where it came from
+type templateDir struct {
+	path   string
+	source string
+}
+
+// getTemplateDirs returns the template directories in lookup order
+// Repository templates come first so a project can override a user's personal template
+func getTemplateDirs() ([]templateDir, error) {
+	repoDir, err := getDoclificPath(TemplatesDirName)
+	if err != nil {
+		return nil, err
+	}
+
+	dirs := []templateDir{{path: repoDir, source: templateSourceRepo}}
+
+	configDir, err := config.GetConfigDir()
+	if err == nil {
+		dirs = append(dirs, templateDir{path: filepath.Join(configDir, "templates"), source: templateSourceUser})
+	}
+
+	return dirs, nil
+}
+
+// readTemplate reads a single template bundle (content.mdx plus optional config.json)
+func readTemplate(dirPath string, name string, source string) (*loadedTemplate, error) {
+	content, err := os.ReadFile(filepath.Join(dirPath, "content.mdx"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read content.mdx for template %s: %w", name, err)
+	}
+
+	tmpl := &loadedTemplate{
+		Template: Template{
+			Name:   name,
+			Title:  name,
+			Source: source,
+		},
+		Content: string(content),
+	}
+
+	configFile, err := os.ReadFile(filepath.Join(dirPath, "config.json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return tmpl, nil // config.json is optional for templates
+		}
+		return nil, fmt.Errorf("failed to read config.json for template %s: %w", name, err)
+	}
+
+	var cfg struct {
+		Title       string  `json:"title"`
+		Description string  `json:"description"`
+		Icon        *string `json:"icon,omitempty"`
+	}
+	if err := json.Unmarshal(configFile, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config.json for template %s: %w", name, err)
+	}
+
+	if cfg.Title != "" {
+		tmpl.Title = cfg.Title
+	}
+	tmpl.Description = cfg.Description
+	tmpl.Icon = cfg.Icon
+
+	return tmpl, nil
+}
+
+// ListTemplates returns all available templates sorted by name
+// When the repo and user config dir both define a template, the repo version wins
+func ListTemplates() ([]Template, error) {
+	dirs, err := getTemplateDirs()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	templates := []Template{}
+
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir.path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to read template directory %s: %w", dir.path, err)
+		}
+
+		for _, entry := range entries {
+			if !entry.IsDir() || seen[entry.Name()] {
+				continue
+			}
+
+			tmpl, err := readTemplate(filepath.Join(dir.path, entry.Name()), entry.Name(), dir.source)
+			if err != nil {
+				continue // Skip incomplete templates rather than hiding every other one
+			}
+
+			seen[entry.Name()] = true
+			templates = append(templates, tmpl.Template)
+		}
+	}
+
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Name < templates[j].Name
+	})
+
+	return templates, nil
+}
+
+// loadTemplate finds a template by name, checking the repo before the user config dir
+func loadTemplate(name string) (*loadedTemplate, error) {
+	if name == "" || name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return nil, fmt.Errorf("invalid template name %q", name)
+	}
+
+	dirs, err := getTemplateDirs()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, dir := range dirs {
+		templatePath := filepath.Join(dir.path, name)
+		if info, err := os.Stat(templatePath); err != nil || !info.IsDir() {
+			continue
+		}
+		return readTemplate(templatePath, name, dir.source)
+	}
+
+	return nil, fmt.Errorf("template %s not found", name)
+}
+
+// templateVariables returns the values substituted into a template for a new doc
+// Author fields come from the git identity and are left empty if git is not configured
+func templateVariables(title string) map[string]string {
+	author, _ := GetGitUsername()
+	authorEmail, _ := GetGitEmail()
+
+	return map[string]string{
+		"title":       title,
+		"date":        time.Now().Format("2006-01-02"),
+		"author":      author,
+		"authorEmail": authorEmail,
+	}
+}
+
+// renderTemplate replaces {{name}} placeholders with their values
+// Unknown placeholders are left untouched so templates can contain literal braces
+func renderTemplate(content string, vars map[string]string) string {
+	pairs := make([]string, 0, len(vars)*2)
+	for key, value := range vars {
+		pairs = append(pairs, "{{"+key+"}}", value)
+	}
+	return strings.NewReplacer(pairs...).Replace(content)
+}
@@ -0,0 +1,139 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTemplate(t *testing.T, dir string, name string, content string, configJSON string) {
+	t.Helper()
+	templateDir := filepath.Join(dir, name)
+	if err := os.MkdirAll(templateDir, 0755); err != nil {
+		t.Fatalf("failed to create template directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(templateDir, "content.mdx"), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write template content.mdx: %v", err)
+	}
+	if configJSON != "" {
+		if err := os.WriteFile(filepath.Join(templateDir, "config.json"), []byte(configJSON), 0644); err != nil {
+			t.Fatalf("failed to write template config.json: %v", err)
+		}
+	}
+}
+
+func TestListTemplates(t *testing.T) {
+	originalDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get current directory: %v", err)
+	}
+
+	tmpDir := t.TempDir()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("failed to change directory: %v", err)
+	}
+	defer os.Chdir(originalDir)
+
+	homeDir := t.TempDir()
+	t.Setenv("HOME", homeDir)
+
+	// Test with no templates anywhere
+	templates, err := ListTemplates()
+	if err != nil {
+		t.Fatalf("ListTemplates() error = %v", err)
+	}
+	if len(templates) != 0 {
+		t.Errorf("ListTemplates() returned %d templates, want 0", len(templates))
+	}
+
+	repoTemplates := filepath.Join(tmpDir, "doclific", TemplatesDirName)
+	userTemplates := filepath.Join(homeDir, ".config", "doclific", "templates")
+	writeTemplate(t, repoTemplates, "adr", "# {{title}}\n", `{"title": "Architecture Decision Record", "icon": "Scale"}`)
+	writeTemplate(t, userTemplates, "adr", "# user adr\n", "")
+	writeTemplate(t, userTemplates, "runbook", "# {{title}}\n", "")
+
+	templates, err = ListTemplates()
+	if err != nil {
+		t.Fatalf("ListTemplates() error = %v", err)
+	}
+	if len(templates) != 2 {
+		t.Fatalf("ListTemplates() returned %d templates, want 2", len(templates))
+	}
+	if templates[0].Name != "adr" || templates[0].Source != "repo" {
+		t.Errorf("ListTemplates()[0] = %+v, want repo adr template", templates[0])
+	}
+	if templates[0].Title != "Architecture Decision Record" {
+		t.Errorf("ListTemplates()[0].Title = %q, want %q", templates[0].Title, "Architecture Decision Record")
+	}
+	if templates[1].Name != "runbook" || templates[1].Source != "user" {
+		t.Errorf("ListTemplates()[1] = %+v, want user runbook template", templates[1])
+	}
+	if templates[1].Title != "runbook" {
+		t.Errorf("ListTemplates()[1].Title = %q, want %q", templates[1].Title, "runbook")
+	}
+}
+
+func TestCreateDocWithTemplate(t *testing.T) {
+	originalDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get current directory: %v", err)
+	}
+
+	tmpDir := t.TempDir()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("failed to change directory: %v", err)
+	}
+	defer os.Chdir(originalDir)
+
+	t.Setenv("HOME", t.TempDir())
+
+	repoTemplates := filepath.Join(tmpDir, "doclific", TemplatesDirName)
+	writeTemplate(t, repoTemplates, "runbook", "# {{title}}\n\nCreated {{date}}\n\n{{unknown}}\n", `{"title": "Runbook", "icon": "LifeBuoy"}`)
+
+	response, err := CreateDoc("", "Deploys", nil, "runbook")
+	if err != nil {
+		t.Fatalf("CreateDoc() error = %v", err)
+	}
+	if response.Icon == nil || *response.Icon != "LifeBuoy" {
+		t.Errorf("CreateDoc() icon = %v, want template icon %q", response.Icon, "LifeBuoy")
+	}
+
+	content, err := os.ReadFile(filepath.Join(response.FilePath, "content.mdx"))
+	if err != nil {
+		t.Fatalf("failed to read content.mdx: %v", err)
+	}
+	if !strings.HasPrefix(string(content), "# Deploys\n\nCreated ") {
+		t.Errorf("CreateDoc() content.mdx = %q, want rendered title and date", string(content))
+	}
+	if strings.Contains(string(content), "{{date}}") {
+		t.Errorf("CreateDoc() content.mdx = %q, date was not substituted", string(content))
+	}
+	if !strings.Contains(string(content), "{{unknown}}") {
+		t.Errorf("CreateDoc() content.mdx = %q, unknown placeholder should be kept", string(content))
+	}
+
+	// The templates folder must not show up as a doc
+	docs, err := GetDocs()
+	if err != nil {
+		t.Fatalf("GetDocs() error = %v", err)
+	}
+	if len(docs) != 1 {
+		t.Fatalf("GetDocs() returned %d docs, want 1", len(docs))
+	}
+
+	// Unknown templates are an error and must not create a folder
+	if _, err := CreateDoc("", "Missing", nil, "does-not-exist"); err == nil {
+		t.Error("CreateDoc() with unknown template should return error")
+	}
+	if _, err := CreateDoc("", "Escape", nil, "../runbook"); err == nil {
+		t.Error("CreateDoc() with path in template name should return error")
+	}
+	docs, err = GetDocs()
+	if err != nil {
+		t.Fatalf("GetDocs() error = %v", err)
+	}
+	if len(docs) != 1 {
+		t.Errorf("GetDocs() returned %d docs after failed creates, want 1", len(docs))
+	}
+}
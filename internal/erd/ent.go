@@ -0,0 +1,468 @@
+package erd
+
+import (
+	"go/ast"
+	"strings"
+)
+
+// entFieldTypes maps ent field constructors to the column types ent creates on PostgreSQL
+var entFieldTypes = map[string]string{
+	"String":  "varchar",
+	"Text":    "text",
+	"Bytes":   "bytea",
+	"Bool":    "boolean",
+	"Time":    "timestamptz",
+	"UUID":    "uuid",
+	"Int":     "bigint",
+	"Int64":   "bigint",
+	"Uint":    "bigint",
+	"Uint64":  "bigint",
+	"Int32":   "integer",
+	"Uint32":  "integer",
+	"Int8":    "smallint",
+	"Int16":   "smallint",
+	"Uint8":   "smallint",
+	"Uint16":  "smallint",
+	"Float":   "double precision",
+	"Float64": "double precision",
+	"Float32": "real",
+	"Enum":    "varchar",
+	"JSON":    "jsonb",
+	"Strings": "jsonb",
+	"Ints":    "jsonb",
+	"Floats":  "jsonb",
+	"Any":     "jsonb",
+}
+
+// entImports are the names a file imports ent's schema packages under
+type entImports struct {
+	ent, field, edge, mixin, entsql string
+}
+
+// entMethod is a call in a builder chain such as .Optional() or .StorageKey("name")
+type entMethod struct {
+	Name string
+	Args []ast.Expr
+}
+
+// entCall is a builder chain such as field.String("name").Optional().Unique()
+type entCall struct {
+	Func    string // constructor, e.g. "String" for field.String
+	Args    []ast.Expr
+	Methods []entMethod // in call order
+}
+
+// unwindEntCall flattens a builder chain whose constructor comes from the package imported as pkg
+func unwindEntCall(expr ast.Expr, pkg string) (entCall, bool) {
+	var methods []entMethod
+	for {
+		call, ok := expr.(*ast.CallExpr)
+		if !ok {
+			return entCall{}, false
+		}
+		selector, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return entCall{}, false
+		}
+		if ident, ok := selector.X.(*ast.Ident); ok {
+			if pkg == "" || ident.Name != pkg {
+				return entCall{}, false
+			}
+			// Methods were collected from the outside in
+			for i, j := 0, len(methods)-1; i < j; i, j = i+1, j-1 {
+				methods[i], methods[j] = methods[j], methods[i]
+			}
+			return entCall{Func: selector.Sel.Name, Args: call.Args, Methods: methods}, true
+		}
+		methods = append(methods, entMethod{Name: selector.Sel.Name, Args: call.Args})
+		expr = selector.X
+	}
+}
+
+// entElements returns the elements of a []ent.Field{...}-style slice literal
+func entElements(expr ast.Expr) []ast.Expr {
+	if lit, ok := expr.(*ast.CompositeLit); ok {
+		return lit.Elts
+	}
+	return nil
+}
+
+// entList is the elements returned by a schema method, with the imports of the file declaring it
+type entList struct {
+	imports  entImports
+	elements []ast.Expr
+}
+
+// entType is a struct embedding ent.Schema or mixin.Schema, with its schema methods
+type entType struct {
+	Name        string
+	Fields      entList
+	Edges       entList
+	Mixins      entList
+	Annotations entList
+}
+
+// entEdge is an edge.To or edge.From declared on an ent schema
+type entEdge struct {
+	Owner    string
+	Name     string
+	Target   string
+	Inverse  bool   // edge.From
+	Ref      string // name of the edge.To this edge inverts
+	Unique   bool
+	Required bool
+	Field    string // column holding the key, from .Field or .StorageKey(edge.Column(...))
+}
+
+// entReader turns ent schemas into tables and foreign keys
+type entReader struct {
+	types  map[string]*entType
+	tables map[string]*SchemaTable
+	schema *Schema
+}
+
+// entColumn returns the column for a field.X(...) chain
+func entColumn(call entCall) (*SchemaColumn, bool) {
+	if len(call.Args) == 0 {
+		return nil, false
+	}
+	name, ok := stringLiteral(call.Args[0])
+	if !ok {
+		return nil, false
+	}
+
+	column := &SchemaColumn{Name: name, Type: entFieldTypes[call.Func]}
+	if column.Type == "" {
+		column.Type = "text"
+	}
+	for _, m := range call.Methods {
+		switch m.Name {
+		case "Optional":
+			column.Nullable = true
+		case "Unique":
+			column.Unique = true
+		case "StorageKey":
+			if len(m.Args) == 1 {
+				if key, ok := stringLiteral(m.Args[0]); ok {
+					column.Name = key
+				}
+			}
+		case "SchemaType":
+			// SchemaType(map[string]string{dialect.Postgres: "numeric(10, 2)"})
+			if len(m.Args) == 1 {
+				for _, element := range entElements(m.Args[0]) {
+					kv, ok := element.(*ast.KeyValueExpr)
+					if !ok {
+						continue
+					}
+					key, _ := stringLiteral(kv.Key)
+					if selector, ok := kv.Key.(*ast.SelectorExpr); ok {
+						key = strings.ToLower(selector.Sel.Name)
+					}
+					if value, ok := stringLiteral(kv.Value); ok && key == "postgres" {
+						column.Type = NormalizeType(value)
+					}
+				}
+			}
+		}
+	}
+	return column, true
+}
+
+// mixinColumns returns the columns added by the mixins of an ent schema
+func (r *entReader) mixinColumns(t *entType) []*SchemaColumn {
+	var columns []*SchemaColumn
+	for _, element := range t.Mixins.elements {
+		lit, ok := element.(*ast.CompositeLit)
+		if !ok {
+			continue
+		}
+		switch typ := lit.Type.(type) {
+		case *ast.SelectorExpr:
+			pkg, _ := typ.X.(*ast.Ident)
+			if pkg == nil || pkg.Name != t.Mixins.imports.mixin {
+				continue
+			}
+			switch typ.Sel.Name {
+			case "Time":
+				columns = append(columns, &SchemaColumn{Name: "create_time", Type: "timestamptz"}, &SchemaColumn{Name: "update_time", Type: "timestamptz"})
+			case "CreateTime":
+				columns = append(columns, &SchemaColumn{Name: "create_time", Type: "timestamptz"})
+			case "UpdateTime":
+				columns = append(columns, &SchemaColumn{Name: "update_time", Type: "timestamptz"})
+			}
+		case *ast.Ident:
+			if mixin := r.types[typ.Name]; mixin != nil {
+				columns = append(columns, r.fieldColumns(mixin.Fields)...)
+			}
+		}
+	}
+	return columns
+}
+
+// fieldColumns returns the columns declared by a Fields method
+func (r *entReader) fieldColumns(fields entList) []*SchemaColumn {
+	var columns []*SchemaColumn
+	for _, element := range fields.elements {
+		if call, ok := unwindEntCall(element, fields.imports.field); ok {
+			if column, ok := entColumn(call); ok {
+				columns = append(columns, column)
+			}
+		}
+	}
+	return columns
+}
+
+// tableName returns the table of an ent schema from its entsql annotation, or ent's default plural name
+func (r *entReader) tableName(t *entType) string {
+	pkg := t.Annotations.imports.entsql
+	for _, element := range t.Annotations.elements {
+		// entsql.Annotation{Table: "accounts"}
+		if lit, ok := element.(*ast.CompositeLit); ok {
+			if selector, ok := lit.Type.(*ast.SelectorExpr); ok && selector.Sel.Name == "Annotation" {
+				if ident, ok := selector.X.(*ast.Ident); ok && ident.Name == pkg {
+					for _, field := range lit.Elts {
+						if kv, ok := field.(*ast.KeyValueExpr); ok {
+							if key, ok := kv.Key.(*ast.Ident); ok && key.Name == "Table" {
+								if name, ok := stringLiteral(kv.Value); ok {
+									return name
+								}
+							}
+						}
+					}
+				}
+			}
+		}
+		// entsql.Table("accounts")
+		if call, ok := unwindEntCall(element, pkg); ok && call.Func == "Table" && len(call.Args) == 1 {
+			if name, ok := stringLiteral(call.Args[0]); ok {
+				return name
+			}
+		}
+	}
+	return pluralize(snakeCase(t.Name))
+}
+
+// edges reads the edge.To and edge.From declarations of a schema
+// An inline .From on an edge.To declares the inverse edge on the same schema
+func (r *entReader) edges(t *entType) []entEdge {
+	var edges []entEdge
+	for _, element := range t.Edges.elements {
+		call, ok := unwindEntCall(element, t.Edges.imports.edge)
+		if !ok || (call.Func != "To" && call.Func != "From") || len(call.Args) < 2 {
+			continue
+		}
+		name, _ := stringLiteral(call.Args[0])
+		target := ""
+		if selector, ok := call.Args[1].(*ast.SelectorExpr); ok {
+			if ident, ok := selector.X.(*ast.Ident); ok {
+				target = ident.Name
+			}
+		}
+		if name == "" || target == "" {
+			continue
+		}
+
+		current := entEdge{Owner: t.Name, Name: name, Target: target, Inverse: call.Func == "From"}
+		for _, m := range call.Methods {
+			switch m.Name {
+			case "From":
+				if len(m.Args) == 1 {
+					edges = append(edges, current)
+					inverseName, _ := stringLiteral(m.Args[0])
+					current = entEdge{Owner: t.Name, Name: inverseName, Target: target, Inverse: true, Ref: name}
+				}
+			case "Ref":
+				if len(m.Args) == 1 {
+					current.Ref, _ = stringLiteral(m.Args[0])
+				}
+			case "Unique":
+				current.Unique = true
+			case "Required":
+				current.Required = true
+			case "Field":
+				if len(m.Args) == 1 {
+					current.Field, _ = stringLiteral(m.Args[0])
+				}
+			case "StorageKey":
+				// StorageKey(edge.Column("owner_id"))
+				if len(m.Args) == 1 {
+					if column, ok := unwindEntCall(m.Args[0], t.Edges.imports.edge); ok && column.Func == "Column" && len(column.Args) == 1 {
+						current.Field, _ = stringLiteral(column.Args[0])
+					}
+				}
+			}
+		}
+		edges = append(edges, current)
+	}
+	return edges
+}
+
+// foreignKey adds a foreign key column on table (if no field declares it) pointing at refTable's id
+func (r *entReader) foreignKey(table *SchemaTable, column string, refTable *SchemaTable, required bool, kind string) {
+	key := refTable.primaryKey()
+	if len(key) == 0 {
+		return
+	}
+	if table.Column(column) == nil {
+		table.Columns = append(table.Columns, &SchemaColumn{Name: column, Type: key[0].Type, Nullable: !required})
+	}
+	r.schema.ForeignKeys = append(r.schema.ForeignKeys, ForeignKey{
+		Table: table.Name, Column: column, RefTable: refTable.Name, RefColumn: key[0].Name, Type: kind,
+	})
+}
+
+// relate adds the foreign keys for an edge.To and its inverse edge.From, if any, the way ent lays them out:
+// the key lives on the "many" side, on the inverse side for one-to-one, and in a join table for many-to-many
+// (including a non-unique edge from a type to itself without an inverse, such as friends)
+func (r *entReader) relate(to entEdge, inverse *entEdge) {
+	owner, target := r.tables[to.Owner], r.tables[to.Target]
+	if owner == nil || target == nil {
+		return
+	}
+	defaultColumn := snakeCase(to.Owner) + "_" + to.Name
+
+	column := func(edges ...*entEdge) string {
+		for _, e := range edges {
+			if e != nil && e.Field != "" {
+				return e.Field
+			}
+		}
+		return defaultColumn
+	}
+	required := func(e *entEdge) bool { return e != nil && e.Required }
+
+	switch {
+	case inverse != nil && to.Unique && inverse.Unique:
+		r.foreignKey(target, column(inverse, &to), owner, required(inverse), OneToOne)
+	case inverse != nil && inverse.Unique, inverse == nil && !to.Unique && to.Owner != to.Target:
+		r.foreignKey(target, column(inverse, &to), owner, required(inverse), "")
+	case to.Unique:
+		r.foreignKey(owner, column(&to, inverse), target, to.Required, "")
+	default:
+		ownerKey, targetKey := owner.primaryKey(), target.primaryKey()
+		if len(ownerKey) == 0 || len(targetKey) == 0 {
+			return
+		}
+		joinName := defaultColumn
+		ownerColumn := snakeCase(to.Owner) + "_id"
+		targetColumn := snakeCase(to.Target) + "_id"
+		if to.Owner == to.Target {
+			targetColumn = singularize(snakeCase(to.Name)) + "_id"
+		}
+		if r.schema.Table(joinName) == nil {
+			r.schema.Tables = append(r.schema.Tables, &SchemaTable{Name: joinName, Columns: []*SchemaColumn{
+				{Name: ownerColumn, Type: ownerKey[0].Type, PrimaryKey: true},
+				{Name: targetColumn, Type: targetKey[0].Type, PrimaryKey: true},
+			}})
+		}
+		r.schema.ForeignKeys = append(r.schema.ForeignKeys,
+			ForeignKey{Table: joinName, Column: ownerColumn, RefTable: owner.Name, RefColumn: ownerKey[0].Name},
+			ForeignKey{Table: joinName, Column: targetColumn, RefTable: target.Name, RefColumn: targetKey[0].Name},
+		)
+	}
+}
+
+// readEntSchemas adds the ent schemas declared in files to schema
+func readEntSchemas(schema *Schema, files []*ast.File) {
+	r := &entReader{types: map[string]*entType{}, tables: map[string]*SchemaTable{}, schema: schema}
+	var order []*entType
+
+	typeFor := func(name string) *entType {
+		if r.types[name] == nil {
+			r.types[name] = &entType{Name: name}
+		}
+		return r.types[name]
+	}
+
+	for _, file := range files {
+		imports := entImports{
+			ent:    importName(file, "entgo.io/ent"),
+			field:  importName(file, "entgo.io/ent/schema/field"),
+			edge:   importName(file, "entgo.io/ent/schema/edge"),
+			mixin:  importName(file, "entgo.io/ent/schema/mixin"),
+			entsql: importName(file, "entgo.io/ent/dialect/entsql"),
+		}
+		if imports.ent == "" && imports.mixin == "" {
+			continue
+		}
+
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					typeSpec, ok := spec.(*ast.TypeSpec)
+					if !ok {
+						continue
+					}
+					structType, ok := typeSpec.Type.(*ast.StructType)
+					if !ok {
+						continue
+					}
+					for _, field := range structType.Fields.List {
+						typeName, _, _ := goTypeName(field.Type)
+						if len(field.Names) != 0 {
+							continue
+						}
+						switch {
+						case imports.ent != "" && typeName == imports.ent+".Schema":
+							order = append(order, typeFor(typeSpec.Name.Name))
+						case imports.mixin != "" && typeName == imports.mixin+".Schema":
+							typeFor(typeSpec.Name.Name)
+						}
+					}
+				}
+			case *ast.FuncDecl:
+				if decl.Recv == nil || decl.Type.Params.NumFields() != 0 {
+					continue
+				}
+				list := entList{imports: imports, elements: entElements(returnedExpr(decl))}
+				t := typeFor(receiverName(decl))
+				switch decl.Name.Name {
+				case "Fields":
+					t.Fields = list
+				case "Edges":
+					t.Edges = list
+				case "Mixin":
+					t.Mixins = list
+				case "Annotations":
+					t.Annotations = list
+				}
+			}
+		}
+	}
+
+	for _, t := range order {
+		table := &SchemaTable{Name: r.tableName(t)}
+		id := &SchemaColumn{Name: "id", Type: "bigint", PrimaryKey: true}
+		table.Columns = append(table.Columns, id)
+		for _, column := range append(r.mixinColumns(t), r.fieldColumns(t.Fields)...) {
+			if column.Name == "id" {
+				id.Type = column.Type // field.UUID("id", uuid.UUID{}) and friends replace the default key
+				continue
+			}
+			table.Columns = append(table.Columns, column)
+		}
+		r.tables[t.Name] = table
+		schema.AddTable(table)
+	}
+
+	var edges []entEdge
+	for _, t := range order {
+		edges = append(edges, r.edges(t)...)
+	}
+	for _, e := range edges {
+		if e.Inverse {
+			continue
+		}
+		var inverse *entEdge
+		for i := range edges {
+			candidate := &edges[i]
+			if candidate.Inverse && candidate.Owner == e.Target && candidate.Target == e.Owner && candidate.Ref == e.Name {
+				inverse = candidate
+				break
+			}
+		}
+		r.relate(e, inverse)
+	}
+}
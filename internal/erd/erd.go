@@ -0,0 +1,260 @@
+package erd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"doclific/internal/core"
+
+	"github.com/google/uuid"
+)
+
+// Relationship types understood by the <ERD> component
+const (
+	OneToOne   = "one-to-one"
+	OneToMany  = "one-to-many"
+	ManyToOne  = "many-to-one"
+	ManyToMany = "many-to-many"
+)
+
+// Column is a column of an ERD table
+type Column struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	Nullable   bool   `json:"nullable"`
+	PrimaryKey bool   `json:"primaryKey"`
+	Unique     bool   `json:"unique"`
+}
+
+// TableData is the data of an ERD table node
+type TableData struct {
+	Name    string   `json:"name"`
+	Columns []Column `json:"columns"`
+}
+
+// Position is the canvas position of an ERD table node
+type Position struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// Table is a table node of an <ERD> block
+type Table struct {
+	ID       string    `json:"id"`
+	Type     string    `json:"type"`
+	Data     TableData `json:"data"`
+	Position Position  `json:"position"`
+}
+
+// RelationshipData is the data of an ERD edge
+type RelationshipData struct {
+	Type string `json:"type"` // one-to-one, one-to-many, many-to-one, many-to-many
+}
+
+// Relationship is an edge between two columns of an <ERD> block
+type Relationship struct {
+	ID           string           `json:"id"`
+	Type         string           `json:"type"`
+	Source       string           `json:"source"`
+	SourceHandle string           `json:"sourceHandle"`
+	Target       string           `json:"target"`
+	TargetHandle string           `json:"targetHandle"`
+	Animated     bool             `json:"animated"`
+	MarkerStart  string           `json:"markerStart,omitempty"`
+	MarkerEnd    string           `json:"markerEnd,omitempty"`
+	Data         RelationshipData `json:"data"`
+}
+
+// Diagram is the content of an <ERD> block
+type Diagram struct {
+	Tables        []Table        `json:"tables"`
+	Relationships []Relationship `json:"relationships"`
+	Source        string         `json:"source,omitempty"` // migration directory or Go package the diagram is generated from
+}
+
+// NewTable returns a table node with fresh IDs for the table and its columns
+func NewTable(name string, columns []Column, position Position) Table {
+	table := Table{
+		ID:       uuid.New().String(),
+		Type:     "tableNode",
+		Data:     TableData{Name: name, Columns: make([]Column, len(columns))},
+		Position: position,
+	}
+	for i, column := range columns {
+		column.ID = uuid.New().String()
+		table.Data.Columns[i] = column
+	}
+	return table
+}
+
+// claw returns the crow's foot marker drawn on the given side of a table
+func claw(side string) string {
+	if side == "r" {
+		return "claw-right"
+	}
+	return "claw-left"
+}
+
+// NewRelationship connects two columns the same way validate-and-encode.js does:
+// handles face each other based on the tables' x positions and "many" ends get a claw marker
+func NewRelationship(source *Table, sourceColumn *Column, target *Table, targetColumn *Column, relationshipType string) Relationship {
+	sourceSide, targetSide := "r", "l"
+	if source.Position.X > target.Position.X {
+		sourceSide, targetSide = "l", "r"
+	}
+
+	relationship := Relationship{
+		ID:           uuid.New().String(),
+		Type:         "default",
+		Source:       source.ID,
+		SourceHandle: fmt.Sprintf("col-%s-source-%s", sourceColumn.ID, sourceSide),
+		Target:       target.ID,
+		TargetHandle: fmt.Sprintf("col-%s-target-%s", targetColumn.ID, targetSide),
+		Data:         RelationshipData{Type: relationshipType},
+	}
+	switch relationshipType {
+	case OneToMany:
+		relationship.MarkerEnd = claw(targetSide)
+	case ManyToOne:
+		relationship.MarkerStart = claw(sourceSide)
+	case ManyToMany:
+		relationship.MarkerStart = claw(sourceSide)
+		relationship.MarkerEnd = claw(targetSide)
+	}
+	return relationship
+}
+
+// Table returns the table with the given name, or nil
+func (d *Diagram) Table(name string) *Table {
+	for i := range d.Tables {
+		if d.Tables[i].Data.Name == name {
+			return &d.Tables[i]
+		}
+	}
+	return nil
+}
+
+// TableByID returns the table with the given node ID, or nil
+func (d *Diagram) TableByID(id string) *Table {
+	for i := range d.Tables {
+		if d.Tables[i].ID == id {
+			return &d.Tables[i]
+		}
+	}
+	return nil
+}
+
+// Column returns the column with the given name, or nil
+func (t *Table) Column(name string) *Column {
+	for i := range t.Data.Columns {
+		if t.Data.Columns[i].Name == name {
+			return &t.Data.Columns[i]
+		}
+	}
+	return nil
+}
+
+// ColumnByHandle returns the column a relationship handle such as "col-<id>-source-r" points at, or nil
+func (t *Table) ColumnByHandle(handle string) *Column {
+	id := strings.TrimPrefix(handle, "col-")
+	for _, suffix := range []string{"-source-l", "-source-r", "-target-l", "-target-r"} {
+		id = strings.TrimSuffix(id, suffix)
+	}
+	for i := range t.Data.Columns {
+		if t.Data.Columns[i].ID == id {
+			return &t.Data.Columns[i]
+		}
+	}
+	return nil
+}
+
+// Edge is a relationship resolved to table and column names
+type Edge struct {
+	SourceTable, SourceColumn string
+	TargetTable, TargetColumn string
+	Type                      string
+}
+
+// Edges resolves relationships to table and column names, skipping any that point at missing tables
+// A column name is empty if its handle points at a missing column
+func (d *Diagram) Edges() []Edge {
+	var edges []Edge
+	for _, relationship := range d.Relationships {
+		source := d.TableByID(relationship.Source)
+		target := d.TableByID(relationship.Target)
+		if source == nil || target == nil {
+			continue
+		}
+		edge := Edge{SourceTable: source.Data.Name, TargetTable: target.Data.Name, Type: relationship.Data.Type}
+		if column := source.ColumnByHandle(relationship.SourceHandle); column != nil {
+			edge.SourceColumn = column.Name
+		}
+		if column := target.ColumnByHandle(relationship.TargetHandle); column != nil {
+			edge.TargetColumn = column.Name
+		}
+		edges = append(edges, edge)
+	}
+	return edges
+}
+
+// Parse reads the tables and relationships attributes of an <ERD> block (already HTML-unescaped)
+func Parse(tables string, relationships string) (*Diagram, error) {
+	diagram := &Diagram{Tables: []Table{}, Relationships: []Relationship{}}
+	if tables != "" {
+		if err := json.Unmarshal([]byte(tables), &diagram.Tables); err != nil {
+			return nil, fmt.Errorf("failed to parse tables: %w", err)
+		}
+	}
+	if relationships != "" {
+		if err := json.Unmarshal([]byte(relationships), &diagram.Relationships); err != nil {
+			return nil, fmt.Errorf("failed to parse relationships: %w", err)
+		}
+	}
+	return diagram, nil
+}
+
+// ParseComponent reads the diagram of an <ERD> block found by core.ParseMDXComponents
+func ParseComponent(component core.MDXComponent) (*Diagram, error) {
+	diagram, err := Parse(component.Attributes["tables"], component.Attributes["relationships"])
+	if err != nil {
+		return nil, err
+	}
+	diagram.Source = component.Attributes["source"]
+	return diagram, nil
+}
+
+// Attributes returns the encoded tables and relationships attributes of the diagram
+func (d *Diagram) Attributes() (string, string, error) {
+	tables := d.Tables
+	if tables == nil {
+		tables = []Table{}
+	}
+	relationships := d.Relationships
+	if relationships == nil {
+		relationships = []Relationship{}
+	}
+
+	tablesJSON, err := json.Marshal(tables)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to encode tables: %w", err)
+	}
+	relationshipsJSON, err := json.Marshal(relationships)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to encode relationships: %w", err)
+	}
+	return core.EncodeAttribute(string(tablesJSON)), core.EncodeAttribute(string(relationshipsJSON)), nil
+}
+
+// MDX renders the diagram as an <ERD> block ready to paste into a doc
+func (d *Diagram) MDX() (string, error) {
+	tables, relationships, err := d.Attributes()
+	if err != nil {
+		return "", err
+	}
+	if d.Source != "" {
+		return fmt.Sprintf("<ERD tables=\"%s\" relationships=\"%s\" source=\"%s\"></ERD>", tables, relationships, core.EncodeAttribute(d.Source)), nil
+	}
+	return fmt.Sprintf("<ERD tables=\"%s\" relationships=\"%s\"></ERD>", tables, relationships), nil
+}
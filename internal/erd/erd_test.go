@@ -0,0 +1,136 @@
+package erd
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeType(t *testing.T) {
+	tests := map[string]string{
+		"INT":                         "integer",
+		"int8":                        "bigint",
+		"character varying(255)":      "varchar",
+		"numeric(10, 2)":              "numeric",
+		"timestamp without time zone": "timestamp",
+		"timestamp(3) with time zone": "timestamptz",
+		"double precision":            "double precision",
+		"bool":                        "boolean",
+		"text[]":                      "text",
+		"integer ARRAY":               "integer",
+		"interval day to second":      "interval",
+		"pg_catalog.int4":             "integer",
+		"bit varying(8)":              "bit varying",
+		"citext":                      "text",
+		"mood":                        "text",
+	}
+	for sqlType, want := range tests {
+		if got := NormalizeType(sqlType); got != want {
+			t.Errorf("NormalizeType(%q) = %q, want %q", sqlType, got, want)
+		}
+	}
+}
+
+func TestBuild(t *testing.T) {
+	schema, err := ParseSQL(`
+CREATE TABLE users (id serial PRIMARY KEY);
+CREATE TABLE profiles (user_id int PRIMARY KEY REFERENCES users);
+CREATE TABLE posts (id serial PRIMARY KEY, user_id int NOT NULL REFERENCES users (id), editor_id int REFERENCES users (id));
+CREATE TABLE comments (id serial PRIMARY KEY, post_id int REFERENCES posts, missing_id int REFERENCES missing);
+`)
+	if err != nil {
+		t.Fatalf("ParseSQL() error = %v", err)
+	}
+
+	diagram := Build(schema)
+	if len(diagram.Tables) != 4 {
+		t.Fatalf("Build() tables = %d, want 4", len(diagram.Tables))
+	}
+	if len(diagram.Relationships) != 4 {
+		t.Fatalf("Build() relationships = %d, want 4 (references to missing tables are dropped)", len(diagram.Relationships))
+	}
+
+	for _, table := range diagram.Tables {
+		if table.ID == "" || table.Type != "tableNode" {
+			t.Errorf("table %s has id %q and type %q", table.Data.Name, table.ID, table.Type)
+		}
+		if table.Position.X < -maxCoord || table.Position.X > maxCoord || table.Position.Y < -maxCoord || table.Position.Y > maxCoord {
+			t.Errorf("table %s position %+v is outside the canvas", table.Data.Name, table.Position)
+		}
+		for _, column := range table.Data.Columns {
+			if !IsDataType(column.Type) {
+				t.Errorf("%s.%s has type %q outside the ERD enum", table.Data.Name, column.Name, column.Type)
+			}
+		}
+	}
+
+	kinds := map[string]string{}
+	for _, relationship := range diagram.Relationships {
+		source := diagram.TableByID(relationship.Source)
+		target := diagram.TableByID(relationship.Target)
+		sourceColumn := source.ColumnByHandle(relationship.SourceHandle)
+		targetColumn := target.ColumnByHandle(relationship.TargetHandle)
+		if sourceColumn == nil || targetColumn == nil {
+			t.Fatalf("relationship %+v has handles that do not match columns", relationship)
+		}
+		kinds[fmt.Sprintf("%s.%s>%s.%s", source.Data.Name, sourceColumn.Name, target.Data.Name, targetColumn.Name)] = relationship.Data.Type
+
+		sourceSide := "r"
+		if source.Position.X > target.Position.X {
+			sourceSide = "l"
+		}
+		if !strings.HasSuffix(relationship.SourceHandle, "-source-"+sourceSide) {
+			t.Errorf("relationship %s does not face its target", relationship.SourceHandle)
+		}
+		if relationship.Data.Type == ManyToOne && relationship.MarkerStart != claw(sourceSide) {
+			t.Errorf("many-to-one relationship has markerStart %q, want %q", relationship.MarkerStart, claw(sourceSide))
+		}
+	}
+
+	want := map[string]string{
+		"profiles.user_id>users.id": OneToOne,
+		"posts.user_id>users.id":    ManyToOne,
+		"posts.editor_id>users.id":  ManyToOne,
+		"comments.post_id>posts.id": ManyToOne,
+	}
+	for edge, kind := range want {
+		if kinds[edge] != kind {
+			t.Errorf("relationship %s = %q, want %q", edge, kinds[edge], kind)
+		}
+	}
+}
+
+func TestLayoutLargeSchema(t *testing.T) {
+	schema := &Schema{}
+	for i := range 60 {
+		table := &SchemaTable{Name: fmt.Sprintf("table_%d", i)}
+		for j := range 12 {
+			table.Columns = append(table.Columns, &SchemaColumn{Name: fmt.Sprintf("column_%d", j), Type: "text"})
+		}
+		schema.Tables = append(schema.Tables, table)
+	}
+
+	diagram := Build(schema)
+	seen := map[Position]bool{}
+	for _, table := range diagram.Tables {
+		position := table.Position
+		if position.X < -maxCoord || position.X > maxCoord || position.Y < -maxCoord || position.Y > maxCoord {
+			t.Errorf("table %s position %+v is outside the canvas", table.Data.Name, position)
+		}
+		if seen[position] {
+			t.Errorf("two tables share position %+v", position)
+		}
+		seen[position] = true
+	}
+}
+
+func TestMDX(t *testing.T) {
+	diagram := &Diagram{Tables: []Table{NewTable("café", []Column{{Name: "id", Type: "integer"}}, Position{})}}
+	mdx, err := diagram.MDX()
+	if err != nil {
+		t.Fatalf("MDX() error = %v", err)
+	}
+	if !strings.HasPrefix(mdx, `<ERD tables="[{&#x22;id&#x22;:`) || !strings.Contains(mdx, `caf&#xE9;`) || !strings.HasSuffix(mdx, `relationships="[]"></ERD>`) {
+		t.Errorf("MDX() = %s", mdx)
+	}
+}
@@ -0,0 +1,401 @@
+package erd
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"doclific/internal/core"
+
+	"github.com/awalterschulze/gographviz"
+)
+
+// ErrBlockNotFound is returned by DocDiagram when a doc has fewer <ERD> blocks than the index asked for
+var ErrBlockNotFound = errors.New("ERD block not found")
+
+// DocDiagram reads the index-th <ERD> block (0-based, in document order) of a doc
+func DocDiagram(filePath string, index int) (*Diagram, error) {
+	content, err := core.GetDoc(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	components := core.ParseMDXComponents(content, core.ComponentERD)
+	if index < 0 || index >= len(components) {
+		return nil, fmt.Errorf("%w: %s has %d ERD block(s)", ErrBlockNotFound, filePath, len(components))
+	}
+	return ParseComponent(components[index])
+}
+
+// ExportFormats are the formats Diagram.Export renders
+var ExportFormats = []string{"sql", "mermaid", "dbml", "dot"}
+
+// Export renders the diagram in one of ExportFormats
+func (d *Diagram) Export(format string) (string, error) {
+	switch format {
+	case "sql":
+		return d.SQL(), nil
+	case "mermaid":
+		return d.Mermaid(), nil
+	case "dbml":
+		return d.DBML(), nil
+	case "dot":
+		return d.DOT()
+	}
+	return "", fmt.Errorf("unknown export format %q (use %s)", format, strings.Join(ExportFormats, ", "))
+}
+
+// plainIdentifier matches names that need no quoting in SQL or DBML
+var plainIdentifier = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// sqlReservedWords are PostgreSQL keywords that cannot be used as unquoted table or column names
+var sqlReservedWords = map[string]bool{
+	"all": true, "analyse": true, "analyze": true, "and": true, "any": true, "array": true, "as": true,
+	"asc": true, "both": true, "case": true, "cast": true, "check": true, "collate": true, "column": true,
+	"constraint": true, "create": true, "current_date": true, "current_time": true, "current_timestamp": true,
+	"current_user": true, "default": true, "desc": true, "distinct": true, "do": true, "else": true,
+	"end": true, "except": true, "false": true, "fetch": true, "for": true, "foreign": true, "from": true,
+	"grant": true, "group": true, "having": true, "in": true, "initially": true, "intersect": true,
+	"into": true, "lateral": true, "leading": true, "limit": true, "localtime": true, "localtimestamp": true,
+	"not": true, "null": true, "offset": true, "on": true, "only": true, "or": true, "order": true,
+	"placing": true, "primary": true, "references": true, "returning": true, "select": true,
+	"session_user": true, "some": true, "symmetric": true, "table": true, "then": true, "to": true,
+	"trailing": true, "true": true, "union": true, "unique": true, "user": true, "using": true,
+	"variadic": true, "when": true, "where": true, "window": true, "with": true,
+}
+
+// sqlIdentifier quotes a table or column name when PostgreSQL would not accept it bare
+func sqlIdentifier(name string) string {
+	if plainIdentifier.MatchString(name) && !sqlReservedWords[name] {
+		return name
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// columnType returns a column's type, defaulting to text like the editor does
+func columnType(column Column) string {
+	if column.Type == "" {
+		return "text"
+	}
+	return column.Type
+}
+
+// primaryKeyColumns returns the names of a table's primary key columns
+func primaryKeyColumns(table Table) []string {
+	var names []string
+	for _, column := range table.Data.Columns {
+		if column.PrimaryKey {
+			names = append(names, column.Name)
+		}
+	}
+	return names
+}
+
+// SQL renders the diagram as PostgreSQL DDL: a CREATE TABLE per table followed by the foreign keys,
+// added with ALTER TABLE so tables can reference each other in any order
+// Many-to-many relationships have no column to hold a key and are left as comments
+func (d *Diagram) SQL() string {
+	var b strings.Builder
+	for i, table := range d.Tables {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+
+		primaryKey := primaryKeyColumns(table)
+		var lines []string
+		for _, column := range table.Data.Columns {
+			line := "    " + sqlIdentifier(column.Name) + " " + columnType(column)
+			if column.PrimaryKey && len(primaryKey) == 1 {
+				line += " PRIMARY KEY"
+			} else {
+				if !column.Nullable {
+					line += " NOT NULL"
+				}
+				if column.Unique {
+					line += " UNIQUE"
+				}
+			}
+			lines = append(lines, line)
+		}
+		if len(primaryKey) > 1 {
+			quoted := make([]string, len(primaryKey))
+			for i, name := range primaryKey {
+				quoted[i] = sqlIdentifier(name)
+			}
+			lines = append(lines, "    PRIMARY KEY ("+strings.Join(quoted, ", ")+")")
+		}
+
+		if len(lines) == 0 {
+			fmt.Fprintf(&b, "CREATE TABLE %s ();\n", sqlIdentifier(table.Data.Name))
+			continue
+		}
+		fmt.Fprintf(&b, "CREATE TABLE %s (\n%s\n);\n", sqlIdentifier(table.Data.Name), strings.Join(lines, ",\n"))
+	}
+
+	var constraints []string
+	seen := map[string]bool{}
+	for _, edge := range d.Edges() {
+		if edge.SourceColumn == "" || edge.TargetColumn == "" {
+			continue
+		}
+
+		var statement string
+		switch edge.Type {
+		case ManyToMany:
+			statement = fmt.Sprintf("-- %s.%s and %s.%s are many-to-many; add a join table to connect them",
+				edge.SourceTable, edge.SourceColumn, edge.TargetTable, edge.TargetColumn)
+		case OneToMany:
+			// The "many" side holds the key
+			statement = foreignKeySQL(edge.TargetTable, edge.TargetColumn, edge.SourceTable, edge.SourceColumn)
+		default:
+			statement = foreignKeySQL(edge.SourceTable, edge.SourceColumn, edge.TargetTable, edge.TargetColumn)
+		}
+		if !seen[statement] {
+			seen[statement] = true
+			constraints = append(constraints, statement)
+		}
+	}
+	if len(constraints) > 0 {
+		b.WriteString("\n" + strings.Join(constraints, "\n") + "\n")
+	}
+
+	return b.String()
+}
+
+// foreignKeySQL adds a foreign key named the way PostgreSQL names them by default
+func foreignKeySQL(table string, column string, refTable string, refColumn string) string {
+	return fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s (%s);",
+		sqlIdentifier(table), sqlIdentifier(table+"_"+column+"_fkey"), sqlIdentifier(column),
+		sqlIdentifier(refTable), sqlIdentifier(refColumn))
+}
+
+// mermaidCardinality maps relationship types to Mermaid crow's foot notation
+var mermaidCardinality = map[string]string{
+	OneToOne:   "||--||",
+	OneToMany:  "||--o{",
+	ManyToOne:  "}o--||",
+	ManyToMany: "}o--o{",
+}
+
+// mermaidIdentifier makes a name safe to use as a Mermaid entity, type or attribute name
+func mermaidIdentifier(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' || r == '.' || r == '"' {
+			return '_'
+		}
+		return r
+	}, name)
+}
+
+// mermaidLabel escapes double quotes, which would end a quoted Mermaid label
+func mermaidLabel(text string) string {
+	return strings.ReplaceAll(text, `"`, "#quot;")
+}
+
+// Mermaid renders the diagram as the body of a Mermaid erDiagram, without a code fence
+func (d *Diagram) Mermaid() string {
+	var b strings.Builder
+	b.WriteString("erDiagram\n")
+	for _, table := range d.Tables {
+		fmt.Fprintf(&b, "    %s {\n", mermaidIdentifier(table.Data.Name))
+		for _, column := range table.Data.Columns {
+			var keys []string
+			if column.PrimaryKey {
+				keys = append(keys, "PK")
+			}
+			if column.Unique {
+				keys = append(keys, "UK")
+			}
+			line := fmt.Sprintf("        %s %s", mermaidIdentifier(columnType(column)), mermaidIdentifier(column.Name))
+			if len(keys) > 0 {
+				line += " " + strings.Join(keys, ", ")
+			}
+			if column.Nullable {
+				line += ` "nullable"`
+			}
+			b.WriteString(line + "\n")
+		}
+		b.WriteString("    }\n")
+	}
+	for _, edge := range d.Edges() {
+		cardinality, ok := mermaidCardinality[edge.Type]
+		if !ok {
+			cardinality = "||--||"
+		}
+		fmt.Fprintf(&b, "    %s %s %s : \"%s to %s\"\n",
+			mermaidIdentifier(edge.SourceTable), cardinality, mermaidIdentifier(edge.TargetTable),
+			mermaidLabel(edge.SourceColumn), mermaidLabel(edge.TargetColumn))
+	}
+	return b.String()
+}
+
+// dbmlRelations maps relationship types to DBML Ref operators
+var dbmlRelations = map[string]string{
+	OneToOne:   "-",
+	OneToMany:  "<",
+	ManyToOne:  ">",
+	ManyToMany: "<>",
+}
+
+// dbmlIdentifier double-quotes a name or type that DBML would not accept bare
+func dbmlIdentifier(name string) string {
+	if plainIdentifier.MatchString(strings.ToLower(name)) {
+		return name
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `\"`) + `"`
+}
+
+// DBML renders the diagram as DBML tables and refs, as read by dbdiagram.io
+func (d *Diagram) DBML() string {
+	var b strings.Builder
+	for i, table := range d.Tables {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+
+		primaryKey := primaryKeyColumns(table)
+		fmt.Fprintf(&b, "Table %s {\n", dbmlIdentifier(table.Data.Name))
+		for _, column := range table.Data.Columns {
+			var settings []string
+			if column.PrimaryKey && len(primaryKey) == 1 {
+				settings = append(settings, "pk")
+			} else {
+				if !column.Nullable {
+					settings = append(settings, "not null")
+				}
+				if column.Unique {
+					settings = append(settings, "unique")
+				}
+			}
+			line := fmt.Sprintf("  %s %s", dbmlIdentifier(column.Name), dbmlIdentifier(columnType(column)))
+			if len(settings) > 0 {
+				line += " [" + strings.Join(settings, ", ") + "]"
+			}
+			b.WriteString(line + "\n")
+		}
+		if len(primaryKey) > 1 {
+			quoted := make([]string, len(primaryKey))
+			for i, name := range primaryKey {
+				quoted[i] = dbmlIdentifier(name)
+			}
+			fmt.Fprintf(&b, "\n  indexes {\n    (%s) [pk]\n  }\n", strings.Join(quoted, ", "))
+		}
+		b.WriteString("}\n")
+	}
+
+	var refs []string
+	for _, edge := range d.Edges() {
+		if edge.SourceColumn == "" || edge.TargetColumn == "" {
+			continue
+		}
+		relation, ok := dbmlRelations[edge.Type]
+		if !ok {
+			relation = "-"
+		}
+		refs = append(refs, fmt.Sprintf("Ref: %s.%s %s %s.%s",
+			dbmlIdentifier(edge.SourceTable), dbmlIdentifier(edge.SourceColumn), relation,
+			dbmlIdentifier(edge.TargetTable), dbmlIdentifier(edge.TargetColumn)))
+	}
+	if len(refs) > 0 {
+		b.WriteString("\n" + strings.Join(refs, "\n") + "\n")
+	}
+
+	return b.String()
+}
+
+// dotArrows maps relationship types to the Graphviz arrows drawn at the source and target ends
+var dotArrows = map[string][2]string{
+	OneToOne:   {"tee", "tee"},
+	OneToMany:  {"tee", "crow"},
+	ManyToOne:  {"crow", "tee"},
+	ManyToMany: {"crow", "crow"},
+}
+
+// dotNodeName returns a table's DOT node ID, always quoted so that keywords, blank names
+// and names with quotes or backslashes stay a single ID
+func dotNodeName(name string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(name) + `"`
+}
+
+// DOT renders the diagram as a Graphviz digraph with one HTML-table node per table
+// and crow's foot edges between the related columns
+func (d *Diagram) DOT() (string, error) {
+	graph := gographviz.NewGraph()
+	if err := graph.SetName("erd"); err != nil {
+		return "", err
+	}
+	if err := graph.SetDir(true); err != nil {
+		return "", err
+	}
+	if err := graph.AddAttr("erd", "rankdir", "LR"); err != nil {
+		return "", err
+	}
+
+	for _, table := range d.Tables {
+		var label strings.Builder
+		label.WriteString(`<<table border="0" cellborder="1" cellspacing="0" cellpadding="4">`)
+		fmt.Fprintf(&label, `<tr><td bgcolor="lightgrey"><b>%s</b></td></tr>`, html.EscapeString(table.Data.Name))
+		for i, column := range table.Data.Columns {
+			var flags []string
+			if column.PrimaryKey {
+				flags = append(flags, "PK")
+			}
+			if column.Unique {
+				flags = append(flags, "UQ")
+			}
+			if column.Nullable {
+				flags = append(flags, "NULL")
+			}
+			fmt.Fprintf(&label, `<tr><td port="c%d" align="left">%s <i>%s</i> %s</td></tr>`,
+				i, html.EscapeString(column.Name), html.EscapeString(columnType(column)), strings.Join(flags, " "))
+		}
+		label.WriteString(`</table>>`)
+
+		attrs := map[string]string{"shape": "plaintext", "label": label.String()}
+		if err := graph.AddNode("erd", dotNodeName(table.Data.Name), attrs); err != nil {
+			return "", err
+		}
+	}
+
+	for _, relationship := range d.Relationships {
+		source := d.TableByID(relationship.Source)
+		target := d.TableByID(relationship.Target)
+		if source == nil || target == nil {
+			continue
+		}
+
+		arrows, ok := dotArrows[relationship.Data.Type]
+		if !ok {
+			arrows = [2]string{"none", "none"}
+		}
+		attrs := map[string]string{"dir": "both", "arrowtail": arrows[0], "arrowhead": arrows[1]}
+		err := graph.AddPortEdge(dotNodeName(source.Data.Name), columnPort(source, relationship.SourceHandle),
+			dotNodeName(target.Data.Name), columnPort(target, relationship.TargetHandle), true, attrs)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	// Graph.String hides write errors (such as an edge to a duplicate table) in its output
+	written, err := graph.WriteAst()
+	if err != nil {
+		return "", err
+	}
+	return written.String(), nil
+}
+
+// columnPort returns the DOT port of the column a handle points at, or "" to attach to the whole table
+func columnPort(table *Table, handle string) string {
+	column := table.ColumnByHandle(handle)
+	if column == nil {
+		return ""
+	}
+	for i := range table.Data.Columns {
+		if &table.Data.Columns[i] == column {
+			return fmt.Sprintf("c%d", i)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,157 @@
+package erd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/awalterschulze/gographviz"
+)
+
+// exportDiagram is a small schema covering single and composite keys, a reserved table name and each relationship type
+func exportDiagram(t *testing.T) *Diagram {
+	t.Helper()
+	schema, err := ParseSQL(`
+CREATE TABLE users (id uuid PRIMARY KEY, email varchar NOT NULL UNIQUE, nickname text);
+CREATE TABLE "order" (id bigint PRIMARY KEY, user_id uuid NOT NULL REFERENCES users (id), total "double precision");
+CREATE TABLE order_tags (order_id bigint REFERENCES "order" (id), tag text, PRIMARY KEY (order_id, tag));
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Build(schema)
+}
+
+func TestExportSQL(t *testing.T) {
+	diagram := exportDiagram(t)
+	sql := diagram.SQL()
+
+	for _, want := range []string{
+		"CREATE TABLE users (\n    id uuid PRIMARY KEY,\n    email varchar NOT NULL UNIQUE,\n    nickname text\n);",
+		`CREATE TABLE "order" (`,
+		"    total double precision",
+		"    PRIMARY KEY (order_id, tag)",
+		`ALTER TABLE "order" ADD CONSTRAINT order_user_id_fkey FOREIGN KEY (user_id) REFERENCES users (id);`,
+	} {
+		if !strings.Contains(sql, want) {
+			t.Errorf("SQL() missing %q:\n%s", want, sql)
+		}
+	}
+
+	// The DDL reads back as the same schema
+	schema, err := ParseSQL(sql)
+	if err != nil {
+		t.Fatalf("ParseSQL(SQL()) error = %v", err)
+	}
+	if _, changes := Merge(diagram, Build(schema)); len(changes) != 0 {
+		t.Errorf("SQL() did not round-trip: %v", changes)
+	}
+}
+
+func TestExportOneToMany(t *testing.T) {
+	diagram := &Diagram{}
+	diagram.Tables = []Table{
+		NewTable("users", []Column{{Name: "id", Type: "bigint", PrimaryKey: true}}, Position{}),
+		NewTable("posts", []Column{{Name: "author_id", Type: "bigint"}}, Position{X: 300}),
+	}
+	users, posts := &diagram.Tables[0], &diagram.Tables[1]
+	diagram.Relationships = []Relationship{NewRelationship(users, users.Column("id"), posts, posts.Column("author_id"), OneToMany)}
+
+	if sql := diagram.SQL(); !strings.Contains(sql, "ALTER TABLE posts ADD CONSTRAINT posts_author_id_fkey FOREIGN KEY (author_id) REFERENCES users (id);") {
+		t.Errorf("SQL() should put the key on the many side:\n%s", sql)
+	}
+	if dbml := diagram.DBML(); !strings.Contains(dbml, "Ref: users.id < posts.author_id") {
+		t.Errorf("DBML() = %s", dbml)
+	}
+}
+
+func TestExportDBML(t *testing.T) {
+	dbml := exportDiagram(t).DBML()
+	for _, want := range []string{
+		"Table users {\n  id uuid [pk]\n  email varchar [not null, unique]\n  nickname text\n}",
+		`  total "double precision"`,
+		"  indexes {\n    (order_id, tag) [pk]\n  }",
+		"Ref: order.user_id > users.id",
+	} {
+		if !strings.Contains(dbml, want) {
+			t.Errorf("DBML() missing %q:\n%s", want, dbml)
+		}
+	}
+}
+
+func TestExportMermaid(t *testing.T) {
+	mermaid := exportDiagram(t).Mermaid()
+	for _, want := range []string{
+		"erDiagram\n    users {\n        uuid id PK\n        varchar email UK\n        text nickname \"nullable\"\n    }",
+		"        double_precision total \"nullable\"",
+		`    order }o--|| users : "user_id to id"`,
+	} {
+		if !strings.Contains(mermaid, want) {
+			t.Errorf("Mermaid() missing %q:\n%s", want, mermaid)
+		}
+	}
+}
+
+func TestExportMermaidUntyped(t *testing.T) {
+	diagram := &Diagram{}
+	diagram.Tables = []Table{
+		NewTable("users", []Column{{Name: "id", PrimaryKey: true}}, Position{}),
+		NewTable("posts", []Column{{Name: `"author"`}}, Position{X: 300}),
+	}
+	users, posts := &diagram.Tables[0], &diagram.Tables[1]
+	diagram.Relationships = []Relationship{NewRelationship(users, users.Column("id"), posts, posts.Column(`"author"`), OneToMany)}
+
+	mermaid := diagram.Mermaid()
+	for _, want := range []string{"        text id PK\n", "        text _author_\n", `: "id to #quot;author#quot;"`} {
+		if !strings.Contains(mermaid, want) {
+			t.Errorf("Mermaid() missing %q:\n%s", want, mermaid)
+		}
+	}
+}
+
+func TestExportDOTUnusualNames(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want string
+	}{
+		{`CREATE TABLE" "0"000"0000000`, `" " [`},
+		{`CREATE TABLE " " (id int PRIMARY KEY); CREATE TABLE "say ""hi"" \" (id int REFERENCES " " (id));`, `"say \"hi\" \\":c0->" ":c0`},
+	}
+	for _, tt := range tests {
+		schema, err := ParseSQL(tt.sql)
+		if err != nil {
+			t.Fatalf("ParseSQL(%q) error = %v", tt.sql, err)
+		}
+		dot, err := Build(schema).Export("dot")
+		if err != nil {
+			t.Fatalf("Export(dot) of %q error = %v", tt.sql, err)
+		}
+		if !strings.Contains(dot, tt.want) {
+			t.Errorf("DOT() of %q missing %q:\n%s", tt.sql, tt.want, dot)
+		}
+		if _, err := gographviz.Read([]byte(dot)); err != nil {
+			t.Errorf("DOT() of %q is not valid DOT: %v\n%s", tt.sql, err, dot)
+		}
+	}
+}
+
+func TestExportDOT(t *testing.T) {
+	dot, err := exportDiagram(t).Export("dot")
+	if err != nil {
+		t.Fatalf("Export(dot) error = %v", err)
+	}
+	for _, want := range []string{
+		"digraph erd {",
+		"rankdir=LR",
+		`<td port="c1" align="left">email <i>varchar</i> UQ</td>`,
+		`"order":c1->"users":c0`,
+		"arrowtail=crow",
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("DOT() missing %q:\n%s", want, dot)
+		}
+	}
+
+	if _, err := exportDiagram(t).Export("png"); err == nil {
+		t.Error("Export() should reject unknown formats")
+	}
+}
@@ -0,0 +1,238 @@
+package erd
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"doclific/internal/core"
+)
+
+// snakeCase converts a Go identifier to snake_case the way GORM and ent name columns ("UserID" -> "user_id")
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			// Start a word after a lower-case letter or digit, or at the last capital of an acronym ("HTTPServer")
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// pluralize returns the plural of a snake_case table name ("user" -> "users", "category" -> "categories")
+func pluralize(name string) string {
+	switch {
+	case strings.HasSuffix(name, "y") && len(name) > 1 && !strings.ContainsRune("aeiou", rune(name[len(name)-2])):
+		return name[:len(name)-1] + "ies"
+	case strings.HasSuffix(name, "s") || strings.HasSuffix(name, "x") || strings.HasSuffix(name, "z") ||
+		strings.HasSuffix(name, "ch") || strings.HasSuffix(name, "sh"):
+		return name + "es"
+	}
+	return name + "s"
+}
+
+// singularize undoes pluralize for the common cases
+func singularize(name string) string {
+	switch {
+	case strings.HasSuffix(name, "ies"):
+		return name[:len(name)-3] + "y"
+	case strings.HasSuffix(name, "ses") || strings.HasSuffix(name, "xes") || strings.HasSuffix(name, "ches") || strings.HasSuffix(name, "shes"):
+		return name[:len(name)-2]
+	case strings.HasSuffix(name, "s") && !strings.HasSuffix(name, "ss"):
+		return name[:len(name)-1]
+	}
+	return name
+}
+
+// goTypeName describes a field type: its name ("string", "time.Time", "User"), and whether it is a pointer or slice
+// []byte is reported as the name "[]byte"
+func goTypeName(expr ast.Expr) (name string, pointer bool, slice bool) {
+	for {
+		switch t := expr.(type) {
+		case *ast.StarExpr:
+			pointer = true
+			expr = t.X
+			continue
+		case *ast.ArrayType:
+			if ident, ok := t.Elt.(*ast.Ident); ok && t.Len == nil && (ident.Name == "byte" || ident.Name == "uint8") {
+				return "[]byte", pointer, slice
+			}
+			if t.Len == nil {
+				slice = true
+			}
+			expr = t.Elt
+			continue
+		case *ast.Ident:
+			return t.Name, pointer, slice
+		case *ast.SelectorExpr:
+			if pkg, ok := t.X.(*ast.Ident); ok {
+				return pkg.Name + "." + t.Sel.Name, pointer, slice
+			}
+		case *ast.IndexExpr:
+			expr = t.X // generic instantiation such as datatypes.JSONType[T]
+			continue
+		}
+		return "", pointer, slice
+	}
+}
+
+// stringLiteral returns the value of a string literal expression
+func stringLiteral(expr ast.Expr) (string, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	value, err := strconv.Unquote(lit.Value)
+	return value, err == nil
+}
+
+// receiverName returns the type name of a method's receiver
+func receiverName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return ""
+	}
+	name, _, _ := goTypeName(fn.Recv.List[0].Type)
+	return name
+}
+
+// returnedExpr returns the expression of a method's last return statement
+func returnedExpr(fn *ast.FuncDecl) ast.Expr {
+	if fn.Body == nil {
+		return nil
+	}
+	for i := len(fn.Body.List) - 1; i >= 0; i-- {
+		if ret, ok := fn.Body.List[i].(*ast.ReturnStmt); ok && len(ret.Results) == 1 {
+			return ret.Results[0]
+		}
+	}
+	return nil
+}
+
+// importName returns the name a file imports a package under, or "" if it does not import it
+func importName(file *ast.File, importPaths ...string) string {
+	for _, spec := range file.Imports {
+		importPath, _ := strconv.Unquote(spec.Path.Value)
+		for _, candidate := range importPaths {
+			if importPath != candidate {
+				continue
+			}
+			if spec.Name != nil {
+				return spec.Name.Name
+			}
+			return importPath[strings.LastIndex(importPath, "/")+1:]
+		}
+	}
+	return ""
+}
+
+// GoFiles expands paths into the Go files to read: files as given, directories as a package,
+// and directories ending in "/..." recursively; test files are skipped
+func GoFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, p := range paths {
+		recursive := strings.HasSuffix(filepath.ToSlash(p), "/...")
+		if recursive {
+			p = strings.TrimSuffix(strings.TrimSuffix(filepath.ToSlash(p), "..."), "/")
+			if p == "" {
+				p = "."
+			}
+		}
+
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", p, err)
+		}
+		if !info.IsDir() {
+			files = append(files, p)
+			continue
+		}
+
+		var found []string
+		err = filepath.WalkDir(p, func(filePath string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				if filePath != p && (!recursive || strings.HasPrefix(d.Name(), ".") || d.Name() == "vendor" || d.Name() == "testdata") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if strings.HasSuffix(d.Name(), ".go") && !strings.HasSuffix(d.Name(), "_test.go") {
+				found = append(found, filePath)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read directory %s: %w", p, err)
+		}
+		sort.Strings(found)
+		files = append(files, found...)
+	}
+	return files, nil
+}
+
+// GoModelFiles finds the Go files in the repository whose metadata hints mention GORM or ent
+func GoModelFiles() ([]string, error) {
+	metadata, err := core.GetFileListAndMetadata("", nil, "", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, file := range metadata {
+		if file.Ext != ".go" || strings.HasSuffix(file.Path, "_test.go") {
+			continue
+		}
+		for _, hint := range file.Hints {
+			if strings.Contains(hint, "gorm") || strings.Contains(hint, "entgo.io/ent") {
+				files = append(files, file.Path)
+				break
+			}
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// ParseGoModels reads the tables and foreign keys defined by GORM models and ent schemas in Go files
+// Directories are read as a single package unless they end in "/..."
+func ParseGoModels(paths []string) (*Schema, error) {
+	files, err := GoFiles(paths)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no .go files found")
+	}
+
+	fset := token.NewFileSet()
+	var parsed []*ast.File
+	for _, file := range files {
+		f, err := parser.ParseFile(fset, file, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+		parsed = append(parsed, f)
+	}
+
+	schema := &Schema{}
+	readGormModels(schema, parsed)
+	readEntSchemas(schema, parsed)
+	return schema, nil
+}
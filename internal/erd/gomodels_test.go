@@ -0,0 +1,329 @@
+package erd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeGoFiles writes Go sources into a temporary package directory
+func writeGoFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// findForeignKey returns the foreign key from table.column, or nil
+func findForeignKey(schema *Schema, table string, column string) *ForeignKey {
+	for i := range schema.ForeignKeys {
+		if schema.ForeignKeys[i].Table == table && schema.ForeignKeys[i].Column == column {
+			return &schema.ForeignKeys[i]
+		}
+	}
+	return nil
+}
+
+func TestSnakeCase(t *testing.T) {
+	tests := map[string]string{
+		"ID":         "id",
+		"UserID":     "user_id",
+		"HTTPServer": "http_server",
+		"CreatedAt":  "created_at",
+		"OAuth2Code": "o_auth2_code",
+		"name":       "name",
+	}
+	for name, want := range tests {
+		if got := snakeCase(name); got != want {
+			t.Errorf("snakeCase(%q) = %q, want %q", name, got, want)
+		}
+	}
+	if got := pluralize("category"); got != "categories" {
+		t.Errorf("pluralize(category) = %q", got)
+	}
+	if got := pluralize("address"); got != "addresses" {
+		t.Errorf("pluralize(address) = %q", got)
+	}
+}
+
+func TestParseGoModelsGorm(t *testing.T) {
+	dir := writeGoFiles(t, map[string]string{
+		"models.go": `package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Company struct {
+	ID   uint   ` + "`gorm:\"primaryKey\"`" + `
+	Name string ` + "`gorm:\"size:100;not null\"`" + `
+}
+
+func (Company) TableName() string { return "organizations" }
+
+type User struct {
+	gorm.Model
+	Email     string  ` + "`gorm:\"uniqueIndex\"`" + `
+	Nickname  *string
+	CompanyID uint
+	Company   Company
+	Profile   Profile
+	Posts     []Post
+	Languages []Language ` + "`gorm:\"many2many:user_languages;\"`" + `
+	internal  string
+	Ignored   string ` + "`gorm:\"-\"`" + `
+}
+
+type Profile struct {
+	ID     uuid.UUID ` + "`gorm:\"type:uuid;primaryKey\"`" + `
+	UserID uint
+	Bio    string ` + "`gorm:\"type:text\"`" + `
+}
+
+type Post struct {
+	ID       uint
+	UserID   uint
+	EditorID *uint
+	Title    string
+	Meta     map[string]any ` + "`gorm:\"serializer:json\"`" + `
+	Audit    Audit ` + "`gorm:\"embedded;embeddedPrefix:audit_\"`" + `
+}
+
+type Editor struct {
+	ID        uint ` + "`gorm:\"primaryKey\"`" + `
+	CreatedAt time.Time
+}
+
+type Language struct {
+	ID   uint ` + "`gorm:\"primaryKey\"`" + `
+	Code string ` + "`gorm:\"column:iso_code;unique\"`" + `
+}
+
+type Audit struct {
+	By string
+	At time.Time
+}
+`,
+	})
+
+	schema, err := ParseGoModels([]string{dir})
+	if err != nil {
+		t.Fatalf("ParseGoModels() error = %v", err)
+	}
+
+	for _, name := range []string{"organizations", "users", "profiles", "posts", "editors", "languages", "user_languages"} {
+		if schema.Table(name) == nil {
+			t.Errorf("table %s not found", name)
+		}
+	}
+	if schema.Table("audits") != nil {
+		t.Error("embedded struct Audit should not be a table")
+	}
+
+	users := schema.Table("users")
+	tests := []struct {
+		table, column, typ           string
+		nullable, primaryKey, unique bool
+	}{
+		{"users", "id", "bigint", false, true, false},
+		{"users", "deleted_at", "timestamptz", true, false, false},
+		{"users", "email", "text", false, false, true},
+		{"users", "nickname", "text", true, false, false},
+		{"organizations", "name", "varchar", false, false, false},
+		{"profiles", "id", "uuid", false, true, false},
+		{"posts", "id", "bigint", false, true, false},
+		{"posts", "editor_id", "bigint", true, false, false},
+		{"posts", "meta", "jsonb", false, false, false},
+		{"posts", "audit_by", "text", false, false, false},
+		{"languages", "iso_code", "text", false, false, true},
+	}
+	for _, tt := range tests {
+		column := schema.Table(tt.table).Column(tt.column)
+		if column == nil {
+			t.Errorf("%s.%s not found", tt.table, tt.column)
+			continue
+		}
+		if column.Type != tt.typ || column.Nullable != tt.nullable || column.PrimaryKey != tt.primaryKey || column.Unique != tt.unique {
+			t.Errorf("%s.%s = %+v, want type %s nullable %v pk %v unique %v",
+				tt.table, tt.column, *column, tt.typ, tt.nullable, tt.primaryKey, tt.unique)
+		}
+	}
+	for _, column := range []string{"internal", "ignored", "company", "posts"} {
+		if users.Column(column) != nil {
+			t.Errorf("users.%s should not be a column", column)
+		}
+	}
+
+	wantKeys := []struct{ table, column, refTable, kind string }{
+		{"users", "company_id", "organizations", ""},
+		{"profiles", "user_id", "users", OneToOne},
+		{"posts", "user_id", "users", ""},
+		{"posts", "editor_id", "editors", ""},
+		{"user_languages", "user_id", "users", ""},
+		{"user_languages", "language_id", "languages", ""},
+	}
+	for _, want := range wantKeys {
+		fk := findForeignKey(schema, want.table, want.column)
+		if fk == nil || fk.RefTable != want.refTable || fk.Type != want.kind {
+			t.Errorf("foreign key %s.%s = %+v, want reference to %s (%q)", want.table, want.column, fk, want.refTable, want.kind)
+		}
+	}
+
+	diagram := Build(schema)
+	if len(diagram.Relationships) != len(wantKeys) {
+		t.Errorf("Build() relationships = %d, want %d", len(diagram.Relationships), len(wantKeys))
+	}
+}
+
+func TestParseGoModelsEnt(t *testing.T) {
+	dir := writeGoFiles(t, map[string]string{
+		"user.go": `package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/mixin"
+	"github.com/google/uuid"
+)
+
+type User struct {
+	ent.Schema
+}
+
+func (User) Mixin() []ent.Mixin {
+	return []ent.Mixin{mixin.Time{}}
+}
+
+func (User) Fields() []ent.Field {
+	return []ent.Field{
+		field.UUID("id", uuid.UUID{}),
+		field.String("email").Unique(),
+		field.String("nickname").Optional(),
+		field.Float("balance").SchemaType(map[string]string{dialect.Postgres: "numeric(10,2)"}),
+	}
+}
+
+func (User) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.To("pets", Pet.Type),
+		edge.To("card", Card.Type).Unique(),
+		edge.To("groups", Group.Type),
+		edge.To("friends", User.Type),
+	}
+}
+
+func (User) Annotations() []schema.Annotation {
+	return []schema.Annotation{entsql.Annotation{Table: "accounts"}}
+}
+`,
+		"pet.go": `package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"github.com/google/uuid"
+)
+
+type Pet struct {
+	ent.Schema
+}
+
+func (Pet) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("name"),
+		field.UUID("owner_id", uuid.UUID{}).Optional(),
+	}
+}
+
+func (Pet) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("owner", User.Type).Ref("pets").Unique().Field("owner_id"),
+	}
+}
+`,
+		"card.go": `package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+)
+
+type Card struct {
+	ent.Schema
+}
+
+func (Card) Fields() []ent.Field {
+	return []ent.Field{field.Time("expired")}
+}
+
+func (Card) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("owner", User.Type).Ref("card").Unique().Required(),
+	}
+}
+
+type Group struct {
+	ent.Schema
+}
+
+func (Group) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("users", User.Type).Ref("groups"),
+	}
+}
+`,
+	})
+
+	schema, err := ParseGoModels([]string{dir})
+	if err != nil {
+		t.Fatalf("ParseGoModels() error = %v", err)
+	}
+
+	accounts := schema.Table("accounts")
+	if accounts == nil {
+		t.Fatalf("ParseGoModels() tables = %+v, want accounts from the entsql annotation", schema.Tables)
+	}
+	if id := accounts.Column("id"); id == nil || id.Type != "uuid" || !id.PrimaryKey {
+		t.Errorf("accounts.id = %+v, want a uuid primary key", id)
+	}
+	if column := accounts.Column("email"); column == nil || column.Type != "varchar" || !column.Unique {
+		t.Errorf("accounts.email = %+v", column)
+	}
+	if column := accounts.Column("nickname"); column == nil || !column.Nullable {
+		t.Errorf("accounts.nickname = %+v, want nullable", column)
+	}
+	if column := accounts.Column("balance"); column == nil || column.Type != "numeric" {
+		t.Errorf("accounts.balance = %+v, want numeric from SchemaType", column)
+	}
+	if accounts.Column("create_time") == nil || accounts.Column("update_time") == nil {
+		t.Error("mixin.Time columns missing")
+	}
+
+	if fk := findForeignKey(schema, "pets", "owner_id"); fk == nil || fk.RefTable != "accounts" || fk.Type != "" {
+		t.Errorf("pets.owner_id foreign key = %+v", fk)
+	}
+	if fk := findForeignKey(schema, "cards", "user_card"); fk == nil || fk.RefTable != "accounts" || fk.Type != OneToOne {
+		t.Errorf("cards.user_card foreign key = %+v", fk)
+	} else if column := schema.Table("cards").Column("user_card"); column.Type != "uuid" || column.Nullable {
+		t.Errorf("cards.user_card = %+v, want a required uuid", column)
+	}
+	if schema.Table("user_groups") == nil || findForeignKey(schema, "user_groups", "group_id") == nil {
+		t.Error("many-to-many join table user_groups missing")
+	}
+	if friends := schema.Table("user_friends"); friends == nil || friends.Column("friend_id") == nil {
+		t.Errorf("self-referencing join table = %+v", friends)
+	}
+}
@@ -0,0 +1,483 @@
+package erd
+
+import (
+	"go/ast"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// gormColumnTypes maps Go field types to ERD column types, following GORM's PostgreSQL defaults
+var gormColumnTypes = map[string]string{
+	"string":          "text",
+	"int":             "bigint",
+	"int64":           "bigint",
+	"uint":            "bigint",
+	"uint64":          "bigint",
+	"int32":           "integer",
+	"uint32":          "integer",
+	"rune":            "integer",
+	"int8":            "smallint",
+	"int16":           "smallint",
+	"uint8":           "smallint",
+	"uint16":          "smallint",
+	"byte":            "smallint",
+	"float64":         "double precision",
+	"float32":         "real",
+	"bool":            "boolean",
+	"time.Time":       "timestamptz",
+	"time.Duration":   "bigint",
+	"[]byte":          "bytea",
+	"json.RawMessage": "jsonb",
+	"uuid.UUID":       "uuid",
+	"decimal.Decimal": "numeric",
+	"datatypes.JSON":  "jsonb",
+	"datatypes.Date":  "date",
+	"datatypes.Time":  "time",
+	"datatypes.UUID":  "uuid",
+}
+
+// gormNullableTypes are field types that hold NULL without being pointers
+var gormNullableTypes = map[string]string{
+	"sql.NullString":         "text",
+	"sql.NullInt64":          "bigint",
+	"sql.NullInt32":          "integer",
+	"sql.NullInt16":          "smallint",
+	"sql.NullByte":           "smallint",
+	"sql.NullFloat64":        "double precision",
+	"sql.NullBool":           "boolean",
+	"sql.NullTime":           "timestamptz",
+	"gorm.DeletedAt":         "timestamptz",
+	"datatypes.JSONMap":      "jsonb",
+	"datatypes.JSONType":     "jsonb",
+	"datatypes.JSONSlice":    "jsonb",
+	"datatypes.NullJSONType": "jsonb",
+	"uuid.NullUUID":          "uuid",
+	"decimal.NullDecimal":    "numeric",
+}
+
+// gormStruct is a struct type declared in the files being read
+type gormStruct struct {
+	Name     string
+	Fields   []*ast.Field
+	GormName string // name the declaring file imports GORM under, or ""
+}
+
+// gormField is a struct field flattened from embedded structs
+type gormField struct {
+	GoName   string
+	Column   string
+	TypeName string
+	Pointer  bool
+	Slice    bool
+	Tag      map[string]string
+}
+
+// gormModel is a struct read as a GORM model
+type gormModel struct {
+	Name   string
+	Fields []gormField
+	Table  *SchemaTable
+}
+
+// gormTag parses a field's `gorm:"..."` tag; keys are lower-cased without underscores ("primary_key" -> "primarykey")
+func gormTag(field *ast.Field) map[string]string {
+	settings := map[string]string{}
+	if field.Tag == nil {
+		return settings
+	}
+	tag, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return settings
+	}
+	value, ok := reflect.StructTag(tag).Lookup("gorm")
+	if !ok {
+		return settings
+	}
+	for _, part := range strings.Split(value, ";") {
+		key, val, _ := strings.Cut(part, ":")
+		key = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(key), "_", ""))
+		if key != "" {
+			settings[key] = strings.TrimSpace(val)
+		}
+	}
+	return settings
+}
+
+// hasGormTag reports whether a field carries a gorm struct tag
+func hasGormTag(field *ast.Field) bool {
+	if field.Tag == nil {
+		return false
+	}
+	tag, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return false
+	}
+	_, ok := reflect.StructTag(tag).Lookup("gorm")
+	return ok
+}
+
+// gormReader turns the structs of a set of files into tables and foreign keys
+type gormReader struct {
+	structs map[string]*gormStruct
+	models  map[string]*gormModel
+	order   []*gormModel
+	schema  *Schema
+}
+
+// isModel reports whether a struct looks like a GORM model: it embeds gorm.Model, has gorm tags,
+// or is declared next to a GORM import and has an ID field
+func (r *gormReader) isModel(s *gormStruct) bool {
+	for _, field := range s.Fields {
+		typeName, _, _ := goTypeName(field.Type)
+		if len(field.Names) == 0 && s.GormName != "" && typeName == s.GormName+".Model" {
+			return true
+		}
+		if hasGormTag(field) {
+			return true
+		}
+		if s.GormName != "" {
+			for _, name := range field.Names {
+				if name.Name == "ID" {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// fields flattens a struct's exported fields, inlining gorm.Model and embedded structs
+func (r *gormReader) fields(s *gormStruct, prefix string, depth int) []gormField {
+	var fields []gormField
+	if depth > 5 {
+		return fields // guard against recursive embedding
+	}
+
+	for _, field := range s.Fields {
+		tag := gormTag(field)
+		if _, ignored := tag["-"]; ignored {
+			continue
+		}
+		typeName, pointer, slice := goTypeName(field.Type)
+
+		if len(field.Names) == 0 {
+			if s.GormName != "" && typeName == s.GormName+".Model" {
+				fields = append(fields,
+					gormField{GoName: "ID", Column: prefix + "id", TypeName: "uint", Tag: map[string]string{"primarykey": ""}},
+					gormField{GoName: "CreatedAt", Column: prefix + "created_at", TypeName: "time.Time", Tag: map[string]string{}},
+					gormField{GoName: "UpdatedAt", Column: prefix + "updated_at", TypeName: "time.Time", Tag: map[string]string{}},
+					gormField{GoName: "DeletedAt", Column: prefix + "deleted_at", TypeName: "gorm.DeletedAt", Tag: map[string]string{}},
+				)
+			} else if embedded, ok := r.structs[typeName]; ok {
+				fields = append(fields, r.fields(embedded, prefix+tag["embeddedprefix"], depth+1)...)
+			}
+			continue
+		}
+
+		if _, ok := tag["embedded"]; ok {
+			if embedded, ok := r.structs[typeName]; ok {
+				fields = append(fields, r.fields(embedded, prefix+tag["embeddedprefix"], depth+1)...)
+			}
+			continue
+		}
+
+		if typeName == "gorm.DeletedAt" || (s.GormName != "" && typeName == s.GormName+".DeletedAt") {
+			typeName = "gorm.DeletedAt"
+		}
+		for _, name := range field.Names {
+			if !ast.IsExported(name.Name) {
+				continue
+			}
+			column := tag["column"]
+			if column == "" {
+				column = prefix + snakeCase(name.Name)
+			}
+			fields = append(fields, gormField{
+				GoName:   name.Name,
+				Column:   column,
+				TypeName: typeName,
+				Pointer:  pointer,
+				Slice:    slice,
+				Tag:      tag,
+			})
+		}
+	}
+	return fields
+}
+
+// column returns the column for a field, or false for fields that are not stored as a column
+// Pointers and sql.Null* types are nullable; other Go values cannot hold NULL and are shown as NOT NULL
+func (r *gormReader) column(field gormField, uniqueIndexes map[string]int) (*SchemaColumn, bool) {
+	column := &SchemaColumn{Name: field.Column}
+	_, serialized := field.Tag["serializer"]
+
+	switch nullableType, isNullable := gormNullableTypes[field.TypeName]; {
+	case field.Tag["type"] != "":
+		column.Type = NormalizeType(field.Tag["type"])
+	case serialized:
+		column.Type = "jsonb"
+	case field.Slice:
+		return nil, false // has no column type without a type or serializer tag
+	case isNullable:
+		column.Type = nullableType
+		column.Nullable = true
+	case field.TypeName == "string" && field.Tag["size"] != "":
+		column.Type = "varchar"
+	case gormColumnTypes[field.TypeName] != "":
+		column.Type = gormColumnTypes[field.TypeName]
+	case r.structs[field.TypeName] != nil || strings.HasPrefix(field.TypeName, "map") || field.TypeName == "":
+		return nil, false // structs, maps and interfaces are associations or need a serializer
+	default:
+		column.Type = "text" // named string types, enums and types from other packages
+	}
+
+	if field.Pointer {
+		column.Nullable = true
+	}
+	if _, ok := field.Tag["not null"]; ok {
+		column.Nullable = false
+	}
+	if _, ok := field.Tag["primarykey"]; ok && field.Tag["primarykey"] != "false" {
+		column.PrimaryKey = true
+		column.Nullable = false
+	}
+	if _, ok := field.Tag["unique"]; ok && field.Tag["unique"] != "false" {
+		column.Unique = true
+	}
+	if name, ok := field.Tag["uniqueindex"]; ok && (name == "" || uniqueIndexes[name] == 1) {
+		column.Unique = true
+	}
+	return column, true
+}
+
+// field returns a model's field by Go name, or nil
+func (m *gormModel) field(goName string) *gormField {
+	for i := range m.Fields {
+		if m.Fields[i].GoName == goName {
+			return &m.Fields[i]
+		}
+	}
+	return nil
+}
+
+// primaryKey returns the model's first primary key column, or nil
+func (m *gormModel) primaryKey() *SchemaColumn {
+	if key := m.Table.primaryKey(); len(key) > 0 {
+		return key[0]
+	}
+	return nil
+}
+
+// primaryKeyField returns the Go name of the model's primary key field, or "ID"
+func (m *gormModel) primaryKeyField() string {
+	if key := m.primaryKey(); key != nil {
+		for _, field := range m.Fields {
+			if field.Column == key.Name {
+				return field.GoName
+			}
+		}
+	}
+	return "ID"
+}
+
+// referencedColumn returns the column a references:<Field> tag points at on model, or "" for its primary key
+func referencedColumn(model *gormModel, tag map[string]string) string {
+	if field := model.field(tag["references"]); field != nil {
+		return field.Column
+	}
+	return ""
+}
+
+// firstName returns the first of a comma-separated list of names in a tag
+func firstName(value string) string {
+	name, _, _ := strings.Cut(value, ",")
+	return strings.TrimSpace(name)
+}
+
+// association adds the foreign keys implied by an association field from owner to target
+func (r *gormReader) association(owner *gormModel, field gormField, target *gormModel) {
+	if _, ok := field.Tag["polymorphic"]; ok {
+		return
+	}
+
+	if joinTable, ok := field.Tag["many2many"]; ok && joinTable != "" {
+		r.joinTable(owner, field, target, joinTable)
+		return
+	}
+
+	foreignKey := firstName(field.Tag["foreignkey"])
+
+	if field.Slice {
+		// Has many: the target holds <Owner><PrimaryKey>
+		if foreignKey == "" {
+			foreignKey = owner.Name + owner.primaryKeyField()
+		}
+		if fk := target.field(foreignKey); fk != nil {
+			r.schema.ForeignKeys = append(r.schema.ForeignKeys, ForeignKey{
+				Table: target.Table.Name, Column: fk.Column, RefTable: owner.Table.Name, RefColumn: referencedColumn(owner, field.Tag),
+			})
+		}
+		return
+	}
+
+	// Belongs to: the owner holds <Field><PrimaryKey>
+	belongsTo := foreignKey
+	if belongsTo == "" {
+		belongsTo = field.GoName + target.primaryKeyField()
+	}
+	if fk := owner.field(belongsTo); fk != nil {
+		r.schema.ForeignKeys = append(r.schema.ForeignKeys, ForeignKey{
+			Table: owner.Table.Name, Column: fk.Column, RefTable: target.Table.Name, RefColumn: referencedColumn(target, field.Tag),
+		})
+		return
+	}
+
+	// Has one: the target holds <Owner><PrimaryKey>
+	hasOne := foreignKey
+	if hasOne == "" {
+		hasOne = owner.Name + owner.primaryKeyField()
+	}
+	if fk := target.field(hasOne); fk != nil {
+		r.schema.ForeignKeys = append(r.schema.ForeignKeys, ForeignKey{
+			Table: target.Table.Name, Column: fk.Column, RefTable: owner.Table.Name, RefColumn: referencedColumn(owner, field.Tag), Type: OneToOne,
+		})
+	}
+}
+
+// joinTable adds a many2many join table (unless a model already defines it) and its two foreign keys
+func (r *gormReader) joinTable(owner *gormModel, field gormField, target *gormModel, name string) {
+	ownerKey, targetKey := owner.primaryKey(), target.primaryKey()
+	if ownerKey == nil || targetKey == nil {
+		return
+	}
+
+	ownerColumn := field.Tag["joinforeignkey"]
+	if ownerColumn == "" {
+		ownerColumn = snakeCase(owner.Name) + "_" + ownerKey.Name
+	} else {
+		ownerColumn = snakeCase(ownerColumn)
+	}
+	targetColumn := field.Tag["joinreferences"]
+	if targetColumn == "" {
+		targetName := target.Name
+		if target == owner {
+			targetName = singularize(field.GoName) // self-referencing, e.g. Friends []User
+		}
+		targetColumn = snakeCase(targetName) + "_" + targetKey.Name
+	} else {
+		targetColumn = snakeCase(targetColumn)
+	}
+
+	if r.schema.Table(name) == nil {
+		r.schema.Tables = append(r.schema.Tables, &SchemaTable{Name: name, Columns: []*SchemaColumn{
+			{Name: ownerColumn, Type: ownerKey.Type, PrimaryKey: true},
+			{Name: targetColumn, Type: targetKey.Type, PrimaryKey: true},
+		}})
+	}
+	r.schema.ForeignKeys = append(r.schema.ForeignKeys,
+		ForeignKey{Table: name, Column: ownerColumn, RefTable: owner.Table.Name, RefColumn: ownerKey.Name},
+		ForeignKey{Table: name, Column: targetColumn, RefTable: target.Table.Name, RefColumn: targetKey.Name},
+	)
+}
+
+// readGormModels adds the GORM models declared in files to schema
+func readGormModels(schema *Schema, files []*ast.File) {
+	r := &gormReader{structs: map[string]*gormStruct{}, models: map[string]*gormModel{}, schema: schema}
+	tableNames := map[string]string{}
+	var names []string
+
+	for _, file := range files {
+		gormName := importName(file, "gorm.io/gorm", "github.com/jinzhu/gorm")
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					typeSpec, ok := spec.(*ast.TypeSpec)
+					if !ok {
+						continue
+					}
+					if structType, ok := typeSpec.Type.(*ast.StructType); ok {
+						r.structs[typeSpec.Name.Name] = &gormStruct{Name: typeSpec.Name.Name, Fields: structType.Fields.List, GormName: gormName}
+						names = append(names, typeSpec.Name.Name)
+					}
+				}
+			case *ast.FuncDecl:
+				// func (User) TableName() string { return "accounts" }
+				if decl.Name.Name == "TableName" && decl.Type.Params.NumFields() == 0 {
+					if name, ok := stringLiteral(returnedExpr(decl)); ok {
+						tableNames[receiverName(decl)] = name
+					}
+				}
+			}
+		}
+	}
+
+	for _, name := range names {
+		s := r.structs[name]
+		if !r.isModel(s) {
+			continue
+		}
+		model := &gormModel{Name: name, Fields: r.fields(s, "", 0)}
+		tableName := tableNames[name]
+		if tableName == "" {
+			tableName = pluralize(snakeCase(name))
+		}
+		model.Table = &SchemaTable{Name: tableName}
+		r.models[name] = model
+		r.order = append(r.order, model)
+	}
+
+	for _, model := range r.order {
+		uniqueIndexes := map[string]int{}
+		for _, field := range model.Fields {
+			if name := field.Tag["uniqueindex"]; name != "" {
+				uniqueIndexes[name]++
+			}
+		}
+		for _, field := range model.Fields {
+			if r.models[field.TypeName] != nil {
+				continue // association
+			}
+			if column, ok := r.column(field, uniqueIndexes); ok && model.Table.Column(column.Name) == nil {
+				model.Table.Columns = append(model.Table.Columns, column)
+			}
+		}
+		// GORM uses a field named ID as the primary key by default
+		if len(model.Table.primaryKey()) == 0 {
+			if id := model.Table.Column("id"); id != nil {
+				id.PrimaryKey = true
+				id.Nullable = false
+			}
+		}
+		schema.AddTable(model.Table)
+	}
+
+	for _, model := range r.order {
+		for _, field := range model.Fields {
+			if target := r.models[field.TypeName]; target != nil {
+				r.association(model, field, target)
+			}
+		}
+	}
+
+	// Fields such as AuthorID point at the Author model even without an association field
+	for _, model := range r.order {
+		for _, field := range model.Fields {
+			if len(field.GoName) <= 2 || !strings.HasSuffix(field.GoName, "ID") || model.Table.Column(field.Column) == nil {
+				continue
+			}
+			target := r.models[strings.TrimSuffix(field.GoName, "ID")]
+			if target == nil || target.primaryKey() == nil {
+				continue
+			}
+			covered := slices.ContainsFunc(schema.ForeignKeys, func(fk ForeignKey) bool {
+				return fk.Table == model.Table.Name && fk.Column == field.Column
+			})
+			if !covered {
+				schema.ForeignKeys = append(schema.ForeignKeys, ForeignKey{Table: model.Table.Name, Column: field.Column, RefTable: target.Table.Name})
+			}
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package erd
+
+import (
+	"math"
+	"sort"
+)
+
+// Approximate size of a table node on the canvas, used to space the layout grid
+const (
+	tableWidth   = 250
+	headerHeight = 48
+	columnHeight = 32
+	gap          = 80
+	maxCoord     = 1000 // validate-and-encode.js keeps positions within -1000..1000
+)
+
+// layoutOrder orders tables so that related tables are next to each other:
+// each connected group is walked breadth-first from its most connected table
+func layoutOrder(count int, links [][2]int) []int {
+	neighbors := make([][]int, count)
+	for _, link := range links {
+		if link[0] == link[1] {
+			continue
+		}
+		neighbors[link[0]] = append(neighbors[link[0]], link[1])
+		neighbors[link[1]] = append(neighbors[link[1]], link[0])
+	}
+
+	byDegree := make([]int, count)
+	for i := range byDegree {
+		byDegree[i] = i
+	}
+	sort.SliceStable(byDegree, func(i, j int) bool {
+		return len(neighbors[byDegree[i]]) > len(neighbors[byDegree[j]])
+	})
+
+	visited := make([]bool, count)
+	order := make([]int, 0, count)
+	for _, start := range byDegree {
+		if visited[start] {
+			continue
+		}
+		visited[start] = true
+		queue := []int{start}
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+			order = append(order, current)
+
+			next := neighbors[current]
+			sort.SliceStable(next, func(i, j int) bool { return len(neighbors[next[i]]) > len(neighbors[next[j]]) })
+			for _, n := range next {
+				if !visited[n] {
+					visited[n] = true
+					queue = append(queue, n)
+				}
+			}
+		}
+	}
+	return order
+}
+
+// layoutTables places tables on a grid centered on the origin, in an order that keeps related tables close
+// Large schemas are squeezed to stay within the canvas bounds the ERD skill validates
+func layoutTables(tables []Table, links [][2]int) {
+	if len(tables) == 0 {
+		return
+	}
+
+	order := layoutOrder(len(tables), links)
+	columns := int(math.Ceil(math.Sqrt(float64(len(tables)))))
+	rows := (len(tables) + columns - 1) / columns
+
+	// Each row is as tall as its tallest table
+	rowHeights := make([]float64, rows)
+	for i, t := range order {
+		height := float64(headerHeight + columnHeight*len(tables[t].Data.Columns))
+		rowHeights[i/columns] = math.Max(rowHeights[i/columns], height)
+	}
+	rowTops := make([]float64, rows)
+	totalHeight := 0.0
+	for row, height := range rowHeights {
+		rowTops[row] = totalHeight
+		totalHeight += height + gap
+	}
+	totalHeight -= gap + rowHeights[rows-1] // y of the last row's top edge
+	totalWidth := float64((columns - 1) * (tableWidth + gap))
+
+	scaleX, scaleY := 1.0, 1.0
+	if totalWidth > 2*maxCoord {
+		scaleX = 2 * maxCoord / totalWidth
+	}
+	if totalHeight > 2*maxCoord {
+		scaleY = 2 * maxCoord / totalHeight
+	}
+
+	for i, t := range order {
+		x := float64((i%columns)*(tableWidth+gap)) - totalWidth/2
+		y := rowTops[i/columns] - totalHeight/2
+		tables[t].Position = Position{X: math.Round(x * scaleX), Y: math.Round(y * scaleY)}
+	}
+}
@@ -0,0 +1,220 @@
+package erd
+
+import (
+	"slices"
+)
+
+// Schema is a database schema read from SQL or code, before it is laid out as a Diagram
+type Schema struct {
+	Tables      []*SchemaTable
+	ForeignKeys []ForeignKey
+}
+
+// SchemaTable is a table of a Schema
+type SchemaTable struct {
+	Name    string
+	Columns []*SchemaColumn
+}
+
+// SchemaColumn is a column of a SchemaTable; Type is already one of DataTypes
+type SchemaColumn struct {
+	Name       string
+	Type       string
+	Nullable   bool
+	PrimaryKey bool
+	Unique     bool
+}
+
+// ForeignKey is a reference from Table.Column to RefTable.RefColumn
+// An empty RefColumn refers to RefTable's primary key
+type ForeignKey struct {
+	Name      string // constraint name, if any
+	Table     string
+	Column    string
+	RefTable  string
+	RefColumn string
+	Type      string // relationship type; empty to infer one-to-one or many-to-one from the column's keys
+}
+
+// Table returns the table with the given name, or nil
+func (s *Schema) Table(name string) *SchemaTable {
+	for _, table := range s.Tables {
+		if table.Name == name {
+			return table
+		}
+	}
+	return nil
+}
+
+// AddTable adds a table, replacing any table with the same name
+func (s *Schema) AddTable(table *SchemaTable) {
+	s.DropTable(table.Name)
+	s.Tables = append(s.Tables, table)
+}
+
+// DropTable removes a table and every foreign key from or to it
+func (s *Schema) DropTable(name string) {
+	s.Tables = slices.DeleteFunc(s.Tables, func(table *SchemaTable) bool { return table.Name == name })
+	s.ForeignKeys = slices.DeleteFunc(s.ForeignKeys, func(fk ForeignKey) bool {
+		return fk.Table == name || fk.RefTable == name
+	})
+}
+
+// RenameTable renames a table and updates the foreign keys that use it
+func (s *Schema) RenameTable(oldName string, newName string) {
+	if table := s.Table(oldName); table != nil {
+		table.Name = newName
+	}
+	for i := range s.ForeignKeys {
+		if s.ForeignKeys[i].Table == oldName {
+			s.ForeignKeys[i].Table = newName
+		}
+		if s.ForeignKeys[i].RefTable == oldName {
+			s.ForeignKeys[i].RefTable = newName
+		}
+	}
+}
+
+// Column returns the column with the given name, or nil
+func (t *SchemaTable) Column(name string) *SchemaColumn {
+	for _, column := range t.Columns {
+		if column.Name == name {
+			return column
+		}
+	}
+	return nil
+}
+
+// primaryKey returns the table's primary key columns
+func (t *SchemaTable) primaryKey() []*SchemaColumn {
+	var columns []*SchemaColumn
+	for _, column := range t.Columns {
+		if column.PrimaryKey {
+			columns = append(columns, column)
+		}
+	}
+	return columns
+}
+
+// DropColumn removes a column and the foreign keys that use it
+func (s *Schema) DropColumn(tableName string, columnName string) {
+	if table := s.Table(tableName); table != nil {
+		table.Columns = slices.DeleteFunc(table.Columns, func(column *SchemaColumn) bool { return column.Name == columnName })
+	}
+	s.ForeignKeys = slices.DeleteFunc(s.ForeignKeys, func(fk ForeignKey) bool {
+		return (fk.Table == tableName && fk.Column == columnName) || (fk.RefTable == tableName && fk.RefColumn == columnName)
+	})
+}
+
+// RenameColumn renames a column and updates the foreign keys that use it
+func (s *Schema) RenameColumn(tableName string, oldName string, newName string) {
+	if table := s.Table(tableName); table != nil {
+		if column := table.Column(oldName); column != nil {
+			column.Name = newName
+		}
+	}
+	for i := range s.ForeignKeys {
+		fk := &s.ForeignKeys[i]
+		if fk.Table == tableName && fk.Column == oldName {
+			fk.Column = newName
+		}
+		if fk.RefTable == tableName && fk.RefColumn == oldName {
+			fk.RefColumn = newName
+		}
+	}
+}
+
+// DropConstraint removes the foreign keys created by a named constraint
+func (s *Schema) DropConstraint(tableName string, name string) {
+	s.ForeignKeys = slices.DeleteFunc(s.ForeignKeys, func(fk ForeignKey) bool {
+		return fk.Table == tableName && fk.Name == name
+	})
+}
+
+// Build lays out the schema's tables and connects them with its foreign keys
+// Foreign keys to tables or columns that are not in the schema are left out
+func Build(schema *Schema) *Diagram {
+	diagram := &Diagram{Tables: []Table{}, Relationships: []Relationship{}}
+
+	index := map[string]int{}
+	for _, schemaTable := range schema.Tables {
+		columns := make([]Column, len(schemaTable.Columns))
+		for i, column := range schemaTable.Columns {
+			columns[i] = Column{
+				Name:       column.Name,
+				Type:       column.Type,
+				Nullable:   column.Nullable,
+				PrimaryKey: column.PrimaryKey,
+				Unique:     column.Unique,
+			}
+		}
+		index[schemaTable.Name] = len(diagram.Tables)
+		diagram.Tables = append(diagram.Tables, NewTable(schemaTable.Name, columns, Position{}))
+	}
+
+	type edge struct {
+		source, target                   int
+		sourceColumn, targetColumn, kind string
+	}
+	var edges []edge
+	seen := map[edge]int{}
+	for _, fk := range schema.ForeignKeys {
+		source, okSource := index[fk.Table]
+		target, okTarget := index[fk.RefTable]
+		if !okSource || !okTarget {
+			continue
+		}
+		sourceTable := schema.Tables[source]
+		targetTable := schema.Tables[target]
+
+		sourceColumn := sourceTable.Column(fk.Column)
+		if sourceColumn == nil {
+			continue
+		}
+		refColumn := fk.RefColumn
+		if refColumn == "" {
+			key := targetTable.primaryKey()
+			if len(key) != 1 {
+				continue
+			}
+			refColumn = key[0].Name
+		}
+		if targetTable.Column(refColumn) == nil {
+			continue
+		}
+
+		kind := fk.Type
+		if kind == "" {
+			kind = ManyToOne
+			if sourceColumn.Unique || (sourceColumn.PrimaryKey && len(sourceTable.primaryKey()) == 1) {
+				kind = OneToOne
+			}
+		}
+
+		// Both sides of an association may declare the same key; an explicit type wins over an inferred one
+		key := edge{source: source, target: target, sourceColumn: sourceColumn.Name, targetColumn: refColumn}
+		if i, ok := seen[key]; ok {
+			if fk.Type != "" {
+				edges[i].kind = kind
+			}
+			continue
+		}
+		seen[key] = len(edges)
+		edges = append(edges, edge{source, target, sourceColumn.Name, refColumn, kind})
+	}
+
+	links := make([][2]int, len(edges))
+	for i, e := range edges {
+		links[i] = [2]int{e.source, e.target}
+	}
+	layoutTables(diagram.Tables, links)
+
+	for _, e := range edges {
+		source := &diagram.Tables[e.source]
+		target := &diagram.Tables[e.target]
+		diagram.Relationships = append(diagram.Relationships,
+			NewRelationship(source, source.Column(e.sourceColumn), target, target.Column(e.targetColumn), e.kind))
+	}
+
+	return diagram
+}
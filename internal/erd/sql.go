@@ -0,0 +1,695 @@
+package erd
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"doclific/internal/core"
+)
+
+// sqlTokenKind classifies a token of a SQL statement
+type sqlTokenKind int
+
+const (
+	sqlWord   sqlTokenKind = iota // keyword or unquoted identifier, lower-cased
+	sqlQuoted                     // "quoted identifier", case preserved
+	sqlString                     // string literal or dollar-quoted body
+	sqlSymbol                     // punctuation or operator character
+)
+
+// sqlToken is a token of a SQL statement
+type sqlToken struct {
+	Kind  sqlTokenKind
+	Value string
+}
+
+// is reports whether the token is the given keyword or symbol
+func (t sqlToken) is(value string) bool {
+	return (t.Kind == sqlWord || t.Kind == sqlSymbol) && t.Value == value
+}
+
+// dollarQuoteTag matches the opening tag of a dollar-quoted string such as $$ or $body$
+var dollarQuoteTag = regexp.MustCompile(`^\$([A-Za-z_][A-Za-z0-9_]*)?\$`)
+
+// isSQLWordChar reports whether c can be part of a keyword or unquoted identifier
+func isSQLWordChar(c byte) bool {
+	return c == '_' || c == '$' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+}
+
+// tokenizeSQL splits SQL into statements of tokens, dropping comments
+func tokenizeSQL(sql string) ([][]sqlToken, error) {
+	var statements [][]sqlToken
+	var current []sqlToken
+	line := 1
+
+	for i := 0; i < len(sql); {
+		c := sql[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r' || c == '\f':
+			i++
+		case c == '-' && i+1 < len(sql) && sql[i+1] == '-':
+			for i < len(sql) && sql[i] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(sql) && sql[i+1] == '*':
+			// Block comments nest in PostgreSQL
+			depth, start := 0, line
+			for i < len(sql) {
+				if strings.HasPrefix(sql[i:], "/*") {
+					depth++
+					i += 2
+				} else if strings.HasPrefix(sql[i:], "*/") {
+					depth--
+					i += 2
+					if depth == 0 {
+						break
+					}
+				} else {
+					if sql[i] == '\n' {
+						line++
+					}
+					i++
+				}
+			}
+			if depth != 0 {
+				return nil, fmt.Errorf("line %d: unterminated comment", start)
+			}
+		case c == '\'' || c == '"':
+			start := line
+			// E'...' strings also escape quotes with a backslash
+			escapes := c == '\'' && i > 0 && (sql[i-1] == 'e' || sql[i-1] == 'E') && (i < 2 || !isSQLWordChar(sql[i-2]))
+			var b strings.Builder
+			i++
+			for {
+				if i >= len(sql) {
+					if c == '"' {
+						return nil, fmt.Errorf("line %d: unterminated quoted identifier", start)
+					}
+					return nil, fmt.Errorf("line %d: unterminated string", start)
+				}
+				if escapes && sql[i] == '\\' && i+1 < len(sql) {
+					b.WriteByte(sql[i+1])
+					i += 2
+					continue
+				}
+				if sql[i] == c {
+					if i+1 < len(sql) && sql[i+1] == c {
+						b.WriteByte(c)
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				if sql[i] == '\n' {
+					line++
+				}
+				b.WriteByte(sql[i])
+				i++
+			}
+			kind := sqlString
+			if c == '"' {
+				kind = sqlQuoted
+			}
+			current = append(current, sqlToken{Kind: kind, Value: b.String()})
+		case c == '$' && dollarQuoteTag.MatchString(sql[i:]):
+			tag := dollarQuoteTag.FindString(sql[i:])
+			end := strings.Index(sql[i+len(tag):], tag)
+			if end == -1 {
+				return nil, fmt.Errorf("line %d: unterminated %s string", line, tag)
+			}
+			body := sql[i+len(tag) : i+len(tag)+end]
+			line += strings.Count(body, "\n")
+			current = append(current, sqlToken{Kind: sqlString, Value: body})
+			i += len(tag) + end + len(tag)
+		case isSQLWordChar(c) && c != '$':
+			start := i
+			for i < len(sql) && isSQLWordChar(sql[i]) {
+				i++
+			}
+			current = append(current, sqlToken{Kind: sqlWord, Value: strings.ToLower(sql[start:i])})
+		case c == ';':
+			if len(current) > 0 {
+				statements = append(statements, current)
+			}
+			current = nil
+			i++
+		default:
+			current = append(current, sqlToken{Kind: sqlSymbol, Value: string(c)})
+			i++
+		}
+	}
+	if len(current) > 0 {
+		statements = append(statements, current)
+	}
+	return statements, nil
+}
+
+// sqlParser walks the tokens of one statement or clause
+type sqlParser struct {
+	tokens []sqlToken
+	pos    int
+}
+
+// done reports whether every token has been consumed
+func (p *sqlParser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+// peek returns the next token without consuming it
+func (p *sqlParser) peek() sqlToken {
+	if p.done() {
+		return sqlToken{Kind: sqlSymbol}
+	}
+	return p.tokens[p.pos]
+}
+
+// accept consumes the given sequence of keywords or symbols if it comes next
+func (p *sqlParser) accept(values ...string) bool {
+	for i, value := range values {
+		if p.pos+i >= len(p.tokens) || !p.tokens[p.pos+i].is(value) {
+			return false
+		}
+	}
+	p.pos += len(values)
+	return true
+}
+
+// name consumes a possibly schema-qualified name, dropping the default "public" schema
+func (p *sqlParser) name() string {
+	var parts []string
+	for !p.done() {
+		token := p.peek()
+		if token.Kind != sqlWord && token.Kind != sqlQuoted {
+			break
+		}
+		parts = append(parts, token.Value)
+		p.pos++
+		if !p.accept(".") {
+			break
+		}
+	}
+	if len(parts) > 1 && parts[0] == "public" {
+		parts = parts[1:]
+	}
+	return strings.Join(parts, ".")
+}
+
+// group consumes a parenthesized group and returns its tokens split on top-level commas
+// Returns nil if the next token is not "("
+func (p *sqlParser) group() [][]sqlToken {
+	if !p.peek().is("(") {
+		return nil
+	}
+	p.pos++
+	items := [][]sqlToken{}
+	var item []sqlToken
+	depth := 0
+	for ; !p.done(); p.pos++ {
+		token := p.tokens[p.pos]
+		switch {
+		case token.is("("):
+			depth++
+		case token.is(")"):
+			if depth == 0 {
+				p.pos++
+				if len(item) > 0 {
+					items = append(items, item)
+				}
+				return items
+			}
+			depth--
+		case token.is(",") && depth == 0:
+			items = append(items, item)
+			item = nil
+			continue
+		}
+		item = append(item, token)
+	}
+	return append(items, item)
+}
+
+// names consumes a parenthesized list of column names
+func (p *sqlParser) names() []string {
+	var names []string
+	for _, item := range p.group() {
+		item := &sqlParser{tokens: item}
+		names = append(names, item.name())
+	}
+	return names
+}
+
+// skipGroup consumes a parenthesized group if one comes next
+func (p *sqlParser) skipGroup() {
+	p.group()
+}
+
+// columnConstraintKeywords start a column constraint and end a column's type or default expression
+var columnConstraintKeywords = map[string]bool{
+	"constraint": true, "not": true, "null": true, "primary": true, "unique": true, "references": true,
+	"default": true, "check": true, "collate": true, "generated": true,
+}
+
+// skipExpression consumes tokens up to the next column constraint keyword outside parentheses
+func (p *sqlParser) skipExpression() {
+	for !p.done() {
+		token := p.peek()
+		if token.Kind == sqlWord && columnConstraintKeywords[token.Value] {
+			return
+		}
+		if token.is("(") {
+			p.skipGroup()
+			continue
+		}
+		p.pos++
+	}
+}
+
+// columnType consumes a column type such as "character varying(255)" or "timestamp(3) with time zone"
+func (p *sqlParser) columnType() string {
+	var b strings.Builder
+	depth := 0
+	for !p.done() {
+		token := p.peek()
+		if depth == 0 && token.Kind == sqlWord && columnConstraintKeywords[token.Value] {
+			break
+		}
+		switch {
+		case token.is("(") || token.is("["):
+			depth++
+		case token.is(")") || token.is("]"):
+			depth--
+		case token.Kind != sqlSymbol && b.Len() > 0 && depth == 0:
+			b.WriteByte(' ')
+		}
+		if token.is(",") && depth > 0 {
+			b.WriteString(", ")
+		} else {
+			b.WriteString(token.Value)
+		}
+		p.pos++
+	}
+	return b.String()
+}
+
+// references consumes "REFERENCES table [(columns)] [actions...]" and returns the table and columns
+func (p *sqlParser) references() (string, []string) {
+	table := p.name()
+	columns := p.names()
+	// MATCH, ON DELETE/UPDATE and DEFERRABLE clauses do not affect the diagram
+	for !p.done() {
+		switch {
+		case p.accept("match"), p.accept("initially"):
+			p.pos++
+		case p.accept("on"):
+			p.pos++ // DELETE or UPDATE
+			if p.accept("set") {
+				p.pos++ // NULL or DEFAULT
+				p.skipGroup()
+			} else if !p.accept("no", "action") {
+				p.pos++
+			}
+		case p.accept("deferrable"), p.accept("not", "deferrable"):
+		default:
+			return table, columns
+		}
+	}
+	return table, columns
+}
+
+// sqlSchemaBuilder applies DDL statements to a Schema in order
+type sqlSchemaBuilder struct {
+	schema *Schema
+}
+
+// apply applies one statement, ignoring statements that do not change tables, columns or keys
+func (s *sqlSchemaBuilder) apply(tokens []sqlToken) {
+	p := &sqlParser{tokens: tokens}
+	switch {
+	case p.accept("create"):
+		p.accept("or", "replace")
+		for p.accept("global") || p.accept("local") || p.accept("temporary") || p.accept("temp") || p.accept("unlogged") {
+		}
+		if p.accept("table") {
+			s.createTable(p)
+		} else if p.accept("unique", "index") {
+			s.createUniqueIndex(p)
+		}
+	case p.accept("alter", "table"):
+		s.alterTable(p)
+	case p.accept("drop", "table"):
+		p.accept("if", "exists")
+		for !p.done() {
+			if name := p.name(); name != "" {
+				s.schema.DropTable(name)
+			}
+			if !p.accept(",") {
+				break
+			}
+		}
+	}
+}
+
+// createTable handles the rest of "CREATE TABLE [IF NOT EXISTS] name (...)"
+func (s *sqlSchemaBuilder) createTable(p *sqlParser) {
+	p.accept("if", "not", "exists")
+	table := &SchemaTable{Name: p.name()}
+	if table.Name == "" || p.peek().is("partition") || p.peek().is("as") || p.peek().is("of") {
+		return // partitions, typed tables and CREATE TABLE AS have no column list to read
+	}
+	s.schema.AddTable(table)
+
+	for _, element := range p.group() {
+		s.tableElement(table, &sqlParser{tokens: element})
+	}
+}
+
+// tableElement handles one column definition or table constraint of a CREATE TABLE or ALTER TABLE ADD
+func (s *sqlSchemaBuilder) tableElement(table *SchemaTable, p *sqlParser) {
+	constraintName := ""
+	if p.accept("constraint") {
+		constraintName = p.name()
+	}
+
+	switch {
+	case p.accept("primary", "key"):
+		columns := p.names()
+		for _, name := range columns {
+			if column := table.Column(name); column != nil {
+				column.PrimaryKey = true
+				column.Nullable = false
+			}
+		}
+	case p.accept("unique"):
+		p.accept("nulls", "not", "distinct")
+		p.accept("nulls", "distinct")
+		// Only a single-column unique constraint makes that column unique on its own
+		if columns := p.names(); len(columns) == 1 {
+			if column := table.Column(columns[0]); column != nil {
+				column.Unique = true
+			}
+		}
+	case p.accept("foreign", "key"):
+		columns := p.names()
+		if !p.accept("references") {
+			return
+		}
+		refTable, refColumns := p.references()
+		for i, name := range columns {
+			fk := ForeignKey{Name: constraintName, Table: table.Name, Column: name, RefTable: refTable}
+			if i < len(refColumns) {
+				fk.RefColumn = refColumns[i]
+			}
+			s.schema.ForeignKeys = append(s.schema.ForeignKeys, fk)
+		}
+	case p.peek().is("check") || p.peek().is("exclude") || p.peek().is("like"):
+		// Checks, exclusion constraints and LIKE copies do not add columns or keys we can show
+	default:
+		if constraintName == "" {
+			s.columnDefinition(table, p)
+		}
+	}
+}
+
+// columnDefinition handles "name type [constraints...]"
+func (s *sqlSchemaBuilder) columnDefinition(table *SchemaTable, p *sqlParser) {
+	name := p.name()
+	if name == "" {
+		return
+	}
+	column := &SchemaColumn{Name: name, Nullable: true}
+	sqlType := p.columnType()
+	column.Type = NormalizeType(sqlType)
+
+	constraintName := ""
+	for !p.done() {
+		switch {
+		case p.accept("constraint"):
+			constraintName = p.name()
+			continue
+		case p.accept("not", "null"):
+			column.Nullable = false
+		case p.accept("null"):
+			column.Nullable = true
+		case p.accept("primary", "key"):
+			column.PrimaryKey = true
+			column.Nullable = false
+		case p.accept("unique"):
+			column.Unique = true
+			p.accept("nulls", "not", "distinct")
+			p.accept("nulls", "distinct")
+		case p.accept("references"):
+			refTable, refColumns := p.references()
+			fk := ForeignKey{Name: constraintName, Table: table.Name, Column: name, RefTable: refTable}
+			if len(refColumns) > 0 {
+				fk.RefColumn = refColumns[0]
+			}
+			s.schema.ForeignKeys = append(s.schema.ForeignKeys, fk)
+		case p.accept("generated"):
+			// Identity columns are implicitly NOT NULL; generated columns keep their nullability
+			if p.accept("always", "as", "identity") || p.accept("by", "default", "as", "identity") {
+				column.Nullable = false
+			}
+			p.skipExpression()
+		case p.accept("default") || p.accept("check") || p.accept("collate"):
+			if !p.done() {
+				if p.peek().is("(") {
+					p.skipGroup()
+				} else {
+					p.pos++
+				}
+			}
+			p.skipExpression()
+		default:
+			p.pos++
+		}
+		constraintName = ""
+	}
+
+	if existing := table.Column(name); existing != nil {
+		*existing = *column
+	} else {
+		table.Columns = append(table.Columns, column)
+	}
+}
+
+// createUniqueIndex handles the rest of "CREATE UNIQUE INDEX ... ON table (column)"
+// A unique index on a single plain column without a WHERE clause makes the column unique
+func (s *sqlSchemaBuilder) createUniqueIndex(p *sqlParser) {
+	for !p.done() && !p.peek().is("on") {
+		p.pos++
+	}
+	if !p.accept("on") {
+		return
+	}
+	p.accept("only")
+	table := s.schema.Table(p.name())
+	if p.accept("using") {
+		p.pos++
+	}
+	items := p.group()
+	if table == nil || len(items) != 1 || !p.done() {
+		return
+	}
+	item := &sqlParser{tokens: items[0]}
+	name := item.name()
+	if !item.done() {
+		return // an expression or a column with an operator class or sort order
+	}
+	if column := table.Column(name); column != nil {
+		column.Unique = true
+	}
+}
+
+// alterTable handles the rest of "ALTER TABLE [IF EXISTS] [ONLY] name action, ..."
+func (s *sqlSchemaBuilder) alterTable(p *sqlParser) {
+	p.accept("if", "exists")
+	p.accept("only")
+	tableName := p.name()
+	p.accept("*")
+
+	// RENAME forms take the rest of the statement
+	if p.accept("rename", "to") {
+		s.schema.RenameTable(tableName, p.name())
+		return
+	}
+	if p.accept("rename") {
+		if p.accept("constraint") {
+			return
+		}
+		p.accept("column")
+		oldName := p.name()
+		if p.accept("to") {
+			s.schema.RenameColumn(tableName, oldName, p.name())
+		}
+		return
+	}
+
+	table := s.schema.Table(tableName)
+	if table == nil {
+		return
+	}
+
+	// Split the remaining actions on top-level commas
+	var actions [][]sqlToken
+	var action []sqlToken
+	depth := 0
+	for _, token := range p.tokens[p.pos:] {
+		switch {
+		case token.is("("):
+			depth++
+		case token.is(")"):
+			depth--
+		case token.is(",") && depth == 0:
+			actions = append(actions, action)
+			action = nil
+			continue
+		}
+		action = append(action, token)
+	}
+	actions = append(actions, action)
+
+	for _, tokens := range actions {
+		a := &sqlParser{tokens: tokens}
+		switch {
+		case a.accept("add"):
+			a.accept("column")
+			a.accept("if", "not", "exists")
+			s.tableElement(table, a)
+		case a.accept("drop", "constraint"):
+			a.accept("if", "exists")
+			s.schema.DropConstraint(tableName, a.name())
+		case a.accept("drop"):
+			a.accept("column")
+			a.accept("if", "exists")
+			s.schema.DropColumn(tableName, a.name())
+		case a.accept("alter"):
+			a.accept("column")
+			column := table.Column(a.name())
+			if column == nil {
+				continue
+			}
+			switch {
+			case a.accept("set", "not", "null"):
+				column.Nullable = false
+			case a.accept("drop", "not", "null"):
+				column.Nullable = true
+			case a.accept("set", "data", "type") || a.accept("type"):
+				column.Type = NormalizeType(a.columnType())
+			}
+		}
+	}
+}
+
+// ParseSQL reads the tables and foreign keys defined by PostgreSQL DDL
+// Statements are applied in order, so later ALTER, RENAME and DROP statements update earlier tables
+func ParseSQL(sql string) (*Schema, error) {
+	schema := &Schema{}
+	if err := schema.ApplySQL(sql); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// ApplySQL applies the statements of sql on top of the schema
+func (s *Schema) ApplySQL(sql string) error {
+	statements, err := tokenizeSQL(sql)
+	if err != nil {
+		return err
+	}
+	builder := &sqlSchemaBuilder{schema: s}
+	for _, statement := range statements {
+		builder.apply(statement)
+	}
+	return nil
+}
+
+// downMigrationMarkers start the rollback half of a migration file in goose and dbmate
+var downMigrationMarkers = regexp.MustCompile(`(?im)^\s*--\s*(\+goose\s+down|migrate:down)\b`)
+
+// upMigration returns the part of a migration file that applies the migration
+func upMigration(sql string) string {
+	if loc := downMigrationMarkers.FindStringIndex(sql); loc != nil {
+		return sql[:loc[0]]
+	}
+	return sql
+}
+
+// isDownMigration reports whether a file only rolls a migration back, as in golang-migrate's *.down.sql
+func isDownMigration(fileName string) bool {
+	name := strings.ToLower(fileName)
+	return strings.HasSuffix(name, ".down.sql") || strings.HasSuffix(name, "_down.sql") || name == "down.sql"
+}
+
+// SQLFiles expands paths into the SQL files to read, in order
+// Directories are searched recursively for *.sql files, sorted so numbered migrations run in sequence,
+// and down migrations are skipped
+func SQLFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", p, err)
+		}
+		if !info.IsDir() {
+			files = append(files, p)
+			continue
+		}
+
+		var found []string
+		err = filepath.WalkDir(p, func(filePath string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				if filePath != p && strings.HasPrefix(d.Name(), ".") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if strings.EqualFold(filepath.Ext(d.Name()), ".sql") && !isDownMigration(d.Name()) {
+				found = append(found, filePath)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read directory %s: %w", p, err)
+		}
+		sort.SliceStable(found, func(i, j int) bool {
+			return core.NaturalLess(filepath.ToSlash(found[i]), filepath.ToSlash(found[j]))
+		})
+		files = append(files, found...)
+	}
+	return files, nil
+}
+
+// ParseSQLFiles reads the schema defined by SQL files and directories of migrations, applied in order
+func ParseSQLFiles(paths []string) (*Schema, error) {
+	files, err := SQLFiles(paths)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no .sql files found")
+	}
+
+	schema := &Schema{}
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		if err := schema.ApplySQL(upMigration(string(content))); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+	}
+	return schema, nil
+}
@@ -0,0 +1,179 @@
+package erd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseSQL(t *testing.T) {
+	sql := `
+-- users and their posts
+CREATE TABLE IF NOT EXISTS public.users (
+    id bigserial PRIMARY KEY,
+    email character varying(255) NOT NULL UNIQUE,
+    "displayName" text,
+    settings jsonb DEFAULT '{}'::jsonb NOT NULL,
+    created_at timestamp(3) with time zone DEFAULT now() NOT NULL
+);
+
+CREATE TABLE posts (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    author_id bigint NOT NULL REFERENCES users ON DELETE SET NULL,
+    title varchar(200) NOT NULL,
+    body text,
+    score numeric(10, 2),
+    tags text[],
+    CONSTRAINT posts_pkey PRIMARY KEY (id)
+);
+
+CREATE TABLE profiles (
+    user_id bigint PRIMARY KEY REFERENCES users (id),
+    bio text
+);
+
+CREATE FUNCTION touch() RETURNS trigger AS $$
+BEGIN
+    NEW.updated_at = now(); -- not a statement boundary
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TABLE post_tags (
+    post_id uuid NOT NULL,
+    tag citext NOT NULL,
+    PRIMARY KEY (post_id, tag)
+);
+
+ALTER TABLE ONLY post_tags
+    ADD CONSTRAINT post_tags_post_fk FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE;
+`
+	schema, err := ParseSQL(sql)
+	if err != nil {
+		t.Fatalf("ParseSQL() error = %v", err)
+	}
+
+	if len(schema.Tables) != 4 {
+		t.Fatalf("ParseSQL() tables = %d, want 4", len(schema.Tables))
+	}
+
+	users := schema.Table("users")
+	if users == nil {
+		t.Fatal("users table not found")
+	}
+	tests := []struct {
+		table, column, typ           string
+		nullable, primaryKey, unique bool
+	}{
+		{"users", "id", "bigserial", false, true, false},
+		{"users", "email", "varchar", false, false, true},
+		{"users", "displayName", "text", true, false, false},
+		{"users", "settings", "jsonb", false, false, false},
+		{"users", "created_at", "timestamptz", false, false, false},
+		{"posts", "id", "uuid", false, true, false},
+		{"posts", "score", "numeric", true, false, false},
+		{"posts", "tags", "text", true, false, false},
+		{"post_tags", "post_id", "uuid", false, true, false},
+		{"post_tags", "tag", "text", false, true, false},
+	}
+	for _, tt := range tests {
+		table := schema.Table(tt.table)
+		if table == nil {
+			t.Fatalf("%s table not found", tt.table)
+		}
+		column := table.Column(tt.column)
+		if column == nil {
+			t.Fatalf("%s.%s not found", tt.table, tt.column)
+		}
+		if column.Type != tt.typ || column.Nullable != tt.nullable || column.PrimaryKey != tt.primaryKey || column.Unique != tt.unique {
+			t.Errorf("%s.%s = %+v, want type %s nullable %v pk %v unique %v",
+				tt.table, tt.column, *column, tt.typ, tt.nullable, tt.primaryKey, tt.unique)
+		}
+	}
+
+	if len(schema.ForeignKeys) != 3 {
+		t.Fatalf("ParseSQL() foreign keys = %+v, want 3", schema.ForeignKeys)
+	}
+	last := schema.ForeignKeys[2]
+	if last.Name != "post_tags_post_fk" || last.Table != "post_tags" || last.Column != "post_id" || last.RefTable != "posts" || last.RefColumn != "id" {
+		t.Errorf("ALTER TABLE foreign key = %+v", last)
+	}
+}
+
+func TestParseSQLAlterations(t *testing.T) {
+	sql := `
+CREATE TABLE accounts (id serial PRIMARY KEY, name text);
+CREATE TABLE members (id serial PRIMARY KEY, account_id integer, legacy text);
+ALTER TABLE members ADD CONSTRAINT members_account_fk FOREIGN KEY (account_id) REFERENCES accounts (id);
+ALTER TABLE members ALTER COLUMN account_id SET NOT NULL, DROP COLUMN legacy, ADD COLUMN email varchar(100);
+CREATE UNIQUE INDEX members_email_idx ON members USING btree (email);
+ALTER TABLE accounts RENAME TO organizations;
+ALTER TABLE members RENAME COLUMN account_id TO organization_id;
+CREATE TABLE scratch (id int);
+DROP TABLE IF EXISTS scratch;
+`
+	schema, err := ParseSQL(sql)
+	if err != nil {
+		t.Fatalf("ParseSQL() error = %v", err)
+	}
+
+	if schema.Table("scratch") != nil || schema.Table("accounts") != nil || schema.Table("organizations") == nil {
+		t.Fatalf("ParseSQL() tables not renamed or dropped: %+v", schema.Tables)
+	}
+
+	members := schema.Table("members")
+	if members.Column("legacy") != nil {
+		t.Error("dropped column legacy is still present")
+	}
+	if column := members.Column("organization_id"); column == nil || column.Nullable {
+		t.Errorf("organization_id = %+v, want a renamed NOT NULL column", column)
+	}
+	if column := members.Column("email"); column == nil || !column.Unique || column.Type != "varchar" {
+		t.Errorf("email = %+v, want a unique varchar", column)
+	}
+
+	if len(schema.ForeignKeys) != 1 || schema.ForeignKeys[0].Column != "organization_id" || schema.ForeignKeys[0].RefTable != "organizations" {
+		t.Errorf("ParseSQL() foreign keys = %+v", schema.ForeignKeys)
+	}
+
+	if _, err := ParseSQL("CREATE TABLE x (name text DEFAULT 'oops);"); err == nil {
+		t.Error("ParseSQL() with an unterminated string should fail")
+	}
+}
+
+func TestParseSQLFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"2_posts.up.sql":   "CREATE TABLE posts (id serial PRIMARY KEY, user_id int REFERENCES users);",
+		"2_posts.down.sql": "DROP TABLE posts;",
+		"10_rename.sql":    "-- +goose Up\nALTER TABLE posts RENAME TO articles;\n-- +goose Down\nALTER TABLE articles RENAME TO posts;",
+		"1_users.up.sql":   "CREATE TABLE users (id serial PRIMARY KEY);",
+		"README.md":        "not SQL",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	found, err := SQLFiles([]string{dir})
+	if err != nil {
+		t.Fatalf("SQLFiles() error = %v", err)
+	}
+	var names []string
+	for _, file := range found {
+		names = append(names, filepath.Base(file))
+	}
+	if got := strings.Join(names, ","); got != "1_users.up.sql,2_posts.up.sql,10_rename.sql" {
+		t.Errorf("SQLFiles() = %s", got)
+	}
+
+	schema, err := ParseSQLFiles([]string{dir})
+	if err != nil {
+		t.Fatalf("ParseSQLFiles() error = %v", err)
+	}
+	if schema.Table("articles") == nil || schema.Table("posts") != nil {
+		t.Errorf("ParseSQLFiles() tables = %+v, want users and articles", schema.Tables)
+	}
+}
@@ -0,0 +1,402 @@
+package erd
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"doclific/internal/core"
+)
+
+// Change kinds reported by Merge
+const (
+	TableAdded          = "table-added"
+	TableRemoved        = "table-removed"
+	ColumnAdded         = "column-added"
+	ColumnRemoved       = "column-removed"
+	ColumnChanged       = "column-changed"
+	RelationshipAdded   = "relationship-added"
+	RelationshipRemoved = "relationship-removed"
+	RelationshipChanged = "relationship-changed"
+)
+
+// Change is a difference between an <ERD> block and the diagram generated from its source
+// Relationship changes are reported on their source table and column
+type Change struct {
+	Kind   string `json:"kind"`
+	Table  string `json:"table"`
+	Column string `json:"column,omitempty"`
+	Detail string `json:"detail,omitempty"` // e.g. "(text → varchar)" or "→ users.id (many-to-one)"
+}
+
+// String describes the change as a diff line such as "+ column users.email (text)"
+func (c Change) String() string {
+	subject, action, _ := strings.Cut(c.Kind, "-")
+	symbol := map[string]string{"added": "+", "removed": "-", "changed": "~"}[action]
+
+	name := c.Table
+	if c.Column != "" {
+		name += "." + c.Column
+	}
+	line := fmt.Sprintf("%s %s %s", symbol, subject, name)
+	if c.Detail != "" {
+		line += " " + c.Detail
+	}
+	return line
+}
+
+// ParseSource reads the schema an <ERD> block's source attribute points at: comma-separated paths
+// relative to the repository root, read as SQL migrations when every path is a .sql file or a
+// directory without Go files, and as GORM models or ent schemas otherwise
+func ParseSource(source string) (*Schema, error) {
+	var paths []string
+	for _, p := range strings.Split(source, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		if !filepath.IsLocal(strings.TrimSuffix(filepath.ToSlash(p), "/...")) {
+			return nil, fmt.Errorf("source %s is outside the repository", p)
+		}
+		paths = append(paths, p)
+	}
+	if len(paths) == 0 {
+		return nil, errors.New("source is empty")
+	}
+
+	if isSQLSource(paths) {
+		return ParseSQLFiles(paths)
+	}
+	return ParseGoModels(paths)
+}
+
+// isSQLSource reports whether every path is a .sql file or a directory of migrations
+func isSQLSource(paths []string) bool {
+	for _, p := range paths {
+		if strings.HasSuffix(filepath.ToSlash(p), "/...") || strings.HasSuffix(p, ".go") {
+			return false
+		}
+		if strings.EqualFold(filepath.Ext(p), ".sql") {
+			continue
+		}
+		entries, err := os.ReadDir(p)
+		if err != nil {
+			return false
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".go") {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Merge updates an existing diagram to match one generated from its source
+// Tables and columns are matched by name and keep their IDs, so existing tables stay where they were
+// placed by hand; new tables are laid out below them. Relationships are matched by their columns in
+// either direction, and those no longer in the source are removed
+func Merge(existing *Diagram, generated *Diagram) (*Diagram, []Change) {
+	merged := &Diagram{Tables: []Table{}, Relationships: []Relationship{}, Source: existing.Source}
+	changes := []Change{}
+
+	for _, table := range existing.Tables {
+		fresh := generated.Table(table.Data.Name)
+		if fresh == nil {
+			changes = append(changes, Change{Kind: TableRemoved, Table: table.Data.Name})
+			continue
+		}
+		merged.Tables = append(merged.Tables, mergeTable(table, fresh, &changes))
+	}
+
+	var added []Table
+	for _, table := range generated.Tables {
+		if existing.Table(table.Data.Name) == nil {
+			changes = append(changes, Change{Kind: TableAdded, Table: table.Data.Name})
+			added = append(added, table)
+		}
+	}
+	placeBelow(merged.Tables, added)
+	merged.Tables = append(merged.Tables, added...)
+
+	wanted := map[endpoints]string{}
+	var order []endpoints
+	for _, relationship := range generated.Relationships {
+		if key, ok := relationshipEndpoints(generated, relationship); ok {
+			wanted[key] = relationship.Data.Type
+			order = append(order, key)
+		}
+	}
+
+	matched := map[endpoints]bool{}
+	for _, relationship := range existing.Relationships {
+		key, ok := relationshipEndpoints(existing, relationship)
+		if !ok {
+			continue // Dangling edges are not drawn by the editor, so drop them quietly
+		}
+
+		kind, found := wanted[key]
+		generatedKey := key
+		if !found {
+			generatedKey = key.reverse()
+			kind, found = wanted[generatedKey]
+			kind = reverseType(kind)
+		}
+		if !found || matched[generatedKey] {
+			changes = append(changes, Change{Kind: RelationshipRemoved, Table: key.source, Column: key.sourceColumn,
+				Detail: fmt.Sprintf("→ %s.%s (%s)", key.target, key.targetColumn, relationship.Data.Type)})
+			continue
+		}
+		matched[generatedKey] = true
+
+		if relationship.Data.Type != kind {
+			changes = append(changes, Change{Kind: RelationshipChanged, Table: key.source, Column: key.sourceColumn,
+				Detail: fmt.Sprintf("→ %s.%s (%s → %s)", key.target, key.targetColumn, relationship.Data.Type, kind)})
+			id := relationship.ID
+			relationship = key.connect(merged, kind)
+			relationship.ID = id
+		}
+		merged.Relationships = append(merged.Relationships, relationship)
+	}
+
+	for _, key := range order {
+		if matched[key] {
+			continue
+		}
+		matched[key] = true
+		changes = append(changes, Change{Kind: RelationshipAdded, Table: key.source, Column: key.sourceColumn,
+			Detail: fmt.Sprintf("→ %s.%s (%s)", key.target, key.targetColumn, wanted[key])})
+		merged.Relationships = append(merged.Relationships, key.connect(merged, wanted[key]))
+	}
+
+	return merged, changes
+}
+
+// mergeTable gives an existing table the generated table's columns, keeping the IDs of columns that
+// are still there so relationship handles keep pointing at them
+func mergeTable(table Table, fresh *Table, changes *[]Change) Table {
+	name := table.Data.Name
+	columns := make([]Column, 0, len(fresh.Data.Columns))
+	for _, column := range fresh.Data.Columns {
+		old := table.Column(column.Name)
+		if old == nil {
+			*changes = append(*changes, Change{Kind: ColumnAdded, Table: name, Column: column.Name, Detail: "(" + column.Type + ")"})
+			columns = append(columns, column)
+			continue
+		}
+
+		var diffs []string
+		if old.Type != column.Type {
+			diffs = append(diffs, old.Type+" → "+column.Type)
+		}
+		if old.Nullable != column.Nullable {
+			diffs = append(diffs, flag(column.Nullable, "nullable", "not null"))
+		}
+		if old.PrimaryKey != column.PrimaryKey {
+			diffs = append(diffs, flag(column.PrimaryKey, "primary key", "not primary key"))
+		}
+		if old.Unique != column.Unique {
+			diffs = append(diffs, flag(column.Unique, "unique", "not unique"))
+		}
+		if len(diffs) > 0 {
+			*changes = append(*changes, Change{Kind: ColumnChanged, Table: name, Column: column.Name, Detail: "(" + strings.Join(diffs, ", ") + ")"})
+		}
+
+		column.ID = old.ID
+		columns = append(columns, column)
+	}
+	for _, column := range table.Data.Columns {
+		if fresh.Column(column.Name) == nil {
+			*changes = append(*changes, Change{Kind: ColumnRemoved, Table: name, Column: column.Name})
+		}
+	}
+
+	table.Data.Columns = columns
+	return table
+}
+
+// flag returns on or off depending on value
+func flag(value bool, on string, off string) string {
+	if value {
+		return on
+	}
+	return off
+}
+
+// placeBelow moves newly added tables, keeping their generated layout, under the existing ones
+func placeBelow(existing []Table, added []Table) {
+	if len(existing) == 0 || len(added) == 0 {
+		return
+	}
+
+	left, bottom := math.Inf(1), math.Inf(-1)
+	for _, table := range existing {
+		left = math.Min(left, table.Position.X)
+		bottom = math.Max(bottom, table.Position.Y+float64(headerHeight+columnHeight*len(table.Data.Columns)))
+	}
+	addedLeft, addedTop := math.Inf(1), math.Inf(1)
+	for _, table := range added {
+		addedLeft = math.Min(addedLeft, table.Position.X)
+		addedTop = math.Min(addedTop, table.Position.Y)
+	}
+
+	for i := range added {
+		added[i].Position.X += left - addedLeft
+		added[i].Position.Y += bottom + gap - addedTop
+	}
+}
+
+// endpoints identifies a relationship by the names of the columns it connects
+type endpoints struct {
+	source, sourceColumn, target, targetColumn string
+}
+
+// reverse returns the same connection seen from the other end
+func (e endpoints) reverse() endpoints {
+	return endpoints{e.target, e.targetColumn, e.source, e.sourceColumn}
+}
+
+// connect creates a relationship between the endpoints' columns in a diagram
+func (e endpoints) connect(d *Diagram, relationshipType string) Relationship {
+	source := d.Table(e.source)
+	target := d.Table(e.target)
+	return NewRelationship(source, source.Column(e.sourceColumn), target, target.Column(e.targetColumn), relationshipType)
+}
+
+// relationshipEndpoints resolves a relationship's table and column names, reporting false if either end is missing
+func relationshipEndpoints(d *Diagram, relationship Relationship) (endpoints, bool) {
+	source := d.TableByID(relationship.Source)
+	target := d.TableByID(relationship.Target)
+	if source == nil || target == nil {
+		return endpoints{}, false
+	}
+	sourceColumn := source.ColumnByHandle(relationship.SourceHandle)
+	targetColumn := target.ColumnByHandle(relationship.TargetHandle)
+	if sourceColumn == nil || targetColumn == nil {
+		return endpoints{}, false
+	}
+	return endpoints{source.Data.Name, sourceColumn.Name, target.Data.Name, targetColumn.Name}, true
+}
+
+// reverseType returns a relationship type as seen from the other end
+func reverseType(relationshipType string) string {
+	switch relationshipType {
+	case OneToMany:
+		return ManyToOne
+	case ManyToOne:
+		return OneToMany
+	}
+	return relationshipType
+}
+
+// SourcedERD is an <ERD> block with a source attribute and how it differs from that source
+type SourcedERD struct {
+	FilePath string   `json:"filePath"`
+	Title    string   `json:"title"`
+	Line     int      `json:"line"` // line of the block in content.mdx
+	Source   string   `json:"source"`
+	Changes  []Change `json:"changes"`
+	Error    string   `json:"error,omitempty"` // set when the block or its source could not be read
+	Applied  bool     `json:"applied,omitempty"`
+}
+
+// SyncDocs regenerates every <ERD> block that has a source attribute and reports how each differs
+// With apply, out-of-date blocks are rewritten in place; a non-empty filePath limits the check to one doc
+func SyncDocs(filePath string, apply bool) ([]SourcedERD, error) {
+	docs, err := core.GetDocs()
+	if err != nil {
+		return nil, err
+	}
+
+	// Several blocks often share a migration directory, so each source is only read once
+	type sourceResult struct {
+		schema *Schema
+		err    error
+	}
+	cache := map[string]sourceResult{}
+
+	results := []SourcedERD{}
+	found := filePath == ""
+	err = core.WalkDocs(docs, func(docPath string, doc core.FolderStructure) error {
+		if filePath != "" && docPath != filePath {
+			return nil
+		}
+		found = true
+
+		content, err := core.GetDoc(docPath)
+		if err != nil {
+			return err
+		}
+
+		type replacement struct {
+			start, end int
+			mdx        string
+		}
+		var replacements []replacement
+		first := len(results)
+
+		for _, component := range core.ParseMDXComponents(content, core.ComponentERD) {
+			source := component.Attributes["source"]
+			if source == "" {
+				continue
+			}
+			result := SourcedERD{FilePath: docPath, Title: doc.Title, Line: component.Line, Source: source, Changes: []Change{}}
+
+			cached, ok := cache[source]
+			if !ok {
+				schema, err := ParseSource(source)
+				if err == nil && len(schema.Tables) == 0 {
+					err = errors.New("no tables found in source")
+				}
+				cached = sourceResult{schema, err}
+				cache[source] = cached
+			}
+			existing, err := ParseComponent(component)
+			if cached.err != nil {
+				err = cached.err
+			}
+			if err != nil {
+				result.Error = err.Error()
+				results = append(results, result)
+				continue
+			}
+
+			merged, changes := Merge(existing, Build(cached.schema))
+			result.Changes = changes
+			if apply && len(changes) > 0 {
+				mdx, err := merged.MDX()
+				if err != nil {
+					return err
+				}
+				replacements = append(replacements, replacement{component.Start, component.End, mdx})
+			}
+			results = append(results, result)
+		}
+
+		if len(replacements) == 0 {
+			return nil
+		}
+		for i := len(replacements) - 1; i >= 0; i-- {
+			r := replacements[i]
+			content = content[:r.start] + r.mdx + content[r.end:]
+		}
+		if err := core.UpdateDoc(docPath, content); err != nil {
+			return fmt.Errorf("failed to update %s: %w", docPath, err)
+		}
+		for i := first; i < len(results); i++ {
+			results[i].Applied = results[i].Error == "" && len(results[i].Changes) > 0
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("doc %s not found", filePath)
+	}
+
+	return results, nil
+}
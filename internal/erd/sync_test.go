@@ -0,0 +1,136 @@
+package erd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	before, err := ParseSQL(`
+CREATE TABLE users (id bigint PRIMARY KEY, email text, nickname text);
+CREATE TABLE posts (id bigint PRIMARY KEY, user_id bigint NOT NULL REFERENCES users (id));
+CREATE TABLE tags (id bigint PRIMARY KEY);
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	existing := Build(before)
+	existing.Table("users").Position = Position{X: -400, Y: 120}
+	existing.Table("posts").Position = Position{X: 300, Y: 500}
+	usersID := existing.Table("users").ID
+	emailID := existing.Table("users").Column("email").ID
+	relationshipID := existing.Relationships[0].ID
+
+	after, err := ParseSQL(`
+CREATE TABLE users (id bigint PRIMARY KEY, email varchar NOT NULL UNIQUE, created_at timestamptz);
+CREATE TABLE posts (id bigint PRIMARY KEY, user_id bigint NOT NULL REFERENCES users (id));
+CREATE TABLE comments (id bigint PRIMARY KEY, post_id bigint REFERENCES posts (id));
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	merged, changes := Merge(existing, Build(after))
+
+	var got []string
+	for _, change := range changes {
+		got = append(got, change.String())
+	}
+	want := []string{
+		"~ column users.email (text → varchar, not null, unique)",
+		"+ column users.created_at (timestamptz)",
+		"- column users.nickname",
+		"- table tags",
+		"+ table comments",
+		"+ relationship comments.post_id → posts.id (many-to-one)",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Merge() changes =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	users := merged.Table("users")
+	if users.ID != usersID || users.Position != (Position{X: -400, Y: 120}) {
+		t.Errorf("users = %s at %+v, want the existing ID and position", users.ID, users.Position)
+	}
+	if users.Column("email").ID != emailID {
+		t.Error("users.email lost its column ID")
+	}
+	if merged.Table("tags") != nil {
+		t.Error("tags should have been removed")
+	}
+	if comments := merged.Table("comments"); comments == nil || comments.Position.Y <= 500 || comments.Position.X != -400 {
+		t.Errorf("comments = %+v, want it below the existing tables", comments)
+	}
+	if len(merged.Relationships) != 2 || merged.Relationships[0].ID != relationshipID {
+		t.Errorf("Merge() relationships = %+v, want the existing posts.user_id edge kept", merged.Relationships)
+	}
+	for _, relationship := range merged.Relationships {
+		if _, ok := relationshipEndpoints(merged, relationship); !ok {
+			t.Errorf("relationship %s points at a missing column", relationship.ID)
+		}
+	}
+
+	if _, changes := Merge(merged, Build(after)); len(changes) != 0 {
+		t.Errorf("Merge() of an up-to-date diagram = %v, want no changes", changes)
+	}
+}
+
+func TestMergeReversedRelationship(t *testing.T) {
+	schema, err := ParseSQL(`
+CREATE TABLE users (id bigint PRIMARY KEY);
+CREATE TABLE posts (id bigint PRIMARY KEY, user_id bigint REFERENCES users (id));
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Drawn by hand from users.id to posts.user_id
+	existing := Build(schema)
+	users, posts := existing.Table("users"), existing.Table("posts")
+	existing.Relationships = []Relationship{
+		NewRelationship(users, users.Column("id"), posts, posts.Column("user_id"), OneToMany),
+	}
+
+	if _, changes := Merge(existing, Build(schema)); len(changes) != 0 {
+		t.Errorf("Merge() = %v, want a reversed one-to-many edge to match", changes)
+	}
+}
+
+func TestParseSource(t *testing.T) {
+	dir := t.TempDir()
+	wd, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	os.MkdirAll(filepath.Join("db", "migrations"), 0755)
+	os.WriteFile(filepath.Join("db", "migrations", "1_users.sql"), []byte("CREATE TABLE users (id bigint PRIMARY KEY);"), 0644)
+	os.MkdirAll("models", 0755)
+	os.WriteFile(filepath.Join("models", "models.go"), []byte("package models\n\ntype Post struct {\n\tID uint `gorm:\"primaryKey\"`\n}\n"), 0644)
+
+	tests := map[string]string{
+		"db/migrations":             "users",
+		"db/migrations/1_users.sql": "users",
+		"models":                    "posts",
+		"./models/...":              "posts",
+	}
+	for source, table := range tests {
+		schema, err := ParseSource(source)
+		if err != nil {
+			t.Errorf("ParseSource(%q) error = %v", source, err)
+			continue
+		}
+		if schema.Table(table) == nil {
+			t.Errorf("ParseSource(%q) tables = %+v, want %s", source, schema.Tables, table)
+		}
+	}
+
+	if _, err := ParseSource("../elsewhere"); err == nil {
+		t.Error("ParseSource() should reject paths outside the repository")
+	}
+	if _, err := ParseSource(" , "); err == nil {
+		t.Error("ParseSource() should reject an empty source")
+	}
+}
@@ -0,0 +1,75 @@
+package erd
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// DataTypes are the column types the <ERD> component accepts, matching the enum in validate-and-encode.js
+var DataTypes = []string{
+	"smallint", "integer", "bigint", "decimal", "numeric", "real", "double precision",
+	"smallserial", "serial", "bigserial", "money",
+	"char", "varchar", "text", "bytea",
+	"date", "time", "timetz", "timestamp", "timestamptz", "interval",
+	"boolean",
+	"inet", "cidr", "macaddr", "macaddr8",
+	"bit", "bit varying", "varbit",
+	"tsvector", "tsquery",
+	"uuid", "xml", "json", "jsonb",
+	"point", "line", "lseg", "box", "path", "polygon", "circle",
+	"int4range", "int8range", "numrange", "daterange", "tsrange", "tstzrange",
+	"pg_lsn", "txid_snapshot",
+}
+
+// typeAliases maps PostgreSQL type spellings to their name in DataTypes
+var typeAliases = map[string]string{
+	"int":                         "integer",
+	"int4":                        "integer",
+	"int2":                        "smallint",
+	"int8":                        "bigint",
+	"serial2":                     "smallserial",
+	"serial4":                     "serial",
+	"serial8":                     "bigserial",
+	"float":                       "double precision",
+	"float4":                      "real",
+	"float8":                      "double precision",
+	"dec":                         "decimal",
+	"character":                   "char",
+	"bpchar":                      "char",
+	"character varying":           "varchar",
+	"bool":                        "boolean",
+	"timestamp without time zone": "timestamp",
+	"timestamp with time zone":    "timestamptz",
+	"time without time zone":      "time",
+	"time with time zone":         "timetz",
+}
+
+// typeModifiers matches length, precision and array modifiers such as "(255)", "(10, 2)" or "[]"
+var typeModifiers = regexp.MustCompile(`\s*(\([^)]*\)|\[\s*\d*\s*\])`)
+
+// IsDataType reports whether t is one of the ERD column types
+func IsDataType(t string) bool {
+	return slices.Contains(DataTypes, t)
+}
+
+// NormalizeType maps a PostgreSQL column type to an ERD column type
+// Modifiers and array brackets are dropped; types the ERD has no equivalent for (enums, domains, extensions) become text
+func NormalizeType(sqlType string) string {
+	t := strings.ToLower(strings.TrimSpace(sqlType))
+	t = typeModifiers.ReplaceAllString(t, "")
+	t = strings.TrimSuffix(t, " array")
+	t = strings.Join(strings.Fields(t), " ")
+	t = strings.TrimPrefix(t, "pg_catalog.")
+	if strings.HasPrefix(t, "interval ") {
+		t = "interval" // field qualifiers such as "interval day to second"
+	}
+
+	if alias, ok := typeAliases[t]; ok {
+		t = alias
+	}
+	if IsDataType(t) {
+		return t
+	}
+	return "text"
+}
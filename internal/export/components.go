@@ -0,0 +1,46 @@
+package export
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"doclific/internal/core"
+)
+
+// replaceComponents replaces every CodebaseSnippet, ERD and HttpRequest element in content
+// with the output of render, leaving the surrounding MDX untouched
+func replaceComponents(content string, render func(component core.MDXComponent) (string, error)) (string, error) {
+	var components []core.MDXComponent
+	for _, name := range []string{core.ComponentCodebaseSnippet, core.ComponentERD, core.ComponentHttpRequest} {
+		components = append(components, core.ParseMDXComponents(content, name)...)
+	}
+	sort.Slice(components, func(i, j int) bool {
+		return components[i].Start < components[j].Start
+	})
+
+	var b strings.Builder
+	offset := 0
+	for _, component := range components {
+		if component.Start < offset {
+			continue // Nested inside a component we already replaced
+		}
+		replacement, err := render(component)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(content[offset:component.Start])
+		b.WriteString(replacement)
+		offset = component.End
+	}
+	b.WriteString(content[offset:])
+
+	return b.String(), nil
+}
+
+// snippetLines returns the lineStart and lineEnd attributes of a CodebaseSnippet as ints (0 if unset)
+func snippetLines(component core.MDXComponent) (int, int) {
+	lineStart, _ := strconv.Atoi(component.Attributes["lineStart"])
+	lineEnd, _ := strconv.Atoi(component.Attributes["lineEnd"])
+	return lineStart, lineEnd
+}
@@ -0,0 +1,236 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"doclific/internal/core"
+	"doclific/internal/erd"
+	"doclific/internal/httpreq"
+
+	"github.com/alecthomas/chroma/v2"
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	gmhtml "github.com/yuin/goldmark/renderer/html"
+)
+
+// markdown renders CommonMark with GitHub extensions; raw HTML is kept so unknown MDX tags pass through
+var markdown = goldmark.New(
+	goldmark.WithExtensions(extension.GFM),
+	goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+	goldmark.WithRendererOptions(gmhtml.WithUnsafe()),
+)
+
+// docLinkTargetRegex matches the target of a doc: markdown link
+var docLinkTargetRegex = regexp.MustCompile(`\]\(doc:([^)\s]+)\)`)
+
+// rewriteDocLinks replaces doc: link targets with the URLs returned by href
+// Targets that href cannot resolve are left as they are, and a target's #fragment is kept
+// unless the returned URL already has one
+func rewriteDocLinks(content string, href func(target string) (string, bool)) string {
+	return docLinkTargetRegex.ReplaceAllStringFunc(content, func(match string) string {
+		target := docLinkTargetRegex.FindStringSubmatch(match)[1]
+		url, ok := href(target)
+		if !ok {
+			return match
+		}
+		if anchor := strings.SplitN(target, "#", 2); len(anchor) == 2 && !strings.Contains(url, "#") {
+			url += "#" + anchor[1]
+		}
+		return "](" + url + ")"
+	})
+}
+
+// renderMDXToHTML converts a doc's MDX to HTML, rendering components as static HTML
+func renderMDXToHTML(content string, href func(target string) (string, bool)) (string, error) {
+	// Components are swapped for placeholders before markdown rendering so that blank
+	// lines inside highlighted code cannot end an HTML block early
+	var blocks []string
+	content, err := replaceComponents(content, func(component core.MDXComponent) (string, error) {
+		var block string
+		switch component.Name {
+		case core.ComponentCodebaseSnippet:
+			block = snippetHTML(component)
+		case core.ComponentERD:
+			block = erdHTML(component)
+		case core.ComponentHttpRequest:
+			block = httpRequestHTML(component)
+		}
+		blocks = append(blocks, block)
+		return fmt.Sprintf("\n\nDOCLIFICBLOCK%dEND\n\n", len(blocks)-1), nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := markdown.Convert([]byte(rewriteDocLinks(content, href)), &buf); err != nil {
+		return "", fmt.Errorf("failed to render markdown: %w", err)
+	}
+
+	output := buf.String()
+	for i, block := range blocks {
+		output = strings.Replace(output, fmt.Sprintf("<p>DOCLIFICBLOCK%dEND</p>", i), block, 1)
+	}
+
+	return output, nil
+}
+
+// highlightCode renders code as highlighted HTML with inline styles, picking the lexer from the file name
+func highlightCode(code string, fileName string, firstLine int) string {
+	lexer := lexers.Match(filepath.Base(fileName))
+	if lexer == nil {
+		lexer = lexers.Fallback
+	}
+	lexer = chroma.Coalesce(lexer)
+
+	if firstLine < 1 {
+		firstLine = 1
+	}
+	formatter := chromahtml.New(
+		chromahtml.WithLineNumbers(true),
+		chromahtml.BaseLineNumber(firstLine),
+	)
+
+	iterator, err := lexer.Tokenise(nil, code)
+	if err != nil {
+		return "<pre><code>" + html.EscapeString(code) + "</code></pre>"
+	}
+
+	var buf bytes.Buffer
+	if err := formatter.Format(&buf, styles.Get("github"), iterator); err != nil {
+		return "<pre><code>" + html.EscapeString(code) + "</code></pre>"
+	}
+	return buf.String()
+}
+
+// snippetHTML resolves a CodebaseSnippet against the working directory and renders it as highlighted code
+func snippetHTML(component core.MDXComponent) string {
+	filePath := component.Attributes["filePath"]
+	lineStart, lineEnd := snippetLines(component)
+
+	result, err := core.ResolveSnippet(filePath, lineStart, lineEnd, component.Attributes["contentHash"])
+	if err != nil {
+		return fmt.Sprintf(`<div class="snippet snippet-missing"><div class="snippet-header">%s</div><p>Snippet unavailable: %s</p></div>`,
+			html.EscapeString(filePath), html.EscapeString(err.Error()))
+	}
+
+	location := filePath
+	if result.LineStart > 0 && result.LineEnd > 0 {
+		location = fmt.Sprintf("%s:%d-%d", filePath, result.LineStart, result.LineEnd)
+	}
+
+	var b strings.Builder
+	b.WriteString(`<div class="snippet"><div class="snippet-header">`)
+	b.WriteString(html.EscapeString(location))
+	if result.NeedsReview {
+		b.WriteString(` <span class="badge badge-warning">may be out of date</span>`)
+	}
+	b.WriteString(`</div>`)
+	b.WriteString(highlightCode(result.Contents, filePath, result.LineStart))
+	b.WriteString(`</div>`)
+	return b.String()
+}
+
+// erdHTML renders an ERD block as one table per entity followed by the relationships
+func erdHTML(component core.MDXComponent) string {
+	diagram, err := erd.ParseComponent(component)
+	if err != nil {
+		return fmt.Sprintf(`<div class="erd erd-invalid"><p>Invalid ERD: %s</p></div>`, html.EscapeString(err.Error()))
+	}
+
+	var b strings.Builder
+	b.WriteString(`<div class="erd"><div class="erd-tables">`)
+	for _, table := range diagram.Tables {
+		b.WriteString(`<table class="erd-table"><thead><tr><th colspan="3">`)
+		b.WriteString(html.EscapeString(table.Data.Name))
+		b.WriteString(`</th></tr></thead><tbody>`)
+		for _, column := range table.Data.Columns {
+			var flags []string
+			if column.PrimaryKey {
+				flags = append(flags, "PK")
+			}
+			if column.Unique {
+				flags = append(flags, "UQ")
+			}
+			if column.Nullable {
+				flags = append(flags, "NULL")
+			}
+			fmt.Fprintf(&b, `<tr><td>%s</td><td><code>%s</code></td><td class="erd-flags">%s</td></tr>`,
+				html.EscapeString(column.Name), html.EscapeString(column.Type), strings.Join(flags, " "))
+		}
+		b.WriteString(`</tbody></table>`)
+	}
+	b.WriteString(`</div>`)
+
+	if edges := diagram.Edges(); len(edges) > 0 {
+		b.WriteString(`<ul class="erd-relationships">`)
+		for _, edge := range edges {
+			fmt.Fprintf(&b, `<li><code>%s.%s</code> → <code>%s.%s</code> <span class="badge">%s</span></li>`,
+				html.EscapeString(edge.SourceTable), html.EscapeString(edge.SourceColumn),
+				html.EscapeString(edge.TargetTable), html.EscapeString(edge.TargetColumn),
+				html.EscapeString(edge.Type))
+		}
+		b.WriteString(`</ul>`)
+	}
+	b.WriteString(`</div>`)
+	return b.String()
+}
+
+// keyValueTableHTML renders enabled key/value pairs as a two-column table
+func keyValueTableHTML(title string, pairs []httpreq.KeyValue) string {
+	pairs = httpreq.Enabled(pairs)
+	if len(pairs) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<table class="kv-table"><thead><tr><th colspan="2">%s</th></tr></thead><tbody>`, html.EscapeString(title))
+	for _, pair := range pairs {
+		fmt.Fprintf(&b, `<tr><td><code>%s</code></td><td><code>%s</code></td></tr>`, html.EscapeString(pair.Key), html.EscapeString(pair.Value))
+	}
+	b.WriteString(`</tbody></table>`)
+	return b.String()
+}
+
+// httpRequestHTML renders an HttpRequest block as a static request description
+func httpRequestHTML(component core.MDXComponent) string {
+	request := httpreq.ParseComponent(component)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<div class="http-request"><div class="http-request-line"><span class="method method-%s">%s</span> <code>%s</code></div>`,
+		strings.ToLower(html.EscapeString(request.Method)), html.EscapeString(request.Method), html.EscapeString(request.URL))
+
+	if request.Auth.Type != "" && request.Auth.Type != "none" {
+		fmt.Fprintf(&b, `<p class="http-auth">Auth: <code>%s</code></p>`, html.EscapeString(request.Auth.Type))
+	}
+
+	b.WriteString(keyValueTableHTML("Query parameters", request.QueryParams))
+	b.WriteString(keyValueTableHTML("Headers", request.Headers))
+
+	switch request.BodyType {
+	case "json", "raw":
+		if request.BodyContent != "" {
+			fileName := "body.txt"
+			if request.BodyType == "json" {
+				fileName = "body.json"
+			}
+			b.WriteString(`<div class="http-body">`)
+			b.WriteString(highlightCode(request.BodyContent, fileName, 1))
+			b.WriteString(`</div>`)
+		}
+	case "form-data", "x-www-form-urlencoded":
+		b.WriteString(keyValueTableHTML("Form data", request.FormData))
+	}
+
+	b.WriteString(`</div>`)
+	return b.String()
+}
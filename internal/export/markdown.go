@@ -0,0 +1,260 @@
+package export
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"doclific/internal/core"
+	"doclific/internal/erd"
+	"doclific/internal/httpreq"
+
+	"github.com/alecthomas/chroma/v2/lexers"
+)
+
+// MarkdownOptions configures ExportMarkdown
+type MarkdownOptions struct {
+	OutDir string
+}
+
+// MarkdownResult summarizes an ExportMarkdown run
+type MarkdownResult struct {
+	OutDir string `json:"outDir"`
+	Files  int    `json:"files"`
+}
+
+// markdownFile is a doc written to <OutDir>/<RelPath>
+type markdownFile struct {
+	FilePath string // folder path relative to doclific
+	RelPath  string // output path relative to OutDir, e.g. "Architecture/Backend.md"
+	Doc      core.FolderStructure
+}
+
+// unsafeFileNameChars matches characters that are not portable in file names
+var unsafeFileNameChars = regexp.MustCompile(`[<>:"/\\|?*\x00-\x1f]`)
+
+// fileNameFromTitle turns a doc title into a portable file name
+func fileNameFromTitle(title string) string {
+	name := strings.TrimSpace(unsafeFileNameChars.ReplaceAllString(title, "-"))
+	name = strings.Trim(name, ".")
+	if name == "" {
+		name = "Untitled"
+	}
+	return name
+}
+
+// collectMarkdownFiles maps every doc to an output path mirroring the tree, using titles as names
+// A doc with children is written as Title.md next to a Title/ folder holding its children
+func collectMarkdownFiles(docs []core.FolderStructure, parentDir string, parentPath string) []markdownFile {
+	var files []markdownFile
+	used := map[string]bool{}
+
+	for _, doc := range docs {
+		filePath := doc.Name
+		if parentPath != "" {
+			filePath = parentPath + "/" + doc.Name
+		}
+
+		// Sibling titles may collide; suffix duplicates so no file is overwritten
+		base := fileNameFromTitle(doc.Title)
+		name := base
+		for i := 2; used[strings.ToLower(name)]; i++ {
+			name = fmt.Sprintf("%s (%d)", base, i)
+		}
+		used[strings.ToLower(name)] = true
+
+		files = append(files, markdownFile{
+			FilePath: filePath,
+			RelPath:  joinSlash(parentDir, name+".md"),
+			Doc:      doc,
+		})
+		files = append(files, collectMarkdownFiles(doc.Children, joinSlash(parentDir, name), filePath)...)
+	}
+
+	return files
+}
+
+// joinSlash joins slash-separated path segments, ignoring an empty parent
+func joinSlash(parent string, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "/" + name
+}
+
+// relativeLink returns a markdown link target from the file at fromRelPath to toRelPath (both slash paths)
+func relativeLink(fromRelPath string, toRelPath string) string {
+	rel, err := filepath.Rel(filepath.Dir(filepath.FromSlash(fromRelPath)), filepath.FromSlash(toRelPath))
+	if err != nil {
+		rel = toRelPath
+	}
+	segments := strings.Split(filepath.ToSlash(rel), "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// fence returns a code fence longer than any backtick run in code
+func fence(code string) string {
+	longest, run := 0, 0
+	for _, r := range code {
+		if r == '`' {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	return strings.Repeat("`", max(3, longest+1))
+}
+
+// languageForFile returns the fenced code block language for a file name
+func languageForFile(fileName string) string {
+	lexer := lexers.Match(filepath.Base(fileName))
+	if lexer == nil {
+		return ""
+	}
+	if aliases := lexer.Config().Aliases; len(aliases) > 0 {
+		return aliases[0]
+	}
+	return strings.ToLower(lexer.Config().Name)
+}
+
+// snippetMarkdown renders a CodebaseSnippet as a fenced code block with a link to the file
+// fileLink is the link target for the snippet's file relative to the output file
+func snippetMarkdown(component core.MDXComponent, fileLink string) string {
+	filePath := component.Attributes["filePath"]
+	lineStart, lineEnd := snippetLines(component)
+
+	result, err := core.ResolveSnippet(filePath, lineStart, lineEnd, component.Attributes["contentHash"])
+	if err != nil {
+		return fmt.Sprintf("> Snippet unavailable: `%s` (%s)\n", filePath, err.Error())
+	}
+
+	label := filePath
+	if result.LineStart > 0 && result.LineEnd > 0 {
+		label = fmt.Sprintf("%s (lines %d-%d)", filePath, result.LineStart, result.LineEnd)
+		fileLink += fmt.Sprintf("#L%d-L%d", result.LineStart, result.LineEnd)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "[%s](%s)\n\n", label, fileLink)
+	f := fence(result.Contents)
+	fmt.Fprintf(&b, "%s%s\n%s\n%s\n", f, languageForFile(filePath), strings.TrimRight(result.Contents, "\n"), f)
+	return b.String()
+}
+
+// erdMarkdown renders an ERD block as a Mermaid erDiagram
+func erdMarkdown(component core.MDXComponent) string {
+	diagram, err := erd.ParseComponent(component)
+	if err != nil {
+		return fmt.Sprintf("> Invalid ERD: %s\n", err.Error())
+	}
+	return "```mermaid\n" + diagram.Mermaid() + "```\n"
+}
+
+// httpRequestMarkdown renders an HttpRequest block as a curl example
+func httpRequestMarkdown(component core.MDXComponent) string {
+	command := httpreq.ParseComponent(component).Curl()
+	f := fence(command)
+	return fmt.Sprintf("%sbash\n%s\n%s\n", f, command, f)
+}
+
+// renderMDXToMarkdown converts a doc's MDX to CommonMark, resolving components
+// fileLink returns the link target for a repository file; href resolves doc: link targets
+func renderMDXToMarkdown(content string, fileLink func(filePath string) string, href func(target string) (string, bool)) (string, error) {
+	content, err := replaceComponents(content, func(component core.MDXComponent) (string, error) {
+		switch component.Name {
+		case core.ComponentCodebaseSnippet:
+			return snippetMarkdown(component, fileLink(component.Attributes["filePath"])), nil
+		case core.ComponentERD:
+			return erdMarkdown(component), nil
+		case core.ComponentHttpRequest:
+			return httpRequestMarkdown(component), nil
+		}
+		return "", nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return rewriteDocLinks(content, href), nil
+}
+
+// ExportMarkdown writes every doc as plain CommonMark into a directory mirroring the doc tree
+// CodebaseSnippets become fenced code blocks, ERDs Mermaid diagrams and HttpRequests curl examples
+func ExportMarkdown(options MarkdownOptions) (*MarkdownResult, error) {
+	if options.OutDir == "" {
+		return nil, fmt.Errorf("output directory is required")
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current working directory: %w", err)
+	}
+
+	outDir, err := filepath.Abs(options.OutDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve output directory: %w", err)
+	}
+
+	docs, err := core.GetDocs()
+	if err != nil {
+		return nil, err
+	}
+
+	graph, err := core.BuildLinkGraph()
+	if err != nil {
+		return nil, err
+	}
+
+	files := collectMarkdownFiles(docs, "", "")
+	relPaths := map[string]string{}
+	for _, file := range files {
+		relPaths[file.FilePath] = file.RelPath
+	}
+
+	for _, file := range files {
+		content, err := core.GetDoc(file.FilePath)
+		if err != nil {
+			return nil, err
+		}
+
+		outPath := filepath.Join(outDir, filepath.FromSlash(file.RelPath))
+
+		// Links to repository files are relative to the output file so they work when the export lives in the repo
+		fileLink := func(filePath string) string {
+			rel, err := filepath.Rel(filepath.Dir(outPath), filepath.Join(cwd, filePath))
+			if err != nil {
+				return filePath
+			}
+			return filepath.ToSlash(rel)
+		}
+		href := func(target string) (string, bool) {
+			for _, link := range graph.Links {
+				if link.Source == file.FilePath && link.Target == target && !link.Broken {
+					return relativeLink(file.RelPath, relPaths[link.Resolved]), true
+				}
+			}
+			return "", false
+		}
+
+		markdown, err := renderMDXToMarkdown(content, fileLink, href)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render %s: %w", file.Doc.Title, err)
+		}
+
+		if err := os.MkdirAll(filepath.Dir(outPath), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create directory: %w", err)
+		}
+		if err := os.WriteFile(outPath, []byte(markdown), 0644); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", file.RelPath, err)
+		}
+	}
+
+	return &MarkdownResult{OutDir: options.OutDir, Files: len(files)}, nil
+}
@@ -0,0 +1,87 @@
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExportMarkdown(t *testing.T) {
+	tmpDir := setupDocs(t, testDocs)
+	outDir := filepath.Join(tmpDir, "docs-md")
+
+	result, err := ExportMarkdown(MarkdownOptions{OutDir: outDir})
+	if err != nil {
+		t.Fatalf("ExportMarkdown() error = %v", err)
+	}
+	if result.Files != 3 {
+		t.Errorf("ExportMarkdown() wrote %d files, want 3", result.Files)
+	}
+
+	read := func(path string) string {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join(outDir, filepath.FromSlash(path)))
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", path, err)
+		}
+		return string(data)
+	}
+
+	// Files are named by title, with children in a folder next to their parent
+	arch := read("Architecture.md")
+	if !strings.Contains(arch, "[backend](Architecture/Backend.md#setup)") {
+		t.Errorf("Architecture.md did not rewrite the doc: link:\n%s", arch)
+	}
+	if !strings.Contains(arch, "[main.go (lines 3-5)](../main.go#L3-L5)") {
+		t.Errorf("Architecture.md did not link the snippet's file:\n%s", arch)
+	}
+	if !strings.Contains(arch, "```go\nfunc main() {\n\tprintln(\"hi\")\n}\n```") {
+		t.Errorf("Architecture.md did not inline the snippet as a fenced block:\n%s", arch)
+	}
+	if strings.Contains(arch, "<CodebaseSnippet") {
+		t.Error("Architecture.md still contains the CodebaseSnippet element")
+	}
+
+	backend := read("Architecture/Backend.md")
+	if !strings.Contains(backend, "```mermaid\nerDiagram\n    users {\n        uuid id PK\n    }\n```") {
+		t.Errorf("Backend.md did not render the ERD as Mermaid:\n%s", backend)
+	}
+	for _, want := range []string{
+		"curl -X POST 'https://api.example.com/users'",
+		"-H 'Accept: application/json'",
+		"-H 'Authorization: Bearer t'",
+		`--data '{"name":"Ada"}'`,
+	} {
+		if !strings.Contains(backend, want) {
+			t.Errorf("Backend.md curl example missing %q:\n%s", want, backend)
+		}
+	}
+
+	read("Guide.md")
+}
+
+func TestExportMarkdownFileNames(t *testing.T) {
+	setupDocs(t, map[string]string{
+		"doclific/a/config.json": `{"title": "Notes", "order": 0}`,
+		"doclific/a/content.mdx": "",
+		"doclific/b/config.json": `{"title": "notes", "order": 1}`,
+		"doclific/b/content.mdx": "",
+		"doclific/c/config.json": `{"title": "A/B: C?", "order": 2}`,
+		"doclific/c/content.mdx": "",
+	})
+
+	result, err := ExportMarkdown(MarkdownOptions{OutDir: "out"})
+	if err != nil {
+		t.Fatalf("ExportMarkdown() error = %v", err)
+	}
+	if result.Files != 3 {
+		t.Fatalf("ExportMarkdown() wrote %d files, want 3", result.Files)
+	}
+
+	for _, name := range []string{"Notes.md", "notes (2).md", "A-B- C-.md"} {
+		if _, err := os.Stat(filepath.Join("out", name)); err != nil {
+			t.Errorf("expected %s to be written: %v", name, err)
+		}
+	}
+}
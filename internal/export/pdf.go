@@ -0,0 +1,197 @@
+package export
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"doclific/internal/core"
+
+	"github.com/go-pdf/fpdf"
+)
+
+const (
+	pdfFont       = "Helvetica"
+	pdfMonoFont   = "Courier"
+	pdfLineHeight = 5.5
+	pdfCodeHeight = 4.2
+)
+
+var (
+	pdfImageRegex  = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	pdfLinkRegex   = regexp.MustCompile(`\[([^\]]+)\]\([^)]*\)`)
+	pdfEmphasis    = regexp.MustCompile(`\*\*|__|~~`)
+	pdfTagRegex    = regexp.MustCompile(`</?[A-Za-z][^>]*>`)
+	pdfHeadingSize = map[int]float64{1: 18, 2: 15, 3: 13, 4: 12, 5: 11, 6: 11}
+)
+
+// plainText strips inline markdown and HTML so a line can be written as PDF text
+func plainText(line string) string {
+	line = pdfImageRegex.ReplaceAllString(line, "$1")
+	line = pdfLinkRegex.ReplaceAllString(line, "$1")
+	line = pdfEmphasis.ReplaceAllString(line, "")
+	line = strings.ReplaceAll(line, "`", "")
+	return pdfTagRegex.ReplaceAllString(line, "")
+}
+
+// pdfWriter renders Markdown into an fpdf document using the built-in fonts
+type pdfWriter struct {
+	pdf *fpdf.Fpdf
+	tr  func(string) string
+}
+
+// paragraph writes text wrapped to the page width
+func (w *pdfWriter) paragraph(text string) {
+	if strings.TrimSpace(text) == "" {
+		return
+	}
+	w.pdf.SetFont(pdfFont, "", 10.5)
+	w.pdf.MultiCell(0, pdfLineHeight, w.tr(text), "", "L", false)
+	w.pdf.Ln(1.5)
+}
+
+// code writes a fenced block in a monospace font on a shaded background
+func (w *pdfWriter) code(lines []string) {
+	w.pdf.SetFont(pdfMonoFont, "", 8.5)
+	w.pdf.SetFillColor(246, 248, 250)
+	text := strings.ReplaceAll(strings.Join(lines, "\n"), "\t", "    ")
+	w.pdf.MultiCell(0, pdfCodeHeight, w.tr(text), "", "L", true)
+	w.pdf.Ln(2)
+}
+
+// markdown writes a Markdown document block by block: headings, lists, fenced code and paragraphs
+func (w *pdfWriter) markdown(content string) {
+	var paragraph []string
+	flush := func() {
+		w.paragraph(strings.Join(paragraph, " "))
+		paragraph = nil
+	}
+
+	var codeLines []string
+	codeFence := ""
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if codeFence != "" {
+			if strings.HasPrefix(trimmed, codeFence) && strings.Trim(trimmed, "`") == "" {
+				w.code(codeLines)
+				codeLines, codeFence = nil, ""
+			} else {
+				codeLines = append(codeLines, line)
+			}
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(trimmed, "```"):
+			flush()
+			codeFence = trimmed[:len(trimmed)-len(strings.TrimLeft(trimmed, "`"))]
+		case trimmed == "":
+			flush()
+		case strings.HasPrefix(trimmed, "#"):
+			flush()
+			level := len(trimmed) - len(strings.TrimLeft(trimmed, "#"))
+			size, ok := pdfHeadingSize[level]
+			if !ok {
+				size = 11
+			}
+			w.pdf.Ln(2)
+			w.pdf.SetFont(pdfFont, "B", size)
+			w.pdf.MultiCell(0, size*0.5, w.tr(plainText(strings.TrimSpace(trimmed[level:]))), "", "L", false)
+			w.pdf.Ln(1.5)
+		case strings.HasPrefix(trimmed, "- "), strings.HasPrefix(trimmed, "* "), strings.HasPrefix(trimmed, "+ "):
+			flush()
+			indent := float64(len(line)-len(strings.TrimLeft(line, " \t"))) * 2
+			w.pdf.SetFont(pdfFont, "", 10.5)
+			w.pdf.SetX(w.pdf.GetX() + indent)
+			w.pdf.MultiCell(0, pdfLineHeight, w.tr("- "+plainText(trimmed[2:])), "", "L", false)
+		case strings.HasPrefix(trimmed, "|"):
+			flush()
+			if strings.Trim(trimmed, "|-: ") == "" {
+				continue // Table delimiter row
+			}
+			w.pdf.SetFont(pdfMonoFont, "", 8.5)
+			w.pdf.MultiCell(0, pdfCodeHeight, w.tr(plainText(trimmed)), "", "L", false)
+		case strings.HasPrefix(trimmed, ">"):
+			flush()
+			w.pdf.SetFont(pdfFont, "I", 10.5)
+			w.pdf.MultiCell(0, pdfLineHeight, w.tr(plainText(strings.TrimSpace(strings.TrimPrefix(trimmed, ">")))), "", "L", false)
+		default:
+			if text := plainText(trimmed); text != "" {
+				paragraph = append(paragraph, text)
+			}
+		}
+	}
+	if codeFence != "" {
+		w.code(codeLines)
+	}
+	flush()
+}
+
+// writePrintPDF renders the bundle as a PDF with a cover, a linked table of contents and bookmarks
+// Components are rendered the same way as the Markdown export, since there is no HTML layout engine
+func writePrintPDF(options PrintOptions, docs []printDoc) error {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(20, 20, 20)
+	pdf.SetAutoPageBreak(true, 20)
+	pdf.SetTitle(options.Title, true)
+	pdf.SetCreator("doclific", true)
+
+	w := &pdfWriter{pdf: pdf, tr: pdf.UnicodeTranslatorFromDescriptor("")}
+
+	pdf.SetFooterFunc(func() {
+		if pdf.PageNo() == 1 {
+			return
+		}
+		pdf.SetY(-15)
+		pdf.SetFont(pdfFont, "", 8)
+		pdf.SetTextColor(101, 109, 118)
+		pdf.CellFormat(0, 10, fmt.Sprintf("%s · %d", w.tr(options.Title), pdf.PageNo()), "", 0, "C", false, 0, "")
+		pdf.SetTextColor(0, 0, 0)
+	})
+
+	// Cover
+	pdf.AddPage()
+	pdf.SetY(110)
+	pdf.SetFont(pdfFont, "B", 26)
+	pdf.MultiCell(0, 12, w.tr(options.Title), "", "C", false)
+
+	// Table of contents; links are pointed at each doc's page as it is written
+	pdf.AddPage()
+	pdf.SetFont(pdfFont, "B", 18)
+	pdf.CellFormat(0, 12, "Contents", "", 1, "L", false, 0, "")
+	links := make([]int, len(docs))
+	for i, doc := range docs {
+		links[i] = pdf.AddLink()
+		pdf.SetFont(pdfFont, "", 11)
+		pdf.SetX(20 + float64(doc.Depth)*6)
+		pdf.CellFormat(0, 7, w.tr(doc.Title), "", 1, "L", false, links[i], "")
+	}
+
+	for i, doc := range docs {
+		content, err := core.GetDoc(doc.FilePath)
+		if err != nil {
+			return err
+		}
+
+		markdown, err := renderMDXToMarkdown(stripLeadingTitle(content, doc.Title),
+			func(filePath string) string { return filePath },
+			func(target string) (string, bool) { return "", false })
+		if err != nil {
+			return fmt.Errorf("failed to render %s: %w", doc.Title, err)
+		}
+
+		pdf.AddPage()
+		pdf.SetLink(links[i], 0, -1)
+		pdf.Bookmark(w.tr(doc.Title), doc.Depth, -1)
+		pdf.SetFont(pdfFont, "B", 22)
+		pdf.MultiCell(0, 11, w.tr(doc.Title), "", "L", false)
+		pdf.Ln(3)
+		w.markdown(markdown)
+	}
+
+	if err := pdf.OutputFileAndClose(options.OutFile); err != nil {
+		return fmt.Errorf("failed to write %s: %w", options.OutFile, err)
+	}
+	return nil
+}
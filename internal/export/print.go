@@ -0,0 +1,263 @@
+package export
+
+import (
+	_ "embed"
+	"fmt"
+	"html"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"doclific/internal/core"
+)
+
+//go:embed print.css
+var printCSS string
+
+var printTemplate = template.Must(template.New("print").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+{{.CSS}}
+</style>
+</head>
+<body>
+<header class="print-cover">
+<h1>{{.Title}}</h1>
+{{if .Subtitle}}<p>{{.Subtitle}}</p>{{end}}
+</header>
+<nav class="print-toc">
+<h2>Contents</h2>
+{{.TOC}}
+</nav>
+{{range .Docs}}<section class="print-doc" id="{{.Anchor}}">
+<h1 class="print-doc-title">{{.Title}}</h1>
+{{.Content}}
+</section>
+{{end}}</body>
+</html>
+`))
+
+// PrintOptions configures BuildPrintBundle
+type PrintOptions struct {
+	Root    string // folder or slug path of the subtree to include; empty includes every doc
+	OutFile string
+	Title   string // defaults to the root doc's title, or the repository name
+	PDF     bool   // write a PDF instead of a printable HTML document
+}
+
+// PrintResult summarizes a BuildPrintBundle run
+type PrintResult struct {
+	OutFile string `json:"outFile"`
+	Docs    int    `json:"docs"`
+}
+
+// printDoc is a doc included in a print bundle
+type printDoc struct {
+	FilePath string // folder path relative to doclific
+	Anchor   string
+	Title    string
+	Depth    int // 0 for top-level docs of the bundle
+	Doc      core.FolderStructure
+}
+
+// printAnchor returns the element ID of a doc's section in a print bundle
+func printAnchor(filePath string) string {
+	return "doc-" + strings.ReplaceAll(filePath, "/", "-")
+}
+
+// collectPrintDocs returns the docs of the subtree at root in FolderStructure order
+// The root doc itself comes first; an empty root selects the whole tree
+func collectPrintDocs(docs []core.FolderStructure, root string) ([]printDoc, error) {
+	var result []printDoc
+	found := root == ""
+
+	core.WalkDocs(docs, func(filePath string, doc core.FolderStructure) error {
+		if root != "" && filePath != root && !strings.HasPrefix(filePath, root+"/") {
+			return nil
+		}
+		found = true
+
+		depth := strings.Count(filePath, "/")
+		if root != "" {
+			depth -= strings.Count(root, "/")
+		}
+		result = append(result, printDoc{
+			FilePath: filePath,
+			Anchor:   printAnchor(filePath),
+			Title:    doc.Title,
+			Depth:    depth,
+			Doc:      doc,
+		})
+		return nil
+	})
+
+	if !found {
+		return nil, fmt.Errorf("doc not found: %s", root)
+	}
+	return result, nil
+}
+
+// tocHTML renders the bundle's table of contents as nested ordered lists
+func tocHTML(docs []printDoc) string {
+	var b strings.Builder
+	depth := -1
+	for _, doc := range docs {
+		if doc.Depth > depth {
+			for ; depth < doc.Depth; depth++ {
+				b.WriteString("<ol>")
+			}
+		} else {
+			b.WriteString("</li>")
+			for ; depth > doc.Depth; depth-- {
+				b.WriteString("</ol></li>")
+			}
+		}
+		fmt.Fprintf(&b, `<li><a href="#%s">%s</a>`, doc.Anchor, html.EscapeString(doc.Title))
+	}
+	for ; depth >= 0; depth-- {
+		b.WriteString("</li></ol>")
+	}
+	return b.String()
+}
+
+// resolvePrintRoot turns a folder or slug path into the folder path of the subtree root
+func resolvePrintRoot(root string) (string, error) {
+	root = strings.Trim(root, "/")
+	if root == "" {
+		return "", nil
+	}
+	resolution, err := core.ResolveSlugPath(root)
+	if err != nil {
+		return "", err
+	}
+	return resolution.FilePath, nil
+}
+
+// BuildPrintBundle concatenates a subtree of docs into one printable document with a table
+// of contents and a page break before each doc, optionally rendered as a PDF
+func BuildPrintBundle(options PrintOptions) (*PrintResult, error) {
+	if options.OutFile == "" {
+		return nil, fmt.Errorf("output file is required")
+	}
+
+	root, err := resolvePrintRoot(options.Root)
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := core.GetDocs()
+	if err != nil {
+		return nil, err
+	}
+
+	docs, err := collectPrintDocs(tree, root)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.Title == "" {
+		options.Title = defaultTitle()
+		if root != "" && len(docs) > 0 {
+			options.Title = docs[0].Title
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(options.OutFile), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	if options.PDF {
+		if err := writePrintPDF(options, docs); err != nil {
+			return nil, err
+		}
+		return &PrintResult{OutFile: options.OutFile, Docs: len(docs)}, nil
+	}
+
+	if err := writePrintHTML(options, docs); err != nil {
+		return nil, err
+	}
+	return &PrintResult{OutFile: options.OutFile, Docs: len(docs)}, nil
+}
+
+// writePrintHTML renders the bundle as a single self-contained HTML document
+func writePrintHTML(options PrintOptions, docs []printDoc) error {
+	graph, err := core.BuildLinkGraph()
+	if err != nil {
+		return err
+	}
+
+	anchors := map[string]string{}
+	for _, doc := range docs {
+		anchors[doc.FilePath] = doc.Anchor
+	}
+
+	type section struct {
+		Anchor  string
+		Title   string
+		Content template.HTML
+	}
+	sections := make([]section, 0, len(docs))
+
+	for _, doc := range docs {
+		content, err := core.GetDoc(doc.FilePath)
+		if err != nil {
+			return err
+		}
+
+		// doc: links to docs inside the bundle jump to their section; others are left as is.
+		// Heading anchors are dropped since heading IDs are not unique across docs
+		href := func(target string) (string, bool) {
+			for _, link := range graph.Links {
+				if link.Source == doc.FilePath && link.Target == target && !link.Broken {
+					anchor, ok := anchors[link.Resolved]
+					return "#" + anchor, ok
+				}
+			}
+			return "", false
+		}
+
+		body, err := renderMDXToHTML(stripLeadingTitle(content, doc.Title), href)
+		if err != nil {
+			return fmt.Errorf("failed to render %s: %w", doc.Title, err)
+		}
+
+		sections = append(sections, section{Anchor: doc.Anchor, Title: doc.Title, Content: template.HTML(body)})
+	}
+
+	subtitle := ""
+	if name, err := core.GetRepoName(); err == nil && name != "" && name != options.Title {
+		subtitle = name
+	}
+
+	file, err := os.Create(options.OutFile)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", options.OutFile, err)
+	}
+	defer file.Close()
+
+	if err := printTemplate.Execute(file, map[string]interface{}{
+		"Title":    options.Title,
+		"Subtitle": subtitle,
+		"CSS":      template.CSS(siteCSS + "\n" + printCSS),
+		"TOC":      template.HTML(tocHTML(docs)),
+		"Docs":     sections,
+	}); err != nil {
+		return fmt.Errorf("failed to write %s: %w", options.OutFile, err)
+	}
+	return nil
+}
+
+// stripLeadingTitle removes a first-line heading that repeats the doc title, since each
+// section already starts with the title
+func stripLeadingTitle(content string, title string) string {
+	trimmed := strings.TrimLeft(content, "\n")
+	firstLine, rest, _ := strings.Cut(trimmed, "\n")
+	if strings.TrimSpace(strings.TrimLeft(firstLine, "#")) == title && strings.HasPrefix(firstLine, "# ") {
+		return rest
+	}
+	return content
+}
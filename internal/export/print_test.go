@@ -0,0 +1,72 @@
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuildPrintBundle(t *testing.T) {
+	tmpDir := setupDocs(t, testDocs)
+	outFile := filepath.Join(tmpDir, "out", "handbook.html")
+
+	result, err := BuildPrintBundle(PrintOptions{OutFile: outFile, Title: "Handbook"})
+	if err != nil {
+		t.Fatalf("BuildPrintBundle() error = %v", err)
+	}
+	if result.Docs != 3 {
+		t.Errorf("BuildPrintBundle() included %d docs, want 3", result.Docs)
+	}
+
+	data, err := os.ReadFile(outFile)
+	if err != nil {
+		t.Fatalf("failed to read bundle: %v", err)
+	}
+	bundle := string(data)
+
+	// Sections follow sidebar order, each with a page break and a TOC entry
+	arch := strings.Index(bundle, `<section class="print-doc" id="doc-arch">`)
+	backend := strings.Index(bundle, `<section class="print-doc" id="doc-arch-backend-uuid">`)
+	guide := strings.Index(bundle, `<section class="print-doc" id="doc-guide">`)
+	if arch < 0 || backend < arch || guide < backend {
+		t.Errorf("bundle sections missing or out of order:\n%s", bundle)
+	}
+	if !strings.Contains(bundle, `<ol><li><a href="#doc-arch">Architecture</a><ol><li><a href="#doc-arch-backend-uuid">Backend</a></li></ol></li><li><a href="#doc-guide">Guide</a></li></ol>`) {
+		t.Errorf("bundle table of contents is not nested in doc order:\n%s", bundle)
+	}
+	if !strings.Contains(bundle, "page-break-before: always") {
+		t.Error("bundle does not include page breaks")
+	}
+	if !strings.Contains(bundle, `href="#doc-arch-backend-uuid"`) || strings.Contains(bundle, "doc:backend-uuid") {
+		t.Error("bundle did not rewrite the doc: link to the section anchor")
+	}
+	if strings.Count(bundle, "<h1") != 4 {
+		t.Errorf("bundle repeats doc titles, got %d h1 elements", strings.Count(bundle, "<h1"))
+	}
+}
+
+func TestBuildPrintBundleSubtreePDF(t *testing.T) {
+	tmpDir := setupDocs(t, testDocs)
+	outFile := filepath.Join(tmpDir, "handbook.pdf")
+
+	result, err := BuildPrintBundle(PrintOptions{Root: "architecture", OutFile: outFile, PDF: true})
+	if err != nil {
+		t.Fatalf("BuildPrintBundle() error = %v", err)
+	}
+	if result.Docs != 2 {
+		t.Errorf("BuildPrintBundle() included %d docs, want 2", result.Docs)
+	}
+
+	data, err := os.ReadFile(outFile)
+	if err != nil {
+		t.Fatalf("failed to read PDF: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "%PDF-") {
+		t.Errorf("output is not a PDF: %q", data[:min(len(data), 16)])
+	}
+
+	if _, err := BuildPrintBundle(PrintOptions{Root: "missing", OutFile: outFile}); err == nil {
+		t.Error("BuildPrintBundle() with an unknown root should fail")
+	}
+}
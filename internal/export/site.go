@@ -0,0 +1,232 @@
+package export
+
+import (
+	_ "embed"
+	"fmt"
+	"html"
+	"html/template"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"doclific/internal/core"
+)
+
+//go:embed site.css
+var siteCSS string
+
+var pageTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.PageTitle}} · {{.SiteTitle}}</title>
+<link rel="stylesheet" href="{{.Root}}style.css">
+</head>
+<body>
+<nav class="sidebar">
+<a class="site-title" href="{{.Root}}index.html">{{.SiteTitle}}</a>
+{{.Nav}}
+</nav>
+<main class="content">
+{{.Content}}
+</main>
+</body>
+</html>
+`))
+
+var indexTemplate = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.SiteTitle}}</title>
+{{if .FirstPage}}<meta http-equiv="refresh" content="0; url={{.FirstPage}}">{{end}}
+<link rel="stylesheet" href="style.css">
+</head>
+<body>
+<main class="content">
+<h1>{{.SiteTitle}}</h1>
+{{if .FirstPage}}<p><a href="{{.FirstPage}}">Open the documentation</a></p>{{else}}<p>No documentation yet.</p>{{end}}
+</main>
+</body>
+</html>
+`))
+
+// SiteOptions configures BuildSite
+type SiteOptions struct {
+	OutDir string
+	Title  string // defaults to the repository name
+}
+
+// SiteResult summarizes a BuildSite run
+type SiteResult struct {
+	OutDir string `json:"outDir"`
+	Pages  int    `json:"pages"`
+}
+
+// sitePage is a doc rendered to <OutDir>/<URLPath>/index.html
+type sitePage struct {
+	FilePath string // folder path relative to doclific
+	URLPath  string // slug path, falling back to folder names for docs without a slug
+	Doc      core.FolderStructure
+}
+
+// pageURL returns the link from a page at depth fromDepth to the page at urlPath
+func pageURL(fromDepth int, urlPath string) string {
+	return strings.Repeat("../", fromDepth) + urlPath + "/index.html"
+}
+
+// collectSitePages lists every doc in sidebar order with its URL path
+func collectSitePages(docs []core.FolderStructure) []sitePage {
+	var pages []sitePage
+	urlPaths := map[string]string{}
+
+	core.WalkDocs(docs, func(filePath string, doc core.FolderStructure) error {
+		segment := doc.Slug
+		if segment == "" {
+			segment = doc.Name
+		}
+
+		urlPath := segment
+		if parent := path.Dir(filePath); parent != "." {
+			urlPath = urlPaths[parent] + "/" + segment
+		}
+		urlPaths[filePath] = urlPath
+
+		pages = append(pages, sitePage{FilePath: filePath, URLPath: urlPath, Doc: doc})
+		return nil
+	})
+
+	return pages
+}
+
+// navHTML renders the sidebar tree, marking the current page as active
+func navHTML(docs []core.FolderStructure, parentPath string, urlPaths map[string]string, current string, depth int) string {
+	if len(docs) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("<ul>")
+	for _, doc := range docs {
+		filePath := doc.Name
+		if parentPath != "" {
+			filePath = parentPath + "/" + doc.Name
+		}
+
+		class := ""
+		if filePath == current {
+			class = ` class="active"`
+		}
+		fmt.Fprintf(&b, `<li><a href="%s"%s>%s</a>`, html.EscapeString(pageURL(depth, urlPaths[filePath])), class, html.EscapeString(doc.Title))
+		b.WriteString(navHTML(doc.Children, filePath, urlPaths, current, depth))
+		b.WriteString("</li>")
+	}
+	b.WriteString("</ul>")
+	return b.String()
+}
+
+// BuildSite renders the whole doc tree into a self-contained static website
+// CodebaseSnippets are resolved against the working directory and inlined as highlighted code
+func BuildSite(options SiteOptions) (*SiteResult, error) {
+	if options.OutDir == "" {
+		return nil, fmt.Errorf("output directory is required")
+	}
+	if options.Title == "" {
+		options.Title = defaultTitle()
+	}
+
+	docs, err := core.GetDocs()
+	if err != nil {
+		return nil, err
+	}
+
+	graph, err := core.BuildLinkGraph()
+	if err != nil {
+		return nil, err
+	}
+
+	pages := collectSitePages(docs)
+	urlPaths := map[string]string{}
+	for _, page := range pages {
+		urlPaths[page.FilePath] = page.URLPath
+	}
+
+	if err := os.MkdirAll(options.OutDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(options.OutDir, "style.css"), []byte(siteCSS), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write style.css: %w", err)
+	}
+
+	for _, page := range pages {
+		content, err := core.GetDoc(page.FilePath)
+		if err != nil {
+			return nil, err
+		}
+
+		depth := strings.Count(page.URLPath, "/") + 1
+		href := func(target string) (string, bool) {
+			for _, link := range graph.Links {
+				if link.Source == page.FilePath && link.Target == target && !link.Broken {
+					return pageURL(depth, urlPaths[link.Resolved]), true
+				}
+			}
+			return "", false
+		}
+
+		body, err := renderMDXToHTML(content, href)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render %s: %w", page.Doc.Title, err)
+		}
+
+		pageDir := filepath.Join(options.OutDir, filepath.FromSlash(page.URLPath))
+		if err := os.MkdirAll(pageDir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create directory: %w", err)
+		}
+
+		file, err := os.Create(filepath.Join(pageDir, "index.html"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create page for %s: %w", page.Doc.Title, err)
+		}
+		err = pageTemplate.Execute(file, map[string]interface{}{
+			"PageTitle": page.Doc.Title,
+			"SiteTitle": options.Title,
+			"Root":      strings.Repeat("../", depth),
+			"Nav":       template.HTML(navHTML(docs, "", urlPaths, page.FilePath, depth)),
+			"Content":   template.HTML(body),
+		})
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to write page for %s: %w", page.Doc.Title, err)
+		}
+	}
+
+	firstPage := ""
+	if len(pages) > 0 {
+		firstPage = pageURL(0, pages[0].URLPath)
+	}
+
+	file, err := os.Create(filepath.Join(options.OutDir, "index.html"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create index.html: %w", err)
+	}
+	defer file.Close()
+	if err := indexTemplate.Execute(file, map[string]interface{}{
+		"SiteTitle": options.Title,
+		"FirstPage": firstPage,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to write index.html: %w", err)
+	}
+
+	return &SiteResult{OutDir: options.OutDir, Pages: len(pages)}, nil
+}
+
+// defaultTitle returns the repository name, or a generic title outside a repository with a remote
+func defaultTitle() string {
+	if name, err := core.GetRepoName(); err == nil && name != "" {
+		return name
+	}
+	return "Documentation"
+}
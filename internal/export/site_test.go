@@ -0,0 +1,108 @@
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setupDocs creates files relative to a temporary working directory and changes into it
+func setupDocs(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	originalDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get current directory: %v", err)
+	}
+
+	tmpDir := t.TempDir()
+	for path, content := range files {
+		fullPath := filepath.Join(tmpDir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatalf("failed to create directory for %s: %v", path, err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", path, err)
+		}
+	}
+
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("failed to change directory: %v", err)
+	}
+	t.Cleanup(func() { os.Chdir(originalDir) })
+
+	return tmpDir
+}
+
+// testDocs is a small doc tree exercising every component type
+var testDocs = map[string]string{
+	"main.go":                   "package main\n\nfunc main() {\n\tprintln(\"hi\")\n}\n",
+	"doclific/arch/config.json": `{"title": "Architecture", "slug": "architecture", "order": 0}`,
+	"doclific/arch/content.mdx": "# Architecture\n\nSee the [backend](doc:backend-uuid#setup).\n\n" +
+		`<CodebaseSnippet filePath="main.go" lineStart="3" lineEnd="5"></CodebaseSnippet>` + "\n",
+	"doclific/arch/backend-uuid/config.json": `{"title": "Backend", "order": 0}`,
+	"doclific/arch/backend-uuid/content.mdx": "# Backend\n\n" +
+		`<ERD tables="[{&#x22;id&#x22;:&#x22;t1&#x22;,&#x22;type&#x22;:&#x22;tableNode&#x22;,&#x22;data&#x22;:{&#x22;name&#x22;:&#x22;users&#x22;,&#x22;columns&#x22;:[{&#x22;id&#x22;:&#x22;c1&#x22;,&#x22;name&#x22;:&#x22;id&#x22;,&#x22;type&#x22;:&#x22;uuid&#x22;,&#x22;nullable&#x22;:false,&#x22;primaryKey&#x22;:true}]},&#x22;position&#x22;:{&#x22;x&#x22;:0,&#x22;y&#x22;:0}}]" relationships="[]" />` + "\n\n" +
+		`<HttpRequest method="POST" url="https://api.example.com/users" headers="[{&#x22;key&#x22;:&#x22;Accept&#x22;,&#x22;value&#x22;:&#x22;application/json&#x22;,&#x22;enabled&#x22;:true}]" queryParams="[]" bodyType="json" bodyContent="{&#x22;name&#x22;:&#x22;Ada&#x22;}" formData="[]" auth="{&#x22;type&#x22;:&#x22;bearer&#x22;,&#x22;token&#x22;:&#x22;t&#x22;}"></HttpRequest>` + "\n",
+	"doclific/guide/config.json": `{"title": "Guide", "order": 1}`,
+	"doclific/guide/content.mdx": "# Guide\n",
+}
+
+func TestBuildSite(t *testing.T) {
+	tmpDir := setupDocs(t, testDocs)
+	outDir := filepath.Join(tmpDir, "dist")
+
+	result, err := BuildSite(SiteOptions{OutDir: outDir, Title: "Test Docs"})
+	if err != nil {
+		t.Fatalf("BuildSite() error = %v", err)
+	}
+	if result.Pages != 3 {
+		t.Errorf("BuildSite() built %d pages, want 3", result.Pages)
+	}
+
+	read := func(path string) string {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join(outDir, filepath.FromSlash(path)))
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", path, err)
+		}
+		return string(data)
+	}
+
+	if _, err := os.Stat(filepath.Join(outDir, "style.css")); err != nil {
+		t.Errorf("BuildSite() did not write style.css: %v", err)
+	}
+	if index := read("index.html"); !strings.Contains(index, "architecture/index.html") {
+		t.Errorf("index.html does not point at the first page:\n%s", index)
+	}
+
+	// Pages live under slug paths, falling back to folder names
+	arch := read("architecture/index.html")
+	if !strings.Contains(arch, `href="../style.css"`) {
+		t.Error("architecture page does not link the stylesheet relatively")
+	}
+	if !strings.Contains(arch, `href="../architecture/backend-uuid/index.html#setup"`) {
+		t.Errorf("architecture page did not rewrite the doc: link:\n%s", arch)
+	}
+	if !strings.Contains(arch, "main.go:3-5") || !strings.Contains(arch, "println") {
+		t.Error("architecture page did not inline the CodebaseSnippet")
+	}
+	if !strings.Contains(arch, `class="active">Architecture</a>`) {
+		t.Error("architecture page does not mark itself active in the sidebar")
+	}
+	if strings.Index(arch, ">Architecture</a>") > strings.Index(arch, ">Guide</a>") {
+		t.Error("sidebar is not in doc order")
+	}
+
+	backend := read("architecture/backend-uuid/index.html")
+	if !strings.Contains(backend, `<th colspan="3">users</th>`) || !strings.Contains(backend, "PK") {
+		t.Error("backend page did not render the ERD as a table")
+	}
+	if !strings.Contains(backend, `<span class="method method-post">POST</span> <code>https://api.example.com/users</code>`) {
+		t.Error("backend page did not render the HttpRequest")
+	}
+	if !strings.Contains(backend, "application/json") {
+		t.Error("backend page did not render the request headers")
+	}
+}
@@ -0,0 +1,204 @@
+package httpreq
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"doclific/internal/core"
+)
+
+// Assertions are the checks `doclific http run` makes against the response to a block
+type Assertions struct {
+	Status string         // expectStatus: codes or classes such as "200", "2xx" or "200,204"
+	JSON   map[string]any // expectJson: JSONPath expressions and the JSON value each must equal
+}
+
+// ParseAssertions reads the expectStatus and expectJson attributes of an <HttpRequest> block
+func ParseAssertions(component core.MDXComponent) (Assertions, error) {
+	assertions := Assertions{Status: strings.TrimSpace(component.Attributes["expectStatus"])}
+	if value := component.Attributes["expectJson"]; strings.TrimSpace(value) != "" {
+		if err := json.Unmarshal([]byte(value), &assertions.JSON); err != nil {
+			return assertions, fmt.Errorf("invalid expectJson: %w", err)
+		}
+	}
+	return assertions, nil
+}
+
+// Check returns a message for each assertion the response does not satisfy
+// Without expectStatus, any status below 400 passes
+func (a Assertions) Check(response *Response) []string {
+	failures := []string{}
+
+	if a.Status == "" {
+		if response.Status >= 400 {
+			failures = append(failures, fmt.Sprintf("status %d, want below 400", response.Status))
+		}
+	} else if ok, err := statusMatches(a.Status, response.Status); err != nil {
+		failures = append(failures, err.Error())
+	} else if !ok {
+		failures = append(failures, fmt.Sprintf("status %d, want %s", response.Status, a.Status))
+	}
+
+	if len(a.JSON) == 0 {
+		return failures
+	}
+	var body any
+	if response.Encoding != "" || json.Unmarshal([]byte(response.Body), &body) != nil {
+		return append(failures, "response body is not JSON")
+	}
+
+	paths := make([]string, 0, len(a.JSON))
+	for path := range a.JSON {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		actual, err := EvalJSONPath(body, path)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", path, err))
+			continue
+		}
+		if !reflect.DeepEqual(actual, a.JSON[path]) {
+			failures = append(failures, fmt.Sprintf("%s = %s, want %s", path, compactJSON(actual), compactJSON(a.JSON[path])))
+		}
+	}
+	return failures
+}
+
+// statusMatches reports whether status matches a comma-separated list of codes and classes such as 2xx
+func statusMatches(expected string, status int) (bool, error) {
+	for _, part := range strings.Split(expected, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if len(part) == 3 && strings.HasSuffix(part, "xx") && part[0] >= '1' && part[0] <= '5' {
+			if status/100 == int(part[0]-'0') {
+				return true, nil
+			}
+			continue
+		}
+		code, err := strconv.Atoi(part)
+		if err != nil {
+			return false, fmt.Errorf("invalid expectStatus %q", expected)
+		}
+		if code == status {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// compactJSON formats a decoded JSON value for a failure message
+func compactJSON(value any) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// EvalJSONPath returns the value at a JSONPath within decoded JSON
+// The supported subset is $ followed by .name, ['name'] and [index] steps (negative indexes count
+// from the end), plus .length on arrays, strings and objects without a "length" key
+func EvalJSONPath(value any, path string) (any, error) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(path), "$")
+	if !ok {
+		return nil, fmt.Errorf("path must start with $")
+	}
+
+	for rest != "" {
+		var key string
+		index, isIndex := 0, false
+
+		switch {
+		case strings.HasPrefix(rest, "."):
+			end := strings.IndexAny(rest[1:], ".[")
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			key, rest = rest[1:end+1], rest[end+1:]
+			if key == "" {
+				return nil, fmt.Errorf("empty name in path")
+			}
+		case strings.HasPrefix(rest, "['"), strings.HasPrefix(rest, `["`):
+			quote := rest[1:2]
+			end := strings.Index(rest[2:], quote+"]")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated [%s in path", quote)
+			}
+			key, rest = rest[2:end+2], rest[end+4:]
+		case strings.HasPrefix(rest, "["):
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated [ in path")
+			}
+			n, err := strconv.Atoi(strings.TrimSpace(rest[1:end]))
+			if err != nil {
+				return nil, fmt.Errorf("unsupported selector %s", rest[:end+1])
+			}
+			index, isIndex, rest = n, true, rest[end+1:]
+		default:
+			return nil, fmt.Errorf("unexpected %q in path", rest)
+		}
+
+		if isIndex {
+			array, ok := value.([]any)
+			if !ok {
+				return nil, fmt.Errorf("[%d] applied to %s", index, jsonKind(value))
+			}
+			if index < 0 {
+				index += len(array)
+			}
+			if index < 0 || index >= len(array) {
+				return nil, fmt.Errorf("index %d out of range (length %d)", index, len(array))
+			}
+			value = array[index]
+			continue
+		}
+
+		switch v := value.(type) {
+		case map[string]any:
+			next, ok := v[key]
+			if !ok && key == "length" {
+				next, ok = float64(len(v)), true
+			}
+			if !ok {
+				return nil, fmt.Errorf("no field %q", key)
+			}
+			value = next
+		case []any:
+			if key != "length" {
+				return nil, fmt.Errorf(".%s applied to array", key)
+			}
+			value = float64(len(v))
+		case string:
+			if key != "length" {
+				return nil, fmt.Errorf(".%s applied to string", key)
+			}
+			value = float64(len([]rune(v)))
+		default:
+			return nil, fmt.Errorf(".%s applied to %s", key, jsonKind(value))
+		}
+	}
+	return value, nil
+}
+
+// jsonKind names the type of a decoded JSON value for error messages
+func jsonKind(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	default:
+		return "object"
+	}
+}
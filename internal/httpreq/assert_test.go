@@ -0,0 +1,77 @@
+package httpreq
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"doclific/internal/core"
+)
+
+func TestEvalJSONPath(t *testing.T) {
+	var body any
+	json.Unmarshal([]byte(`{"user":{"name":"Ada","tags":["a","b","c"]},"items":[{"id":1},{"id":2}],"odd key":true}`), &body)
+
+	tests := map[string]any{
+		"$.user.name":        "Ada",
+		"$['user']['name']":  "Ada",
+		`$["odd key"]`:       true,
+		"$.items[1].id":      float64(2),
+		"$.items[-1].id":     float64(2),
+		"$.user.tags.length": float64(3),
+		"$.user.name.length": float64(3),
+	}
+	for path, want := range tests {
+		got, err := EvalJSONPath(body, path)
+		if err != nil {
+			t.Errorf("EvalJSONPath(%q) error = %v", path, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("EvalJSONPath(%q) = %v, want %v", path, got, want)
+		}
+	}
+
+	for _, path := range []string{"user.name", "$.missing", "$.items[5]", "$.items[*]", "$.user.name.first", "$['user"} {
+		if _, err := EvalJSONPath(body, path); err == nil {
+			t.Errorf("EvalJSONPath(%q) should fail", path)
+		}
+	}
+}
+
+func TestAssertions(t *testing.T) {
+	component := core.MDXComponent{Attributes: map[string]string{
+		"expectStatus": "2xx, 304",
+		"expectJson":   `{"$.id": 7, "$.user": {"name": "Ada"}, "$.tags.length": 2}`,
+	}}
+	assertions, err := ParseAssertions(component)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	response := &Response{Status: 201, Body: `{"id":7,"user":{"name":"Ada"},"tags":["x","y"]}`}
+	if failures := assertions.Check(response); len(failures) != 0 {
+		t.Errorf("Check() = %v, want no failures", failures)
+	}
+
+	response = &Response{Status: 404, Body: `{"id":8,"user":{"name":"Ada"}}`}
+	want := []string{
+		"status 404, want 2xx, 304",
+		`$.id = 8, want 7`,
+		`$.tags.length: no field "tags"`,
+	}
+	if failures := assertions.Check(response); !reflect.DeepEqual(failures, want) {
+		t.Errorf("Check() = %q, want %q", failures, want)
+	}
+
+	if failures := (Assertions{}).Check(&Response{Status: 500}); len(failures) != 1 {
+		t.Errorf("Check() without expectStatus should fail on 500, got %v", failures)
+	}
+	if failures := (Assertions{JSON: map[string]any{"$.a": 1.0}}).Check(&Response{Status: 200, Body: "<html>"}); len(failures) != 1 || !strings.Contains(failures[0], "not JSON") {
+		t.Errorf("Check() on a non-JSON body = %v", failures)
+	}
+	if _, err := ParseAssertions(core.MDXComponent{Attributes: map[string]string{"expectJson": "{"}}); err == nil {
+		t.Error("ParseAssertions() should reject malformed expectJson")
+	}
+}
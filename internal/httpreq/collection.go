@@ -0,0 +1,299 @@
+package httpreq
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"doclific/internal/core"
+)
+
+// CollectionFormats are the formats Collection.Export renders
+var CollectionFormats = []string{"postman", "insomnia", "curl"}
+
+// headingPattern matches a Markdown heading line
+var headingPattern = regexp.MustCompile(`^(#{1,6})\s+(.+?)\s*#*\s*$`)
+
+// CollectionRequest is an <HttpRequest> block in an exported collection
+type CollectionRequest struct {
+	Name    string // the heading above the block, or its method and URL
+	Line    int
+	Request Request
+}
+
+// CollectionFolder is a doc with <HttpRequest> blocks of its own or in the docs nested under it
+type CollectionFolder struct {
+	Title    string
+	FilePath string
+	Requests []CollectionRequest
+	Folders  []*CollectionFolder
+}
+
+// CollectionVariable is a variable exported with a collection
+type CollectionVariable struct {
+	Key    string
+	Value  string
+	Secret bool // secrets are exported by name only, so their values never leave the user's machine
+}
+
+// Collection is the <HttpRequest> blocks of a doc tree, grouped by doc hierarchy
+type Collection struct {
+	Name         string
+	Requests     []CollectionRequest // blocks of the exported doc itself
+	Folders      []*CollectionFolder
+	Environment  string               // environment the variables were taken from, if any
+	Variables    []CollectionVariable // sorted by key
+	Environments []Environment        // every environment, for formats that can switch between them
+}
+
+// LoadCollection collects the <HttpRequest> blocks of every doc, or of a doc and the docs nested under it
+// Variables are taken from the environment, or the active one when it is empty. Any other variable the
+// requests use is exported with an empty value to be filled in
+func LoadCollection(filePath, environment string) (*Collection, error) {
+	docs, err := core.GetDocs()
+	if err != nil {
+		return nil, err
+	}
+
+	collection := &Collection{}
+	if filePath == "" {
+		collection.Name = "Documentation"
+		if name, err := core.GetRepoName(); err == nil && name != "" {
+			collection.Name = name
+		}
+		for i := range docs {
+			folder, err := collectionFolder(docs[i], docs[i].Name)
+			if err != nil {
+				return nil, err
+			}
+			if folder != nil {
+				collection.Folders = append(collection.Folders, folder)
+			}
+		}
+	} else {
+		var root *CollectionFolder
+		found := false
+		err := core.WalkDocs(docs, func(docPath string, doc core.FolderStructure) error {
+			if docPath != filePath {
+				return nil
+			}
+			found = true
+			collection.Name = doc.Title
+			var err error
+			root, err = collectionFolder(doc, docPath)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			return nil, fmt.Errorf("doc %s not found", filePath)
+		}
+		if root != nil {
+			collection.Requests, collection.Folders = root.Requests, root.Folders
+		}
+	}
+
+	if err := collection.loadVariables(environment); err != nil {
+		return nil, err
+	}
+	return collection, nil
+}
+
+// collectionFolder reads the blocks of a doc and its children, returning nil when there are none
+func collectionFolder(doc core.FolderStructure, filePath string) (*CollectionFolder, error) {
+	content, err := core.GetDoc(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	folder := &CollectionFolder{Title: doc.Title, FilePath: filePath, Requests: docRequests(content, doc.Title)}
+	for _, child := range doc.Children {
+		childFolder, err := collectionFolder(child, filePath+"/"+child.Name)
+		if err != nil {
+			return nil, err
+		}
+		if childFolder != nil {
+			folder.Folders = append(folder.Folders, childFolder)
+		}
+	}
+
+	if len(folder.Requests) == 0 && len(folder.Folders) == 0 {
+		return nil, nil
+	}
+	return folder, nil
+}
+
+// docRequests parses the <HttpRequest> blocks of a doc and names them
+// A block is named after the outermost heading (below the doc's # title) between it and the block before it.
+// A doc's only block without such a heading is named after the doc; other blocks without a heading of
+// their own are named after their method and URL
+func docRequests(content, title string) []CollectionRequest {
+	lines := strings.Split(content, "\n")
+	components := core.ParseMDXComponents(content, core.ComponentHttpRequest)
+
+	headings := make([]string, len(components))
+	counts := map[string]int{}
+	previousLine := 0
+	for i, component := range components {
+		level := 0
+		for line := previousLine; line < component.Line-1 && line < len(lines); line++ {
+			match := headingPattern.FindStringSubmatch(lines[line])
+			if match == nil || len(match[1]) == 1 {
+				continue
+			}
+			if level == 0 || len(match[1]) < level {
+				level, headings[i] = len(match[1]), unescapeMDXText(match[2])
+			}
+		}
+		if headings[i] == "" && len(components) == 1 {
+			headings[i] = title
+		}
+		counts[headings[i]]++
+		previousLine = component.Line
+	}
+
+	requests := []CollectionRequest{}
+	for i, component := range components {
+		request := ParseComponent(component)
+		name := headings[i]
+		if name == "" || counts[name] > 1 {
+			name = strings.TrimSpace(request.Method + " " + request.URL)
+		}
+		requests = append(requests, CollectionRequest{Name: name, Line: component.Line, Request: request})
+	}
+	return requests
+}
+
+// unescapeMDXText removes the backslashes MDX needs before braces and angle brackets in text
+func unescapeMDXText(text string) string {
+	return strings.NewReplacer(`\{`, "{", `\}`, "}", `\<`, "<").Replace(text)
+}
+
+// WalkRequests calls fn for every request of the collection, in export order
+func (c *Collection) WalkRequests(fn func(request CollectionRequest)) {
+	var walk func(folders []*CollectionFolder)
+	walk = func(folders []*CollectionFolder) {
+		for _, folder := range folders {
+			for _, request := range folder.Requests {
+				fn(request)
+			}
+			walk(folder.Folders)
+		}
+	}
+	for _, request := range c.Requests {
+		fn(request)
+	}
+	walk(c.Folders)
+}
+
+// loadVariables fills in the variables of the environment and any others the requests use
+func (c *Collection) loadVariables(name string) error {
+	environments, err := ListEnvironments()
+	if err != nil {
+		return err
+	}
+	c.Environments = environments.Environments
+	if name == "" {
+		name = environments.Active
+	}
+
+	variables := map[string]CollectionVariable{}
+	c.WalkRequests(func(request CollectionRequest) {
+		request.Request.mapFields(func(value string) string {
+			for _, match := range variablePattern.FindAllStringSubmatch(value, -1) {
+				variables[match[1]] = CollectionVariable{Key: match[1]}
+			}
+			return value
+		})
+	})
+
+	if name != "" {
+		found := false
+		for _, environment := range environments.Environments {
+			if environment.Name != name {
+				continue
+			}
+			found = true
+			for key, value := range environment.Variables {
+				variables[key] = CollectionVariable{Key: key, Value: value}
+			}
+			for _, key := range environment.Secrets {
+				variables[key] = CollectionVariable{Key: key, Secret: true}
+			}
+		}
+		if !found {
+			return fmt.Errorf("%w: %s", ErrEnvironmentNotFound, name)
+		}
+		c.Environment = name
+	}
+
+	c.Variables = []CollectionVariable{}
+	for _, variable := range variables {
+		c.Variables = append(c.Variables, variable)
+	}
+	sort.Slice(c.Variables, func(i, j int) bool {
+		return c.Variables[i].Key < c.Variables[j].Key
+	})
+	return nil
+}
+
+// mapFields returns a copy of the request with fn applied to every field that may hold {{variables}}
+func (r Request) mapFields(fn func(string) string) Request {
+	mapPairs := func(pairs []KeyValue) []KeyValue {
+		var result []KeyValue
+		for _, pair := range pairs {
+			pair.Key, pair.Value = fn(pair.Key), fn(pair.Value)
+			result = append(result, pair)
+		}
+		return result
+	}
+
+	r.URL = fn(r.URL)
+	r.Headers = mapPairs(r.Headers)
+	r.QueryParams = mapPairs(r.QueryParams)
+	r.FormData = mapPairs(r.FormData)
+	r.BodyContent = fn(r.BodyContent)
+	r.Auth.Username = fn(r.Auth.Username)
+	r.Auth.Password = fn(r.Auth.Password)
+	r.Auth.Token = fn(r.Auth.Token)
+	r.Auth.APIKeyName = fn(r.Auth.APIKeyName)
+	r.Auth.APIKeyValue = fn(r.Auth.APIKeyValue)
+	return r
+}
+
+// replaceVariables rewrites every {{name}} placeholder in value with format(name)
+func replaceVariables(value string, format func(name string) string) string {
+	return rewriteVariables(value, func(text string) string { return text }, format)
+}
+
+// rewriteVariables rewrites the text around placeholders with literal and each {{name}} placeholder with format(name)
+func rewriteVariables(value string, literal, format func(string) string) string {
+	var b strings.Builder
+	last := 0
+	for _, match := range variablePattern.FindAllStringSubmatchIndex(value, -1) {
+		if match[0] > last {
+			b.WriteString(literal(value[last:match[0]]))
+		}
+		b.WriteString(format(value[match[2]:match[3]]))
+		last = match[1]
+	}
+	if last < len(value) {
+		b.WriteString(literal(value[last:]))
+	}
+	return b.String()
+}
+
+// Export renders the collection in one of CollectionFormats
+func (c *Collection) Export(format string) (string, error) {
+	switch format {
+	case "postman":
+		return c.Postman()
+	case "insomnia":
+		return c.Insomnia()
+	case "curl":
+		return c.CurlScript(), nil
+	}
+	return "", fmt.Errorf("unknown export format %q (use %s)", format, strings.Join(CollectionFormats, ", "))
+}
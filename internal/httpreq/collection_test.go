@@ -0,0 +1,218 @@
+package httpreq
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setupCollectionProject writes a doc tree with HttpRequest blocks and a staging environment with a secret
+func setupCollectionProject(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("HOME", t.TempDir())
+
+	files := map[string]string{
+		"doclific/api/config.json": `{"title": "API", "order": 0}`,
+		"doclific/api/content.mdx": `# API
+
+## List users
+
+### Parameters
+
+<HttpRequest method="GET" url="{{ baseUrl }}/users?sort=name" queryParams="[{&#x22;key&#x22;:&#x22;page&#x22;,&#x22;value&#x22;:&#x22;1 of {{pages}}&#x22;,&#x22;enabled&#x22;:true},{&#x22;key&#x22;:&#x22;debug&#x22;,&#x22;value&#x22;:&#x22;1&#x22;,&#x22;enabled&#x22;:false}]" auth="{&#x22;type&#x22;:&#x22;bearer&#x22;,&#x22;token&#x22;:&#x22;{{token}}&#x22;}" />
+
+## Create a user
+
+<HttpRequest method="POST" url="http://localhost:3000/users" bodyType="json" bodyContent="{&#x22;name&#x22;: &#x22;it's {{name}}&#x22;}" auth="{&#x22;type&#x22;:&#x22;apikey&#x22;,&#x22;apiKeyName&#x22;:&#x22;key&#x22;,&#x22;apiKeyValue&#x22;:&#x22;{{api-key}}&#x22;,&#x22;apiKeyLocation&#x22;:&#x22;query&#x22;}" />
+`,
+		"doclific/api/orders/config.json": `{"title": "Orders", "order": 0}`,
+		"doclific/api/orders/content.mdx": `<HttpRequest method="PUT" url="{{baseUrl}}/orders" bodyType="x-www-form-urlencoded" formData="[{&#x22;key&#x22;:&#x22;status&#x22;,&#x22;value&#x22;:&#x22;paid&#x22;,&#x22;enabled&#x22;:true}]" />
+`,
+		"doclific/api/empty/config.json":      `{"title": "Empty", "order": 1}`,
+		"doclific/api/empty/content.mdx":      "# Nothing to send\n",
+		"doclific/.environments/staging.json": `{"baseUrl": "https://staging.example.com"}`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wd, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	if err := SetSecret("staging", "token", "s3cret"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadCollection(t *testing.T) {
+	setupCollectionProject(t)
+
+	collection, err := LoadCollection("api", "staging")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if collection.Name != "API" || len(collection.Requests) != 2 || len(collection.Folders) != 1 {
+		t.Fatalf("LoadCollection(api) = %+v, want the doc's requests and only the folders with requests", collection)
+	}
+	if collection.Requests[0].Name != "List users" || collection.Requests[1].Name != "Create a user" {
+		t.Errorf("request names = %q, %q", collection.Requests[0].Name, collection.Requests[1].Name)
+	}
+	if folder := collection.Folders[0]; folder.Title != "Orders" || folder.FilePath != "api/orders" || folder.Requests[0].Name != "Orders" {
+		t.Errorf("Folders[0] = %+v, want a single block named after its doc", folder)
+	}
+
+	var variables []string
+	for _, variable := range collection.Variables {
+		variables = append(variables, variable.Key+"="+variable.Value)
+		if variable.Key == "token" && !variable.Secret {
+			t.Error("token should be exported as a secret")
+		}
+	}
+	if got := strings.Join(variables, " "); got != "api-key= baseUrl=https://staging.example.com name= pages= token=" {
+		t.Errorf("Variables = %s", got)
+	}
+
+	if collection, err := LoadCollection("", ""); err != nil || len(collection.Folders) != 1 || collection.Environment != "" {
+		t.Errorf("LoadCollection() = %+v, %v", collection, err)
+	}
+	if _, err := LoadCollection("nope", ""); err == nil {
+		t.Error("LoadCollection() should fail for an unknown doc")
+	}
+	if _, err := LoadCollection("api", "prod"); !errors.Is(err, ErrEnvironmentNotFound) {
+		t.Errorf("LoadCollection() with an unknown environment error = %v", err)
+	}
+}
+
+func TestPostman(t *testing.T) {
+	setupCollectionProject(t)
+	collection, err := LoadCollection("api", "staging")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := collection.Export("postman")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(output, "s3cret") {
+		t.Fatal("Postman export contains a secret value")
+	}
+	var exported postmanCollection
+	if err := json.Unmarshal([]byte(output), &exported); err != nil {
+		t.Fatal(err)
+	}
+	if exported.Info.Schema != postmanSchema || len(exported.Item) != 3 || exported.Item[2].Name != "Orders" || len(exported.Item[2].Item) != 1 {
+		t.Fatalf("Postman items = %+v", exported.Item)
+	}
+
+	list := exported.Item[0].Request
+	if list.URL.Raw != "{{baseUrl}}/users?sort=name&page=1 of {{pages}}" || strings.Join(list.URL.Host, ".") != "{{baseUrl}}" || strings.Join(list.URL.Path, "/") != "users" {
+		t.Errorf("url = %+v", list.URL)
+	}
+	if len(list.URL.Query) != 3 || list.URL.Query[0].Key != "sort" || !list.URL.Query[2].Disabled {
+		t.Errorf("query = %+v", list.URL.Query)
+	}
+	if list.Auth.Type != "bearer" || list.Auth.Bearer[0].Value != "{{token}}" || list.Body != nil {
+		t.Errorf("auth = %+v, body = %+v", list.Auth, list.Body)
+	}
+
+	create := exported.Item[1].Request
+	if create.URL.Protocol != "http" || create.URL.Port != "3000" || create.URL.Host[0] != "localhost" {
+		t.Errorf("url = %+v", create.URL)
+	}
+	if create.Body.Mode != "raw" || create.Body.Raw != `{"name": "it's {{name}}"}` || create.Auth.APIKey[2].Value != "query" {
+		t.Errorf("body = %+v, auth = %+v", create.Body, create.Auth)
+	}
+	if order := exported.Item[2].Item[0].Request; order.Body.Mode != "urlencoded" || order.Body.URLEncoded[0].Key != "status" || order.Auth.Type != "noauth" {
+		t.Errorf("orders request = %+v", order)
+	}
+
+	if len(exported.Variable) != 5 || exported.Variable[1].Value != "https://staging.example.com" || exported.Variable[4].Description == "" {
+		t.Errorf("variables = %+v", exported.Variable)
+	}
+}
+
+func TestInsomnia(t *testing.T) {
+	setupCollectionProject(t)
+	collection, err := LoadCollection("api", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := collection.Export("insomnia")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(output, "s3cret") {
+		t.Fatal("Insomnia export contains a secret value")
+	}
+	var exported insomniaExport
+	if err := json.Unmarshal([]byte(output), &exported); err != nil {
+		t.Fatal(err)
+	}
+
+	byName := map[string]insomniaResource{}
+	for _, resource := range exported.Resources {
+		byName[resource.Type+" "+resource.Name] = resource
+	}
+	workspace, base, staging := byName["workspace API"], byName["environment Base Environment"], byName["environment staging"]
+	if workspace.Type != "workspace" || *base.ParentID != workspace.ID || *staging.ParentID != base.ID {
+		t.Fatalf("resources = %+v", exported.Resources)
+	}
+	if staging.Data["baseUrl"] != "https://staging.example.com" || staging.Data["token"] != "" {
+		t.Errorf("staging environment = %+v", staging.Data)
+	}
+
+	list := byName["request List users"]
+	if *list.ParentID != workspace.ID || list.URL != "{{ _.baseUrl }}/users?sort=name" || list.Authentication.Token != "{{ _.token }}" {
+		t.Errorf("List users = %+v", list)
+	}
+	create := byName["request Create a user"]
+	if create.Authentication.Value != "{{ _['api-key'] }}" || create.Authentication.AddTo != "queryParams" || create.Body.MimeType != "application/json" {
+		t.Errorf("Create a user = %+v", create)
+	}
+	orders := byName["request_group Orders"]
+	if *orders.ParentID != workspace.ID || *byName["request Orders"].ParentID != orders.ID {
+		t.Errorf("Orders folder = %+v", orders)
+	}
+
+	if _, err := collection.Export("har"); err == nil {
+		t.Error("Export() should reject unknown formats")
+	}
+}
+
+func TestCurlScript(t *testing.T) {
+	setupCollectionProject(t)
+	collection, err := LoadCollection("api", "staging")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	script := collection.CurlScript()
+	for _, want := range []string{
+		"#!/bin/sh\n",
+		"api_key=${api_key-''}\nbaseUrl=${baseUrl-'https://staging.example.com'}\n",
+		"token=${token-}  # secret\n",
+		"\n# List users\ncurl -X GET \"${baseUrl}\"'/users?sort=name&page=1+of+'\"${pages}\" \\\n  -H 'Authorization: Bearer '\"${token}\"\necho\n",
+		`--data '{"name": "it'\''s '"${name}"'"}'`,
+		"curl -X POST 'http://localhost:3000/users?key='\"${api_key}\"",
+		"\n# == Orders (api/orders) ==\n\n# Orders\n",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("CurlScript() missing %q:\n%s", want, script)
+		}
+	}
+	if strings.Contains(script, "s3cret") {
+		t.Error("CurlScript() contains a secret value")
+	}
+}
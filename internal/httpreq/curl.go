@@ -0,0 +1,123 @@
+package httpreq
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// shellQuote quotes a value for a POSIX shell using single quotes
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// Curl renders the request as a multi-line curl invocation
+func (r Request) Curl() string {
+	return r.curl(shellQuote, url.QueryEscape)
+}
+
+// curl renders the invocation, quoting each argument with quote and escaping query parameters with escape
+func (r Request) curl(quote, escape func(string) string) string {
+	args := []string{"curl -X " + r.Method + " " + quote(r.fullURL(escape))}
+
+	for _, header := range Enabled(r.Headers) {
+		args = append(args, "-H "+quote(header.Key+": "+header.Value))
+	}
+
+	switch r.Auth.Type {
+	case "basic":
+		args = append(args, "-u "+quote(r.Auth.Username+":"+r.Auth.Password))
+	case "bearer":
+		args = append(args, "-H "+quote("Authorization: Bearer "+r.Auth.Token))
+	case "apikey":
+		if r.Auth.APIKeyLocation != "query" && r.Auth.APIKeyName != "" {
+			args = append(args, "-H "+quote(r.Auth.APIKeyName+": "+r.Auth.APIKeyValue))
+		}
+	}
+
+	switch r.BodyType {
+	case "json":
+		args = append(args, "-H "+quote("Content-Type: application/json"))
+		if r.BodyContent != "" {
+			args = append(args, "--data "+quote(r.BodyContent))
+		}
+	case "raw":
+		if r.BodyContent != "" {
+			args = append(args, "--data-binary "+quote(r.BodyContent))
+		}
+	case "form-data":
+		for _, field := range Enabled(r.FormData) {
+			args = append(args, "-F "+quote(field.Key+"="+field.Value))
+		}
+	case "x-www-form-urlencoded":
+		for _, field := range Enabled(r.FormData) {
+			args = append(args, "--data-urlencode "+quote(field.Key+"="+field.Value))
+		}
+	}
+
+	return strings.Join(args, " \\\n  ")
+}
+
+// shellUnsafe matches characters variable names may contain but shell variable names may not
+var shellUnsafe = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// shellVariable returns the shell variable a {{name}} placeholder is exported as
+func shellVariable(name string) string {
+	return shellUnsafe.ReplaceAllString(name, "_")
+}
+
+// shellQuoteVariables quotes a value like shellQuote, turning {{name}} placeholders into "${name}" expansions
+func shellQuoteVariables(value string) string {
+	quoted := rewriteVariables(value, shellQuote, func(name string) string {
+		return `"${` + shellVariable(name) + `}"`
+	})
+	if quoted == "" {
+		return "''"
+	}
+	return quoted
+}
+
+// queryEscapeVariables escapes a query key or value, leaving {{name}} placeholders for the shell to expand
+func queryEscapeVariables(value string) string {
+	return rewriteVariables(value, url.QueryEscape, func(name string) string {
+		return "{{" + name + "}}"
+	})
+}
+
+// CurlScript renders the collection as a shell script with a curl command per request
+// Variables become shell variables that default to their exported value and can be overridden from the environment
+func (c *Collection) CurlScript() string {
+	var b strings.Builder
+	b.WriteString("#!/bin/sh\n")
+	fmt.Fprintf(&b, "# %s: HttpRequest blocks exported by doclific", c.Name)
+	if c.Environment != "" {
+		fmt.Fprintf(&b, " with the %s environment", c.Environment)
+	}
+	b.WriteString("\n# Variables can be overridden when running the script, such as: token=abc sh requests.sh\n")
+
+	if len(c.Variables) > 0 {
+		b.WriteString("\n")
+		for _, variable := range c.Variables {
+			name := shellVariable(variable.Key)
+			if variable.Secret {
+				fmt.Fprintf(&b, "%s=${%s-}  # secret\n", name, name)
+			} else {
+				fmt.Fprintf(&b, "%s=${%s-%s}\n", name, name, shellQuote(variable.Value))
+			}
+		}
+	}
+
+	var writeRequests func(requests []CollectionRequest, folders []*CollectionFolder)
+	writeRequests = func(requests []CollectionRequest, folders []*CollectionFolder) {
+		for _, request := range requests {
+			fmt.Fprintf(&b, "\n# %s\n%s\necho\n", request.Name, request.Request.curl(shellQuoteVariables, queryEscapeVariables))
+		}
+		for _, folder := range folders {
+			fmt.Fprintf(&b, "\n# == %s (%s) ==\n", folder.Title, folder.FilePath)
+			writeRequests(folder.Requests, folder.Folders)
+		}
+	}
+	writeRequests(c.Requests, c.Folders)
+	return b.String()
+}
@@ -0,0 +1,327 @@
+package httpreq
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"doclific/internal/config"
+)
+
+const (
+	// EnvironmentsDirName is the folder inside doclific/ holding one <name>.json of shared variables per environment
+	EnvironmentsDirName = ".environments"
+
+	// secretsFileName is the file in the user config dir holding secrets and the active environment of each project
+	secretsFileName = "environments.json"
+)
+
+// ErrEnvironmentNotFound is returned for an environment with neither shared variables nor secrets
+var ErrEnvironmentNotFound = errors.New("environment not found")
+
+// variableNamePattern matches valid variable names, and variablePattern {{name}} placeholders with optional spaces
+var (
+	variableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+	variablePattern     = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_.-]*)\s*\}\}`)
+)
+
+// Environment is a named set of variables, such as local, staging or prod
+type Environment struct {
+	Name      string            `json:"name"`
+	Variables map[string]string `json:"variables"` // shared values committed to the repository
+	Secrets   []string          `json:"secrets"`   // names of the secrets set in the user config dir; values are never listed
+}
+
+// Environments lists the environments of the current project and which one requests resolve against
+type Environments struct {
+	Active       string        `json:"active"`
+	Environments []Environment `json:"environments"`
+}
+
+// projectSecrets is the per-user state of one project's environments
+type projectSecrets struct {
+	Active  string                       `json:"active,omitempty"`
+	Secrets map[string]map[string]string `json:"secrets,omitempty"` // environment name -> variable -> value
+}
+
+// validateEnvironmentName rejects names that cannot be stored as a file in the environments folder
+func validateEnvironmentName(name string) error {
+	if name == "" || name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return fmt.Errorf("invalid environment name %q", name)
+	}
+	return nil
+}
+
+// environmentsDir returns the doclific/.environments folder of the current project
+func environmentsDir() (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current working directory: %w", err)
+	}
+	return filepath.Join(cwd, "doclific", EnvironmentsDirName), nil
+}
+
+// readSharedVariables reads the committed variables of every environment, keyed by environment name
+func readSharedVariables() (map[string]map[string]string, error) {
+	dir, err := environmentsDir()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]map[string]string{}, nil
+		}
+		return nil, fmt.Errorf("failed to read environments: %w", err)
+	}
+
+	environments := map[string]map[string]string{}
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".json")
+		if entry.IsDir() || !ok || validateEnvironmentName(name) != nil {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read environment %s: %w", name, err)
+		}
+		variables := map[string]string{}
+		if err := json.Unmarshal(data, &variables); err != nil {
+			return nil, fmt.Errorf("failed to parse environment %s: %w", name, err)
+		}
+		environments[name] = variables
+	}
+	return environments, nil
+}
+
+// loadSecrets reads the user's secrets file and returns it along with the current project's entry
+// Projects are keyed by their directory so environments with the same name in different repositories stay separate
+func loadSecrets() (map[string]*projectSecrets, string, error) {
+	project, err := os.Getwd()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get current working directory: %w", err)
+	}
+
+	projects := map[string]*projectSecrets{}
+	data, err := config.ReadUserFile(secretsFileName)
+	if err != nil {
+		return nil, "", err
+	}
+	if data != nil {
+		if err := json.Unmarshal(data, &projects); err != nil {
+			return nil, "", fmt.Errorf("failed to parse secrets: %w", err)
+		}
+	}
+	if projects[project] == nil {
+		projects[project] = &projectSecrets{}
+	}
+	return projects, project, nil
+}
+
+// saveSecrets writes the secrets file, readable only by the user
+func saveSecrets(projects map[string]*projectSecrets) error {
+	for key, project := range projects {
+		if project.Active == "" && len(project.Secrets) == 0 {
+			delete(projects, key)
+		}
+	}
+	data, err := json.MarshalIndent(projects, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal secrets: %w", err)
+	}
+	return config.WriteUserFile(secretsFileName, data, 0600)
+}
+
+// ListEnvironments returns the project's environments sorted by name
+// An environment exists if it has a file in doclific/.environments or secrets in the user config dir
+func ListEnvironments() (*Environments, error) {
+	shared, err := readSharedVariables()
+	if err != nil {
+		return nil, err
+	}
+	projects, project, err := loadSecrets()
+	if err != nil {
+		return nil, err
+	}
+	secrets := projects[project].Secrets
+
+	names := map[string]bool{}
+	for name := range shared {
+		names[name] = true
+	}
+	for name := range secrets {
+		names[name] = true
+	}
+
+	result := &Environments{Active: projects[project].Active, Environments: []Environment{}}
+	for name := range names {
+		environment := Environment{Name: name, Variables: shared[name], Secrets: []string{}}
+		if environment.Variables == nil {
+			environment.Variables = map[string]string{}
+		}
+		for key := range secrets[name] {
+			environment.Secrets = append(environment.Secrets, key)
+		}
+		sort.Strings(environment.Secrets)
+		result.Environments = append(result.Environments, environment)
+	}
+	sort.Slice(result.Environments, func(i, j int) bool {
+		return result.Environments[i].Name < result.Environments[j].Name
+	})
+
+	// A selection whose files have since been removed resolves nothing
+	if !names[result.Active] {
+		result.Active = ""
+	}
+	return result, nil
+}
+
+// SetActiveEnvironment selects the environment requests resolve against; an empty name clears the selection
+func SetActiveEnvironment(name string) error {
+	if name != "" {
+		environments, err := ListEnvironments()
+		if err != nil {
+			return err
+		}
+		found := false
+		for _, environment := range environments.Environments {
+			found = found || environment.Name == name
+		}
+		if !found {
+			return fmt.Errorf("%w: %s", ErrEnvironmentNotFound, name)
+		}
+	}
+
+	projects, project, err := loadSecrets()
+	if err != nil {
+		return err
+	}
+	projects[project].Active = name
+	return saveSecrets(projects)
+}
+
+// SetSecret stores a secret variable for an environment outside the repository; an empty value removes it
+func SetSecret(environment, key, value string) error {
+	if err := validateEnvironmentName(environment); err != nil {
+		return err
+	}
+	if !variableNamePattern.MatchString(key) {
+		return fmt.Errorf("invalid variable name %q", key)
+	}
+
+	projects, project, err := loadSecrets()
+	if err != nil {
+		return err
+	}
+	secrets := projects[project].Secrets
+	if secrets == nil {
+		secrets = map[string]map[string]string{}
+		projects[project].Secrets = secrets
+	}
+	if value == "" {
+		delete(secrets[environment], key)
+		if len(secrets[environment]) == 0 {
+			delete(secrets, environment)
+		}
+	} else {
+		if secrets[environment] == nil {
+			secrets[environment] = map[string]string{}
+		}
+		secrets[environment][key] = value
+	}
+	return saveSecrets(projects)
+}
+
+// EnvironmentVariables returns an environment's shared variables overlaid with the user's secrets
+func EnvironmentVariables(name string) (map[string]string, error) {
+	if err := validateEnvironmentName(name); err != nil {
+		return nil, err
+	}
+	shared, err := readSharedVariables()
+	if err != nil {
+		return nil, err
+	}
+	projects, project, err := loadSecrets()
+	if err != nil {
+		return nil, err
+	}
+	secrets := projects[project].Secrets[name]
+	if shared[name] == nil && secrets == nil {
+		return nil, fmt.Errorf("%w: %s", ErrEnvironmentNotFound, name)
+	}
+
+	variables := map[string]string{}
+	for key, value := range shared[name] {
+		variables[key] = value
+	}
+	for key, value := range secrets {
+		variables[key] = value
+	}
+	return variables, nil
+}
+
+// ActiveVariables returns the variables of the active environment, or nil when none is selected
+func ActiveVariables() (map[string]string, error) {
+	environments, err := ListEnvironments()
+	if err != nil {
+		return nil, err
+	}
+	if environments.Active == "" {
+		return nil, nil
+	}
+	return EnvironmentVariables(environments.Active)
+}
+
+// Resolve returns a copy of the request with {{name}} placeholders replaced by their values
+// Placeholders are replaced in the URL, headers, query parameters, body, form data and auth
+// An error wrapping ErrInvalidRequest lists any placeholders without a value
+func (r Request) Resolve(variables map[string]string) (Request, error) {
+	missing := map[string]bool{}
+	substitute := func(value string) string {
+		return variablePattern.ReplaceAllStringFunc(value, func(placeholder string) string {
+			name := variablePattern.FindStringSubmatch(placeholder)[1]
+			if value, ok := variables[name]; ok {
+				return value
+			}
+			missing[name] = true
+			return placeholder
+		})
+	}
+	substitutePairs := func(pairs []KeyValue) []KeyValue {
+		var result []KeyValue
+		for _, pair := range pairs {
+			if pair.Enabled {
+				pair.Key, pair.Value = substitute(pair.Key), substitute(pair.Value)
+			}
+			result = append(result, pair)
+		}
+		return result
+	}
+
+	r.URL = substitute(r.URL)
+	r.Headers = substitutePairs(r.Headers)
+	r.QueryParams = substitutePairs(r.QueryParams)
+	r.FormData = substitutePairs(r.FormData)
+	r.BodyContent = substitute(r.BodyContent)
+	r.Auth.Username = substitute(r.Auth.Username)
+	r.Auth.Password = substitute(r.Auth.Password)
+	r.Auth.Token = substitute(r.Auth.Token)
+	r.Auth.APIKeyName = substitute(r.Auth.APIKeyName)
+	r.Auth.APIKeyValue = substitute(r.Auth.APIKeyValue)
+
+	if len(missing) > 0 {
+		names := make([]string, 0, len(missing))
+		for name := range missing {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return r, fmt.Errorf("%w: undefined variables %s (select an environment that defines them)", ErrInvalidRequest, strings.Join(names, ", "))
+	}
+	return r, nil
+}
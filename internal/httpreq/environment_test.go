@@ -0,0 +1,112 @@
+package httpreq
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	request := Request{
+		URL:         "{{baseUrl}}/users/{{ userId }}",
+		Headers:     []KeyValue{{Key: "X-Tenant", Value: "{{tenant}}", Enabled: true}, {Key: "X-Off", Value: "{{unset}}"}},
+		BodyContent: `{"id":"{{userId}}","literal":"{{ not a variable }}"}`,
+		Auth:        Auth{Type: "bearer", Token: "{{token}}"},
+	}
+	variables := map[string]string{"baseUrl": "http://localhost:3000", "userId": "42", "tenant": "acme", "token": "secret"}
+
+	resolved, err := request.Resolve(variables)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if resolved.URL != "http://localhost:3000/users/42" || resolved.Auth.Token != "secret" || resolved.Headers[0].Value != "acme" {
+		t.Errorf("Resolve() = %+v", resolved)
+	}
+	if resolved.Headers[1].Value != "{{unset}}" {
+		t.Errorf("Resolve() should leave disabled headers alone, got %q", resolved.Headers[1].Value)
+	}
+	if resolved.BodyContent != `{"id":"42","literal":"{{ not a variable }}"}` {
+		t.Errorf("Resolve() body = %s", resolved.BodyContent)
+	}
+	if request.URL != "{{baseUrl}}/users/{{ userId }}" {
+		t.Error("Resolve() modified the original request")
+	}
+
+	_, err = request.Resolve(map[string]string{"baseUrl": "http://localhost"})
+	if !errors.Is(err, ErrInvalidRequest) || err.Error() != "invalid request: undefined variables tenant, token, userId (select an environment that defines them)" {
+		t.Errorf("Resolve() with missing variables error = %v", err)
+	}
+}
+
+func TestEnvironments(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HOME", filepath.Join(dir, "home"))
+	project := filepath.Join(dir, "project")
+	envDir := filepath.Join(project, "doclific", EnvironmentsDirName)
+	if err := os.MkdirAll(envDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(envDir, "local.json"), []byte(`{"baseUrl":"http://localhost:3000","token":"dev"}`), 0644)
+	os.WriteFile(filepath.Join(envDir, "staging.json"), []byte(`{"baseUrl":"https://staging.example.com"}`), 0644)
+	// A secrets file left world-readable is tightened on the next write
+	secretsDir := filepath.Join(dir, "home", ".config", "doclific")
+	if err := os.MkdirAll(secretsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(secretsDir, secretsFileName), []byte(`{}`), 0644)
+	wd, _ := os.Getwd()
+	if err := os.Chdir(project); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	if variables, err := ActiveVariables(); err != nil || variables != nil {
+		t.Fatalf("ActiveVariables() without a selection = %v, %v", variables, err)
+	}
+	if err := SetActiveEnvironment("prod"); !errors.Is(err, ErrEnvironmentNotFound) {
+		t.Errorf("SetActiveEnvironment(prod) error = %v", err)
+	}
+	if err := SetSecret("staging", "token", "s3cret"); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetActiveEnvironment("staging"); err != nil {
+		t.Fatal(err)
+	}
+
+	environments, err := ListEnvironments()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if environments.Active != "staging" || len(environments.Environments) != 2 {
+		t.Fatalf("ListEnvironments() = %+v", environments)
+	}
+	staging := environments.Environments[1]
+	if staging.Name != "staging" || len(staging.Secrets) != 1 || staging.Secrets[0] != "token" || staging.Variables["token"] != "" {
+		t.Errorf("ListEnvironments() staging = %+v, want the secret listed by name only", staging)
+	}
+
+	variables, err := ActiveVariables()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if variables["baseUrl"] != "https://staging.example.com" || variables["token"] != "s3cret" {
+		t.Errorf("ActiveVariables() = %v", variables)
+	}
+
+	// Secrets stay out of the repository and are private to the user
+	info, err := os.Stat(filepath.Join(dir, "home", ".config", "doclific", secretsFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("secrets file mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	if err := SetSecret("staging", "token", ""); err != nil {
+		t.Fatal(err)
+	}
+	if variables, _ := EnvironmentVariables("staging"); variables["token"] != "" {
+		t.Errorf("SetSecret() with an empty value should remove the secret, got %v", variables)
+	}
+}
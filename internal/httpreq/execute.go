@@ -0,0 +1,294 @@
+package httpreq
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"doclific/internal/config"
+)
+
+// Limits applied to proxied requests
+const (
+	DefaultTimeout = 30 * time.Second
+	MaxBodySize    = 10 << 20 // response bytes kept; the rest is discarded and Truncated is set
+	maxRedirects   = 10
+)
+
+// DefaultAllowedHosts are the hosts requests may always be sent to: services on the dev machine
+var DefaultAllowedHosts = []string{"localhost", "*.localhost", "127.0.0.1", "::1"}
+
+// ErrInvalidRequest is returned for requests that cannot be sent, such as a malformed URL
+var ErrInvalidRequest = errors.New("invalid request")
+
+// ErrHostNotAllowed is returned when a request or one of its redirects targets a host outside the allow-list
+var ErrHostNotAllowed = errors.New("host is not in the allow-list")
+
+// Timing breaks down how long a request took, in milliseconds
+// Phases that did not happen (such as DNS for an IP address or TLS for http) are 0
+type Timing struct {
+	DNS       int64 `json:"dns"`
+	Connect   int64 `json:"connect"`
+	TLS       int64 `json:"tls"`
+	FirstByte int64 `json:"firstByte"` // from sending the request to the first response byte
+	Total     int64 `json:"total"`
+}
+
+// Response is the result of executing a Request, shaped like the editor's HttpResponse
+type Response struct {
+	Status     int               `json:"status"`
+	StatusText string            `json:"statusText"`
+	Headers    map[string]string `json:"headers"`
+	Body       string            `json:"body"`
+	Encoding   string            `json:"encoding,omitempty"` // "base64" when the body is not valid UTF-8
+	Truncated  bool              `json:"truncated,omitempty"`
+	Time       int64             `json:"time"` // total milliseconds
+	Size       int64             `json:"size"` // bytes received, before truncation
+	Cookies    []string          `json:"cookies"`
+	Timing     Timing            `json:"timing"`
+}
+
+// HostAllowed reports whether host matches one of the patterns
+// Patterns are host names or IP addresses, "*.example.com" for any subdomain, or "*" for any host
+func HostAllowed(host string, patterns []string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		switch {
+		case pattern == "":
+			continue
+		case pattern == "*":
+			return true
+		case strings.HasPrefix(pattern, "*."):
+			if strings.HasSuffix(host, pattern[1:]) {
+				return true
+			}
+		case host == strings.Trim(pattern, "[]"):
+			return true
+		}
+	}
+	return false
+}
+
+// Executor sends Requests on behalf of the editor, which cannot reach most APIs from the browser
+// because of CORS. Only hosts in AllowedHosts can be reached, including through redirects
+type Executor struct {
+	AllowedHosts []string
+	Timeout      time.Duration
+	Variables    map[string]string // values for {{name}} placeholders, usually from the active environment
+}
+
+// checkURL rejects anything but http(s) URLs to allowed hosts
+func (e *Executor) checkURL(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%w: unsupported URL scheme %q", ErrInvalidRequest, u.Scheme)
+	}
+	if !HostAllowed(u.Hostname(), e.AllowedHosts) {
+		return fmt.Errorf("%w: %s (allow it with: %s)", ErrHostNotAllowed, u.Hostname(), e.allowCommand(u.Hostname()))
+	}
+	return nil
+}
+
+// allowCommand returns the command adding host to the configured HTTP_ALLOWED_HOSTS, keeping the hosts already there
+func (e *Executor) allowCommand(host string) string {
+	var hosts []string
+	for _, allowed := range e.AllowedHosts {
+		if !slices.Contains(DefaultAllowedHosts, allowed) {
+			hosts = append(hosts, allowed)
+		}
+	}
+	return fmt.Sprintf(`doclific set HTTP_ALLOWED_HOSTS "%s"`, strings.Join(append(hosts, host), ", "))
+}
+
+// newHTTPRequest builds the outgoing request the same way the editor does in the browser:
+// enabled headers and query parameters, auth and a body for methods other than GET and HEAD
+func newHTTPRequest(ctx context.Context, request Request) (*http.Request, error) {
+	var body io.Reader
+	contentType := ""
+	if request.Method != http.MethodGet && request.Method != http.MethodHead {
+		switch request.BodyType {
+		case "json":
+			body, contentType = strings.NewReader(request.BodyContent), "application/json"
+		case "raw":
+			body = strings.NewReader(request.BodyContent)
+		case "x-www-form-urlencoded":
+			form := url.Values{}
+			for _, field := range Enabled(request.FormData) {
+				form.Add(field.Key, field.Value)
+			}
+			body, contentType = strings.NewReader(form.Encode()), "application/x-www-form-urlencoded"
+		case "form-data":
+			var buf bytes.Buffer
+			writer := multipart.NewWriter(&buf)
+			for _, field := range Enabled(request.FormData) {
+				if err := writer.WriteField(field.Key, field.Value); err != nil {
+					return nil, fmt.Errorf("%w: %w", ErrInvalidRequest, err)
+				}
+			}
+			if err := writer.Close(); err != nil {
+				return nil, fmt.Errorf("failed to encode form data: %w", err)
+			}
+			body, contentType = &buf, writer.FormDataContentType()
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, request.Method, request.FullURL(), body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidRequest, err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	for _, header := range Enabled(request.Headers) {
+		req.Header.Set(header.Key, header.Value)
+	}
+
+	switch request.Auth.Type {
+	case "basic":
+		if request.Auth.Username != "" {
+			req.SetBasicAuth(request.Auth.Username, request.Auth.Password)
+		}
+	case "bearer":
+		if request.Auth.Token != "" {
+			req.Header.Set("Authorization", "Bearer "+request.Auth.Token)
+		}
+	case "apikey":
+		if request.Auth.APIKeyLocation != "query" && request.Auth.APIKeyName != "" {
+			req.Header.Set(request.Auth.APIKeyName, request.Auth.APIKeyValue)
+		}
+	}
+	return req, nil
+}
+
+// Execute sends the request and reads the response
+// Errors are returned for requests that could not be sent or got no response; HTTP error statuses are not errors
+func (e *Executor) Execute(ctx context.Context, request Request) (*Response, error) {
+	request.setDefaults()
+	request, err := request.Resolve(e.Variables)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := e.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var timing Timing
+	var dnsStart, connectStart, tlsStart, wroteRequest time.Time
+	start := time.Now()
+	trace := &httptrace.ClientTrace{
+		DNSStart:          func(httptrace.DNSStartInfo) { dnsStart = time.Now() },
+		DNSDone:           func(httptrace.DNSDoneInfo) { timing.DNS = time.Since(dnsStart).Milliseconds() },
+		ConnectStart:      func(string, string) { connectStart = time.Now() },
+		ConnectDone:       func(string, string, error) { timing.Connect = time.Since(connectStart).Milliseconds() },
+		TLSHandshakeStart: func() { tlsStart = time.Now() },
+		TLSHandshakeDone:  func(tls.ConnectionState, error) { timing.TLS = time.Since(tlsStart).Milliseconds() },
+		WroteRequest:      func(httptrace.WroteRequestInfo) { wroteRequest = time.Now() },
+		GotFirstResponseByte: func() {
+			if !wroteRequest.IsZero() {
+				timing.FirstByte = time.Since(wroteRequest).Milliseconds()
+			}
+		},
+	}
+
+	req, err := newHTTPRequest(httptrace.WithClientTrace(ctx, trace), request)
+	if err != nil {
+		return nil, err
+	}
+	if err := e.checkURL(req.URL); err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{
+		CheckRedirect: func(next *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxRedirects)
+			}
+			return e.checkURL(next.URL)
+		},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		if errors.Is(err, ErrHostNotAllowed) || errors.Is(err, ErrInvalidRequest) {
+			return nil, err
+		}
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, fmt.Errorf("request timed out after %s", timeout)
+		}
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, MaxBodySize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	size := int64(len(body))
+	truncated := false
+	if size == MaxBodySize {
+		rest, _ := io.Copy(io.Discard, resp.Body)
+		size += rest
+		truncated = rest > 0
+	}
+	timing.Total = time.Since(start).Milliseconds()
+
+	response := newResponse(resp, body, size, truncated)
+	response.Time = timing.Total
+	response.Timing = timing
+	return response, nil
+}
+
+// newResponse converts a received response and its body (read up to MaxBodySize) into a Response
+func newResponse(resp *http.Response, body []byte, size int64, truncated bool) *Response {
+	response := &Response{
+		Status:     resp.StatusCode,
+		StatusText: strings.TrimSpace(strings.TrimPrefix(resp.Status, fmt.Sprint(resp.StatusCode))),
+		Headers:    map[string]string{},
+		Body:       string(body),
+		Truncated:  truncated,
+		Size:       size,
+		Cookies:    resp.Header.Values("Set-Cookie"),
+	}
+	if response.Cookies == nil {
+		response.Cookies = []string{}
+	}
+	if !utf8.Valid(body) {
+		response.Body = base64.StdEncoding.EncodeToString(body)
+		response.Encoding = "base64"
+	}
+	for key, values := range resp.Header {
+		response.Headers[strings.ToLower(key)] = strings.Join(values, ", ")
+	}
+	return response
+}
+
+// AllowedHosts returns DefaultAllowedHosts plus the comma-separated HTTP_ALLOWED_HOSTS config value
+func AllowedHosts() ([]string, error) {
+	value, err := config.GetConfigValue("HTTP_ALLOWED_HOSTS")
+	if err != nil {
+		return nil, err
+	}
+
+	hosts := append([]string{}, DefaultAllowedHosts...)
+	for _, host := range strings.Split(value, ",") {
+		if host = strings.TrimSpace(host); host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts, nil
+}
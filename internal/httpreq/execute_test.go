@@ -0,0 +1,173 @@
+package httpreq
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHostAllowed(t *testing.T) {
+	patterns := []string{"localhost", "*.internal.example.com", "10.0.0.5", "[::1]"}
+	tests := map[string]bool{
+		"localhost":                 true,
+		"LOCALHOST.":                true,
+		"api.internal.example.com":  true,
+		"internal.example.com":      false,
+		"evil-internal.example.com": false,
+		"10.0.0.5":                  true,
+		"10.0.0.6":                  false,
+		"::1":                       true,
+		"example.org":               false,
+	}
+	for host, want := range tests {
+		if got := HostAllowed(host, patterns); got != want {
+			t.Errorf("HostAllowed(%q) = %v, want %v", host, got, want)
+		}
+	}
+	if !HostAllowed("anything.test", []string{"*"}) {
+		t.Error(`HostAllowed() should allow any host for "*"`)
+	}
+}
+
+func TestExecute(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc"})
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]string{
+			"method":      r.Method,
+			"query":       r.URL.RawQuery,
+			"accept":      r.Header.Get("Accept"),
+			"auth":        r.Header.Get("Authorization"),
+			"contentType": r.Header.Get("Content-Type"),
+			"body":        string(body),
+		})
+	}))
+	defer server.Close()
+
+	executor := &Executor{AllowedHosts: DefaultAllowedHosts}
+	response, err := executor.Execute(context.Background(), Request{
+		Method:      "post",
+		URL:         server.URL + "/users",
+		Headers:     []KeyValue{{Key: "Accept", Value: "application/json", Enabled: true}, {Key: "X-Off", Value: "1"}},
+		QueryParams: []KeyValue{{Key: "page", Value: "2", Enabled: true}},
+		BodyType:    "json",
+		BodyContent: `{"name":"Ada"}`,
+		Auth:        Auth{Type: "bearer", Token: "secret"},
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if response.Status != http.StatusCreated || response.StatusText != "Created" {
+		t.Errorf("Execute() status = %d %q", response.Status, response.StatusText)
+	}
+	if response.Headers["content-type"] != "application/json" || len(response.Cookies) != 1 {
+		t.Errorf("Execute() headers = %v, cookies = %v", response.Headers, response.Cookies)
+	}
+	if response.Size != int64(len(response.Body)) || response.Time != response.Timing.Total {
+		t.Errorf("Execute() size = %d, time = %d, timing = %+v", response.Size, response.Time, response.Timing)
+	}
+
+	var echoed map[string]string
+	if err := json.Unmarshal([]byte(response.Body), &echoed); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"method":      "POST",
+		"query":       "page=2",
+		"accept":      "application/json",
+		"auth":        "Bearer secret",
+		"contentType": "application/json",
+		"body":        `{"name":"Ada"}`,
+	}
+	for key, value := range want {
+		if echoed[key] != value {
+			t.Errorf("server saw %s = %q, want %q", key, echoed[key], value)
+		}
+	}
+}
+
+func TestExecuteFormData(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		io.WriteString(w, r.FormValue("name")+"|"+r.URL.Query().Get("api_key"))
+	}))
+	defer server.Close()
+
+	executor := &Executor{AllowedHosts: DefaultAllowedHosts}
+	response, err := executor.Execute(context.Background(), Request{
+		Method:   "PUT",
+		URL:      server.URL,
+		BodyType: "form-data",
+		FormData: []KeyValue{{Key: "name", Value: "Ada", Enabled: true}},
+		Auth:     Auth{Type: "apikey", APIKeyName: "api_key", APIKeyValue: "k", APIKeyLocation: "query"},
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if response.Body != "Ada|k" {
+		t.Errorf("Execute() body = %q, want the form field and query API key", response.Body)
+	}
+}
+
+func TestExecuteAllowList(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://example.com/", http.StatusFound)
+	}))
+	defer server.Close()
+
+	executor := &Executor{AllowedHosts: append([]string{"api.example.org"}, DefaultAllowedHosts...)}
+	if _, err := executor.Execute(context.Background(), Request{URL: "http://example.com/"}); !errors.Is(err, ErrHostNotAllowed) {
+		t.Errorf("Execute() to a host outside the allow-list error = %v", err)
+	} else if !strings.Contains(err.Error(), `doclific set HTTP_ALLOWED_HOSTS "api.example.org, example.com"`) {
+		t.Errorf("Execute() error = %v, want the command allowing the host", err)
+	}
+	if _, err := executor.Execute(context.Background(), Request{URL: server.URL}); !errors.Is(err, ErrHostNotAllowed) {
+		t.Errorf("Execute() following a redirect outside the allow-list error = %v", err)
+	}
+	if _, err := executor.Execute(context.Background(), Request{URL: "file:///etc/passwd"}); !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("Execute() with a file URL error = %v", err)
+	}
+}
+
+func TestExecuteTimeout(t *testing.T) {
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(done)
+
+	executor := &Executor{AllowedHosts: DefaultAllowedHosts, Timeout: 50 * time.Millisecond}
+	_, err := executor.Execute(context.Background(), Request{URL: server.URL})
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("Execute() error = %v, want a timeout", err)
+	}
+}
+
+func TestCurl(t *testing.T) {
+	request := Request{
+		Method:      "GET",
+		URL:         "https://api.example.com/search?q=1",
+		QueryParams: []KeyValue{{Key: "page", Value: "2", Enabled: true}},
+		Auth:        Auth{Type: "basic", Username: "ada", Password: "it's"},
+	}
+	want := "curl -X GET 'https://api.example.com/search?q=1&page=2' \\\n  -u 'ada:it'\\''s'"
+	if got := request.Curl(); got != want {
+		t.Errorf("Curl() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,224 @@
+package httpreq
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// insomniaIdentifier matches variable names Insomnia templates can use after "_."
+var insomniaIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Insomnia export format v4 elements
+type insomniaExport struct {
+	Type         string             `json:"_type"`
+	ExportFormat int                `json:"__export_format"`
+	ExportDate   string             `json:"__export_date"`
+	ExportSource string             `json:"__export_source"`
+	Resources    []insomniaResource `json:"resources"`
+}
+
+// insomniaResource is a workspace, folder (request_group), request or environment, listed flat with parent IDs
+type insomniaResource struct {
+	ID             string            `json:"_id"`
+	Type           string            `json:"_type"`
+	ParentID       *string           `json:"parentId"`
+	Name           string            `json:"name"`
+	Description    string            `json:"description,omitempty"`
+	Scope          string            `json:"scope,omitempty"`
+	Method         string            `json:"method,omitempty"`
+	URL            string            `json:"url,omitempty"`
+	Headers        []insomniaPair    `json:"headers,omitempty"`
+	Parameters     []insomniaPair    `json:"parameters,omitempty"`
+	Body           *insomniaBody     `json:"body,omitempty"`
+	Authentication *insomniaAuth     `json:"authentication,omitempty"`
+	Data           map[string]string `json:"data,omitempty"`
+	SortKey        *int              `json:"metaSortKey,omitempty"`
+}
+
+type insomniaPair struct {
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	Disabled bool   `json:"disabled,omitempty"`
+}
+
+type insomniaBody struct {
+	MimeType string         `json:"mimeType,omitempty"`
+	Text     string         `json:"text,omitempty"`
+	Params   []insomniaPair `json:"params,omitempty"`
+}
+
+type insomniaAuth struct {
+	Type     string `json:"type,omitempty"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Token    string `json:"token,omitempty"`
+	Key      string `json:"key,omitempty"`
+	Value    string `json:"value,omitempty"`
+	AddTo    string `json:"addTo,omitempty"`
+}
+
+// insomniaID derives a stable resource ID, so importing a newer export updates the same requests
+func insomniaID(prefix, key string) string {
+	sum := sha1.Sum([]byte(key))
+	return prefix + "_" + hex.EncodeToString(sum[:])[:24]
+}
+
+// insomniaVariables rewrites placeholders to Insomnia's {{ _.name }} form
+func insomniaVariables(value string) string {
+	return replaceVariables(value, func(name string) string {
+		if insomniaIdentifier.MatchString(name) {
+			return "{{ _." + name + " }}"
+		}
+		return "{{ _['" + name + "'] }}"
+	})
+}
+
+// Insomnia renders the collection as an Insomnia v4 export: a workspace with one folder per doc,
+// a base environment holding the exported variables and a sub-environment per doclific environment
+func (c *Collection) Insomnia() (string, error) {
+	workspaceID := insomniaID("wrk", c.Name)
+	baseEnvironmentID := insomniaID("env", c.Name)
+	resources := []insomniaResource{
+		{ID: workspaceID, Type: "workspace", Name: c.Name, Scope: "collection"},
+	}
+
+	base := insomniaResource{ID: baseEnvironmentID, Type: "environment", ParentID: &workspaceID, Name: "Base Environment", Data: map[string]string{}}
+	for _, variable := range c.Variables {
+		base.Data[variable.Key] = variable.Value
+	}
+	resources = append(resources, base)
+	for _, environment := range c.Environments {
+		data := map[string]string{}
+		for key, value := range environment.Variables {
+			data[key] = value
+		}
+		for _, key := range environment.Secrets {
+			data[key] = "" // secrets are exported by name only
+		}
+		resources = append(resources, insomniaResource{
+			ID:       insomniaID("env", c.Name+"/"+environment.Name),
+			Type:     "environment",
+			ParentID: &baseEnvironmentID,
+			Name:     environment.Name,
+			Data:     data,
+		})
+	}
+
+	resources = append(resources, insomniaResources(workspaceID, c.Requests, c.Folders)...)
+
+	export := insomniaExport{
+		Type:         "export",
+		ExportFormat: 4,
+		ExportDate:   time.Now().UTC().Format(time.RFC3339),
+		ExportSource: "doclific",
+		Resources:    resources,
+	}
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to render Insomnia export: %w", err)
+	}
+	return string(data) + "\n", nil
+}
+
+// insomniaResources converts the requests of a doc and its child docs as folders, keeping their order
+func insomniaResources(parentID string, requests []CollectionRequest, folders []*CollectionFolder) []insomniaResource {
+	var resources []insomniaResource
+	order := 0
+	next := func() *int {
+		order++
+		key := order
+		return &key
+	}
+
+	for _, request := range requests {
+		resource := insomniaRequestFor(request, parentID)
+		resource.SortKey = next()
+		resources = append(resources, resource)
+	}
+	for _, folder := range folders {
+		folderID := insomniaID("fld", folder.FilePath)
+		resources = append(resources, insomniaResource{
+			ID:          folderID,
+			Type:        "request_group",
+			ParentID:    &parentID,
+			Name:        folder.Title,
+			Description: "doc: " + folder.FilePath,
+			SortKey:     next(),
+		})
+		resources = append(resources, insomniaResources(folderID, folder.Requests, folder.Folders)...)
+	}
+	return resources
+}
+
+// insomniaPairs converts headers, query parameters or form fields, keeping disabled ones disabled
+func insomniaPairs(pairs []KeyValue) []insomniaPair {
+	result := []insomniaPair{}
+	for _, pair := range pairs {
+		if pair.Key != "" {
+			result = append(result, insomniaPair{Name: pair.Key, Value: pair.Value, Disabled: !pair.Enabled})
+		}
+	}
+	return result
+}
+
+// insomniaRequestFor converts a request; auth is kept as Insomnia authentication rather than headers
+func insomniaRequestFor(collectionRequest CollectionRequest, parentID string) insomniaResource {
+	request := collectionRequest.Request.mapFields(insomniaVariables)
+	resource := insomniaResource{
+		ID:             insomniaID("req", parentID+":"+strconv.Itoa(collectionRequest.Line)),
+		Type:           "request",
+		ParentID:       &parentID,
+		Name:           collectionRequest.Name,
+		Method:         request.Method,
+		URL:            request.URL,
+		Headers:        insomniaPairs(request.Headers),
+		Parameters:     insomniaPairs(request.QueryParams),
+		Body:           &insomniaBody{},
+		Authentication: &insomniaAuth{},
+	}
+
+	contentType := ""
+	for _, header := range Enabled(request.Headers) {
+		if strings.EqualFold(header.Key, "Content-Type") {
+			contentType = header.Value
+		}
+	}
+	switch request.BodyType {
+	case "json":
+		// Insomnia only sets the header itself when the body type is picked in its editor
+		if contentType == "" {
+			resource.Headers = append(resource.Headers, insomniaPair{Name: "Content-Type", Value: "application/json"})
+		}
+		resource.Body = &insomniaBody{MimeType: "application/json", Text: request.BodyContent}
+	case "raw":
+		if contentType == "" {
+			contentType = "text/plain"
+		}
+		resource.Body = &insomniaBody{MimeType: contentType, Text: request.BodyContent}
+	case "x-www-form-urlencoded":
+		resource.Body = &insomniaBody{MimeType: "application/x-www-form-urlencoded", Params: insomniaPairs(request.FormData)}
+	case "form-data":
+		resource.Body = &insomniaBody{MimeType: "multipart/form-data", Params: insomniaPairs(request.FormData)}
+	}
+
+	auth := request.Auth
+	switch auth.Type {
+	case "basic":
+		resource.Authentication = &insomniaAuth{Type: "basic", Username: auth.Username, Password: auth.Password}
+	case "bearer":
+		resource.Authentication = &insomniaAuth{Type: "bearer", Token: auth.Token}
+	case "apikey":
+		addTo := "header"
+		if auth.APIKeyLocation == "query" {
+			addTo = "queryParams"
+		}
+		resource.Authentication = &insomniaAuth{Type: "apikey", Key: auth.APIKeyName, Value: auth.APIKeyValue, AddTo: addTo}
+	}
+	return resource
+}
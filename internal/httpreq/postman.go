@@ -0,0 +1,221 @@
+package httpreq
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// postmanSchema identifies the Postman collection format v2.1
+const postmanSchema = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+// Postman collection v2.1 elements
+type postmanCollection struct {
+	Info     postmanInfo       `json:"info"`
+	Item     []postmanItem     `json:"item"`
+	Variable []postmanVariable `json:"variable,omitempty"`
+}
+
+type postmanInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Schema      string `json:"schema"`
+}
+
+type postmanVariable struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Type        string `json:"type"`
+	Description string `json:"description,omitempty"`
+}
+
+type postmanItem struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Item        []postmanItem   `json:"item,omitempty"`
+	Request     *postmanRequest `json:"request,omitempty"`
+}
+
+type postmanRequest struct {
+	Method string            `json:"method"`
+	Header []postmanKeyValue `json:"header"`
+	URL    postmanURL        `json:"url"`
+	Body   *postmanBody      `json:"body,omitempty"`
+	Auth   *postmanAuth      `json:"auth,omitempty"`
+}
+
+type postmanKeyValue struct {
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Type     string `json:"type,omitempty"`
+	Disabled bool   `json:"disabled,omitempty"`
+}
+
+type postmanURL struct {
+	Raw      string            `json:"raw"`
+	Protocol string            `json:"protocol,omitempty"`
+	Host     []string          `json:"host,omitempty"`
+	Port     string            `json:"port,omitempty"`
+	Path     []string          `json:"path,omitempty"`
+	Query    []postmanKeyValue `json:"query,omitempty"`
+}
+
+type postmanBody struct {
+	Mode       string            `json:"mode"`
+	Raw        string            `json:"raw,omitempty"`
+	URLEncoded []postmanKeyValue `json:"urlencoded,omitempty"`
+	FormData   []postmanKeyValue `json:"formdata,omitempty"`
+	Options    map[string]any    `json:"options,omitempty"`
+}
+
+type postmanAuth struct {
+	Type   string            `json:"type"`
+	Basic  []postmanKeyValue `json:"basic,omitempty"`
+	Bearer []postmanKeyValue `json:"bearer,omitempty"`
+	APIKey []postmanKeyValue `json:"apikey,omitempty"`
+}
+
+// Postman renders the collection as a Postman v2.1 collection, with one folder per doc
+// and the environment's variables as collection variables
+func (c *Collection) Postman() (string, error) {
+	collection := postmanCollection{
+		Info: postmanInfo{Name: c.Name, Schema: postmanSchema},
+		Item: postmanItems(c.Requests, c.Folders),
+	}
+	if c.Environment != "" {
+		collection.Info.Description = fmt.Sprintf("Exported from doclific with the %s environment", c.Environment)
+	}
+	for _, variable := range c.Variables {
+		postmanVar := postmanVariable{Key: variable.Key, Value: variable.Value, Type: "string"}
+		if variable.Secret {
+			postmanVar.Description = "Secret: set its value in Postman"
+		}
+		collection.Variable = append(collection.Variable, postmanVar)
+	}
+
+	data, err := json.MarshalIndent(collection, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to render Postman collection: %w", err)
+	}
+	return string(data) + "\n", nil
+}
+
+// postmanItems converts the requests of a doc followed by its child docs as folders
+func postmanItems(requests []CollectionRequest, folders []*CollectionFolder) []postmanItem {
+	items := []postmanItem{}
+	for _, request := range requests {
+		items = append(items, postmanItem{Name: request.Name, Request: postmanRequestFor(request.Request)})
+	}
+	for _, folder := range folders {
+		items = append(items, postmanItem{
+			Name:        folder.Title,
+			Description: "doc: " + folder.FilePath,
+			Item:        postmanItems(folder.Requests, folder.Folders),
+		})
+	}
+	return items
+}
+
+// postmanVariables rewrites placeholders to Postman's {{name}} form, without the spaces doclific allows
+func postmanVariables(value string) string {
+	return replaceVariables(value, func(name string) string { return "{{" + name + "}}" })
+}
+
+// postmanPairs converts headers, query parameters or form fields, keeping disabled ones disabled
+func postmanPairs(pairs []KeyValue, fieldType string) []postmanKeyValue {
+	result := []postmanKeyValue{}
+	for _, pair := range pairs {
+		if pair.Key == "" {
+			continue
+		}
+		result = append(result, postmanKeyValue{Key: pair.Key, Value: pair.Value, Type: fieldType, Disabled: !pair.Enabled})
+	}
+	return result
+}
+
+// postmanRequestFor converts a request; auth is kept as Postman auth rather than headers
+func postmanRequestFor(request Request) *postmanRequest {
+	request = request.mapFields(postmanVariables)
+	result := &postmanRequest{
+		Method: request.Method,
+		Header: postmanPairs(request.Headers, ""),
+		URL:    postmanURLFor(request),
+	}
+
+	switch request.BodyType {
+	case "json":
+		result.Body = &postmanBody{Mode: "raw", Raw: request.BodyContent, Options: map[string]any{"raw": map[string]string{"language": "json"}}}
+	case "raw":
+		result.Body = &postmanBody{Mode: "raw", Raw: request.BodyContent}
+	case "x-www-form-urlencoded":
+		result.Body = &postmanBody{Mode: "urlencoded", URLEncoded: postmanPairs(request.FormData, "")}
+	case "form-data":
+		result.Body = &postmanBody{Mode: "formdata", FormData: postmanPairs(request.FormData, "text")}
+	}
+
+	auth := request.Auth
+	switch auth.Type {
+	case "basic":
+		result.Auth = &postmanAuth{Type: "basic", Basic: []postmanKeyValue{
+			{Key: "username", Value: auth.Username, Type: "string"},
+			{Key: "password", Value: auth.Password, Type: "string"},
+		}}
+	case "bearer":
+		result.Auth = &postmanAuth{Type: "bearer", Bearer: []postmanKeyValue{{Key: "token", Value: auth.Token, Type: "string"}}}
+	case "apikey":
+		location := "header"
+		if auth.APIKeyLocation == "query" {
+			location = "query"
+		}
+		result.Auth = &postmanAuth{Type: "apikey", APIKey: []postmanKeyValue{
+			{Key: "key", Value: auth.APIKeyName, Type: "string"},
+			{Key: "value", Value: auth.APIKeyValue, Type: "string"},
+			{Key: "in", Value: location, Type: "string"},
+		}}
+	default:
+		result.Auth = &postmanAuth{Type: "noauth"}
+	}
+	return result
+}
+
+// postmanURLFor splits the request URL into the parts Postman stores next to the raw URL
+func postmanURLFor(request Request) postmanURL {
+	base, query, _ := strings.Cut(request.URL, "?")
+	result := postmanURL{Raw: base, Query: []postmanKeyValue{}}
+
+	rest := base
+	if scheme, afterScheme, ok := strings.Cut(rest, "://"); ok {
+		result.Protocol, rest = scheme, afterScheme
+	}
+	host, path, hasPath := strings.Cut(rest, "/")
+	if i := strings.LastIndex(host, ":"); i >= 0 && i < len(host)-1 && strings.Trim(host[i+1:], "0123456789") == "" {
+		host, result.Port = host[:i], host[i+1:]
+	}
+	if host != "" {
+		result.Host = strings.Split(host, ".")
+	}
+	if hasPath {
+		result.Path = strings.Split(path, "/")
+	}
+
+	// Query parameters written into the URL come first, as they do when the request is sent
+	var enabled []string
+	if query != "" {
+		for _, param := range strings.Split(query, "&") {
+			key, value, _ := strings.Cut(param, "=")
+			result.Query = append(result.Query, postmanKeyValue{Key: key, Value: value})
+			enabled = append(enabled, param)
+		}
+	}
+	for _, param := range postmanPairs(request.QueryParams, "") {
+		result.Query = append(result.Query, param)
+		if !param.Disabled {
+			enabled = append(enabled, param.Key+"="+param.Value)
+		}
+	}
+
+	if len(enabled) > 0 {
+		result.Raw += "?" + strings.Join(enabled, "&")
+	}
+	return result
+}
@@ -0,0 +1,515 @@
+package httpreq
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"doclific/internal/config"
+	"doclific/internal/core"
+)
+
+const (
+	// recordingFileName is the file in the user config dir holding the exchanges of the last recording
+	recordingFileName = "recording.jsonl"
+
+	// maxExampleBodySize is how much of a recorded response body is kept in a block's response attribute
+	maxExampleBodySize = 64 << 10
+)
+
+// credentialNamePattern matches header, query parameter and form field names that usually carry credentials
+var credentialNamePattern = regexp.MustCompile(`(?i)key|token|secret|auth|passw`)
+
+// skippedRecordedHeaders are request headers added by browsers, clients and proxies rather than
+// chosen by the API's caller; they are left out of blocks made from recorded exchanges
+var skippedRecordedHeaders = map[string]bool{
+	"accept-encoding": true, "accept-language": true, "authorization": true, "cache-control": true,
+	"connection": true, "content-length": true, "content-type": true, "cookie": true, "dnt": true,
+	"forwarded": true, "host": true, "if-modified-since": true, "if-none-match": true, "keep-alive": true,
+	"origin": true, "pragma": true, "priority": true, "proxy-connection": true, "referer": true, "te": true,
+	"transfer-encoding": true, "upgrade-insecure-requests": true, "user-agent": true,
+	"x-forwarded-for": true, "x-forwarded-host": true, "x-forwarded-proto": true,
+}
+
+// Exchange is a request forwarded by a Recorder and the response it got
+type Exchange struct {
+	ID       int               `json:"id"`
+	Time     time.Time         `json:"time"`
+	Method   string            `json:"method"`
+	URL      string            `json:"url"` // the URL the request was forwarded to
+	Headers  map[string]string `json:"headers"`
+	Body     string            `json:"body,omitempty"`
+	Encoding string            `json:"encoding,omitempty"` // "base64" when the request body is not valid UTF-8
+	// BodyTruncated is set when the request body was longer than MaxBodySize and only its start was recorded
+	BodyTruncated bool      `json:"bodyTruncated,omitempty"`
+	Response      *Response `json:"response,omitempty"`
+	Error         string    `json:"error,omitempty"` // why the target could not be reached
+}
+
+// Summary describes the exchange on one line, such as "#3 POST /users?page=2 → 201 in 12ms"
+func (e Exchange) Summary() string {
+	target := e.URL
+	if u, err := url.Parse(e.URL); err == nil {
+		target = u.RequestURI()
+	}
+	if e.Response == nil {
+		return fmt.Sprintf("#%d %s %s → failed: %s", e.ID, e.Method, target, e.Error)
+	}
+	return fmt.Sprintf("#%d %s %s → %d in %dms", e.ID, e.Method, target, e.Response.Status, e.Response.Time)
+}
+
+// DefaultRecordingPath returns where recordings are kept: the user config dir, since they hold real credentials
+func DefaultRecordingPath() (string, error) {
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, recordingFileName), nil
+}
+
+// exchangeKey is the context key carrying an exchange from the incoming request to its response
+type exchangeKey struct{}
+
+// Recorder is a reverse proxy to a target that appends every exchange to a recording file
+type Recorder struct {
+	OnExchange func(Exchange) // called after each exchange is recorded, such as to log it
+
+	proxy    *httputil.ReverseProxy
+	mu       sync.Mutex
+	file     *os.File
+	nextID   int
+	writeErr error
+}
+
+// NewRecorder starts a recording of the exchanges with target in path, replacing any previous recording
+func NewRecorder(target, path string) (*Recorder, error) {
+	targetURL, err := url.Parse(target)
+	if err != nil || (targetURL.Scheme != "http" && targetURL.Scheme != "https") || targetURL.Host == "" {
+		return nil, fmt.Errorf("invalid target %q: use an http:// or https:// URL", target)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create recording directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create recording: %w", err)
+	}
+
+	recorder := &Recorder{file: file}
+	recorder.proxy = &httputil.ReverseProxy{
+		Rewrite: func(request *httputil.ProxyRequest) {
+			request.SetURL(targetURL)
+			request.SetXForwarded()
+		},
+		ModifyResponse: recorder.recordResponse,
+		ErrorHandler:   recorder.recordError,
+	}
+	return recorder, nil
+}
+
+// ServeHTTP forwards the request to the target and records it with its response
+func (r *Recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	// Only the first MaxBodySize bytes are kept; the rest streams through to the target
+	body, err := io.ReadAll(io.LimitReader(req.Body, MaxBodySize+1))
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	req.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), req.Body), req.Body}
+
+	truncated := len(body) > MaxBodySize
+	if truncated {
+		body = body[:MaxBodySize]
+	}
+	exchange := &Exchange{Time: time.Now(), Method: req.Method, Headers: map[string]string{}, Body: string(body), BodyTruncated: truncated}
+	if !utf8.Valid(body) {
+		exchange.Body, exchange.Encoding = base64.StdEncoding.EncodeToString(body), "base64"
+	}
+	for key, values := range req.Header {
+		exchange.Headers[key] = strings.Join(values, ", ")
+	}
+
+	r.proxy.ServeHTTP(w, req.WithContext(context.WithValue(req.Context(), exchangeKey{}, exchange)))
+}
+
+// recordResponse records the target's response, leaving the body intact for the client
+// Event streams are passed through without their body, since they do not end
+func (r *Recorder) recordResponse(resp *http.Response) error {
+	exchange := resp.Request.Context().Value(exchangeKey{}).(*Exchange)
+	exchange.URL = resp.Request.URL.String()
+
+	// Only the first MaxBodySize bytes are kept; the rest streams through to the client
+	var body []byte
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "text/event-stream" {
+		data, err := io.ReadAll(io.LimitReader(resp.Body, MaxBodySize+1))
+		if err != nil {
+			return err
+		}
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(data), resp.Body), resp.Body}
+		body = data
+	}
+
+	// The recording keeps the body as the client's code sees it after decompression
+	decoded := false
+	if strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		if reader, err := gzip.NewReader(bytes.NewReader(body)); err == nil {
+			if data, err := io.ReadAll(reader); err == nil {
+				body, decoded = data, true
+			}
+		}
+	}
+
+	size := int64(len(body))
+	truncated := size > MaxBodySize
+	if truncated {
+		body = body[:MaxBodySize]
+		if resp.ContentLength > size && !decoded {
+			size = resp.ContentLength
+		}
+	}
+	response := newResponse(resp, body, size, truncated)
+	response.Time = time.Since(exchange.Time).Milliseconds()
+	if decoded {
+		delete(response.Headers, "content-encoding")
+		delete(response.Headers, "content-length")
+	}
+	exchange.Response = response
+
+	r.record(exchange)
+	return nil
+}
+
+// recordError records a request the target did not answer and replies with 502 Bad Gateway
+func (r *Recorder) recordError(w http.ResponseWriter, req *http.Request, err error) {
+	exchange := req.Context().Value(exchangeKey{}).(*Exchange)
+	exchange.URL = req.URL.String()
+	exchange.Error = err.Error()
+	r.record(exchange)
+
+	http.Error(w, "doclific http record: "+err.Error(), http.StatusBadGateway)
+}
+
+// record numbers the exchange and appends it to the recording
+func (r *Recorder) record(exchange *Exchange) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+	exchange.ID = r.nextID
+	data, err := json.Marshal(exchange)
+	if err == nil {
+		_, err = r.file.Write(append(data, '\n'))
+	}
+	if err != nil && r.writeErr == nil {
+		r.writeErr = fmt.Errorf("failed to write recording: %w", err)
+	}
+
+	if r.OnExchange != nil {
+		r.OnExchange(*exchange)
+	}
+}
+
+// Close finishes the recording, returning the first error writing it
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	err := r.file.Close()
+	if r.writeErr != nil {
+		return r.writeErr
+	}
+	return err
+}
+
+// ReadRecording reads the exchanges of a recording in the order they happened
+func ReadRecording(path string) ([]Exchange, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("no recording found at %s", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recording: %w", err)
+	}
+
+	exchanges := []Exchange{}
+	for i, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		var exchange Exchange
+		if err := json.Unmarshal([]byte(line), &exchange); err != nil {
+			return nil, fmt.Errorf("failed to parse recording line %d: %w", i+1, err)
+		}
+		exchanges = append(exchanges, exchange)
+	}
+	return exchanges, nil
+}
+
+// SelectExchanges picks exchanges by ID from a list such as "1,3-5"
+// An empty list picks every exchange that got a response
+func SelectExchanges(exchanges []Exchange, pick string) ([]Exchange, error) {
+	byID := map[int]Exchange{}
+	for _, exchange := range exchanges {
+		byID[exchange.ID] = exchange
+	}
+
+	var selected []Exchange
+	if strings.TrimSpace(pick) == "" {
+		for _, exchange := range exchanges {
+			if exchange.Response != nil {
+				selected = append(selected, exchange)
+			}
+		}
+		return selected, nil
+	}
+
+	for _, part := range strings.Split(pick, ",") {
+		from, to, isRange := strings.Cut(strings.TrimSpace(part), "-")
+		first, err := strconv.Atoi(strings.TrimSpace(from))
+		last := first
+		if err == nil && isRange {
+			last, err = strconv.Atoi(strings.TrimSpace(to))
+		}
+		if err != nil || last < first {
+			return nil, fmt.Errorf("invalid exchange selection %q (use IDs and ranges such as 1,3-5)", part)
+		}
+
+		for id := first; id <= last; id++ {
+			exchange, ok := byID[id]
+			if !ok {
+				return nil, fmt.Errorf("no exchange #%d in the recording", id)
+			}
+			if exchange.Response == nil {
+				return nil, fmt.Errorf("exchange #%d has no response: %s", id, exchange.Error)
+			}
+			selected = append(selected, exchange)
+		}
+	}
+	return selected, nil
+}
+
+// orderedPairs splits a query string or form body into enabled pairs, keeping their order
+func orderedPairs(raw string) []KeyValue {
+	var pairs []KeyValue
+	for _, part := range strings.Split(raw, "&") {
+		if part == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(part, "=")
+		if unescaped, err := url.QueryUnescape(key); err == nil {
+			key = unescaped
+		}
+		if unescaped, err := url.QueryUnescape(value); err == nil {
+			value = unescaped
+		}
+		pairs = append(pairs, KeyValue{Key: key, Value: value, Enabled: true})
+	}
+	return pairs
+}
+
+// credentialVariable names the variable standing in for a credential, such as x-api-key for X-Api-Key
+func credentialVariable(name string) string {
+	variable := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' || r == '.' || r == '-' {
+			return r
+		}
+		return '_'
+	}, strings.ToLower(name))
+	if !variableNamePattern.MatchString(variable) {
+		variable = "_" + variable
+	}
+	return variable
+}
+
+// redactCredentials replaces the values of pairs whose names look like credentials, such as
+// X-Api-Key or api_key, with {{name}} variables
+func redactCredentials(pairs []KeyValue) []KeyValue {
+	for i, pair := range pairs {
+		if credentialNamePattern.MatchString(pair.Key) && pair.Value != "" {
+			pairs[i].Value = "{{" + credentialVariable(pair.Key) + "}}"
+		}
+	}
+	return pairs
+}
+
+// Block converts the exchange into an <HttpRequest> block with its response as the example and
+// its status as expectStatus. A non-empty baseURL, such as {{baseUrl}}, replaces the recorded origin.
+// Credentials are replaced with variables so they do not end up in the docs: Authorization becomes
+// {{token}} or {{username}}/{{password}}, headers, query parameters and form fields named like
+// credentials (X-Api-Key, ?api_key=) become {{x-api-key}} or {{api_key}}, and cookies are dropped
+func (e Exchange) Block(baseURL string) Block {
+	block := Block{Request: Request{
+		Method:      e.Method,
+		URL:         e.URL,
+		Headers:     []KeyValue{},
+		QueryParams: []KeyValue{},
+		BodyType:    "none",
+		FormData:    []KeyValue{},
+		Auth:        Auth{Type: "none"},
+	}}
+	request := &block.Request
+
+	if u, err := url.Parse(e.URL); err == nil {
+		request.QueryParams = append(request.QueryParams, orderedPairs(u.RawQuery)...)
+		u.RawQuery, u.Fragment = "", ""
+		request.URL = u.String()
+		if baseURL != "" {
+			request.URL = strings.TrimSuffix(baseURL, "/") + u.EscapedPath()
+		}
+	}
+
+	headers := map[string]string{}
+	for key, value := range e.Headers {
+		headers[strings.ToLower(key)] = value
+	}
+	var names []string
+	for key := range e.Headers {
+		lower := strings.ToLower(key)
+		if !skippedRecordedHeaders[lower] && !strings.HasPrefix(lower, "sec-") && !(lower == "accept" && e.Headers[key] == "*/*") {
+			names = append(names, key)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		request.Headers = append(request.Headers, KeyValue{Key: name, Value: e.Headers[name], Enabled: true})
+	}
+
+	if authorization := headers["authorization"]; authorization != "" {
+		scheme, _, _ := strings.Cut(authorization, " ")
+		switch strings.ToLower(scheme) {
+		case "bearer":
+			request.Auth = Auth{Type: "bearer", Token: "{{token}}"}
+		case "basic":
+			request.Auth = Auth{Type: "basic", Username: "{{username}}", Password: "{{password}}"}
+		default:
+			request.Headers = append(request.Headers, KeyValue{Key: "Authorization", Value: "{{authorization}}", Enabled: true})
+		}
+	}
+
+	contentType := headers["content-type"]
+	mediaType, params, _ := mime.ParseMediaType(contentType)
+	switch {
+	case e.Body == "" || e.Encoding != "" || e.BodyTruncated:
+		// binary and cut-off bodies cannot be edited in a block
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		request.BodyType, request.BodyContent = "json", e.Body
+		var indented bytes.Buffer
+		if json.Indent(&indented, []byte(e.Body), "", "  ") == nil {
+			request.BodyContent = indented.String()
+		}
+	case mediaType == "application/x-www-form-urlencoded":
+		request.BodyType = "x-www-form-urlencoded"
+		request.FormData = append(request.FormData, orderedPairs(e.Body)...)
+	case mediaType == "multipart/form-data":
+		request.BodyType = "form-data"
+		reader := multipart.NewReader(strings.NewReader(e.Body), params["boundary"])
+		for {
+			part, err := reader.NextPart()
+			if err != nil {
+				break
+			}
+			if part.FileName() != "" {
+				continue // files cannot be attached to a block
+			}
+			value, _ := io.ReadAll(part)
+			request.FormData = append(request.FormData, KeyValue{Key: part.FormName(), Value: string(value), Enabled: true})
+		}
+	default:
+		request.BodyType, request.BodyContent = "raw", e.Body
+		if contentType != "" {
+			request.Headers = append(request.Headers, KeyValue{Key: "Content-Type", Value: contentType, Enabled: true})
+		}
+	}
+
+	request.Headers = redactCredentials(request.Headers)
+	request.QueryParams = redactCredentials(request.QueryParams)
+	request.FormData = redactCredentials(request.FormData)
+
+	if e.Response != nil {
+		block.Assertions.Status = strconv.Itoa(e.Response.Status)
+		block.Response = exampleResponse(*e.Response)
+	}
+	return block
+}
+
+// exampleResponse trims a recorded response for storing in a doc: cookies are dropped and long bodies cut short
+func exampleResponse(response Response) *Response {
+	headers := map[string]string{}
+	for key, value := range response.Headers {
+		if key != "set-cookie" {
+			headers[key] = value
+		}
+	}
+	response.Headers = headers
+	response.Cookies = []string{}
+	response.Timing = Timing{}
+
+	if len(response.Body) > maxExampleBodySize {
+		response.Truncated = true
+		if response.Encoding != "" {
+			response.Body = "" // a cut base64 body cannot be decoded
+		} else {
+			body := response.Body[:maxExampleBodySize]
+			for !utf8.ValidString(body) {
+				body = body[:len(body)-1]
+			}
+			response.Body = body
+		}
+	}
+	return &response
+}
+
+// InsertExchanges appends the exchanges to the end of a doc as <HttpRequest> blocks under "## METHOD /path" headings
+func InsertExchanges(filePath string, exchanges []Exchange, baseURL string) error {
+	content, err := core.GetDoc(filePath)
+	if err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	b.WriteString(strings.TrimRight(content, "\n"))
+	for _, exchange := range exchanges {
+		mdx, err := exchange.Block(baseURL).MDX()
+		if err != nil {
+			return err
+		}
+		path := exchange.URL
+		if u, err := url.Parse(exchange.URL); err == nil {
+			path = u.Path
+		}
+		if b.Len() > 0 {
+			b.WriteString("\n\n")
+		}
+		fmt.Fprintf(&b, "## %s %s\n\n%s", exchange.Method, strings.NewReplacer("{", `\{`, "}", `\}`, "<", `\<`).Replace(path), mdx)
+	}
+	b.WriteString("\n")
+
+	return core.UpdateDoc(filePath, b.String())
+}
@@ -0,0 +1,271 @@
+package httpreq
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"doclific/internal/core"
+)
+
+func TestRecorder(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Encoding", "gzip")
+		w.WriteHeader(http.StatusCreated)
+		gz := gzip.NewWriter(w)
+		gz.Write([]byte(`{"path":"` + r.URL.Path + `","body":` + string(body) + `}`))
+		gz.Close()
+	}))
+	defer target.Close()
+
+	path := filepath.Join(t.TempDir(), "recording.jsonl")
+	recorder, err := NewRecorder(target.URL, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var logged []string
+	recorder.OnExchange = func(exchange Exchange) { logged = append(logged, exchange.Summary()) }
+	proxy := httptest.NewServer(recorder)
+	defer proxy.Close()
+
+	req, _ := http.NewRequest("POST", proxy.URL+"/users?page=2", strings.NewReader(`{"name":"Ada"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer s3cret")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated || string(body) != `{"path":"/users","body":{"name":"Ada"}}` {
+		t.Errorf("proxied response = %d %s", resp.StatusCode, body)
+	}
+
+	large := strings.Repeat("a", MaxBodySize+10)
+	resp, err = http.Post(proxy.URL+"/upload", "text/plain", strings.NewReader(large))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ = io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.Contains(string(body), `"body":`+large[:100]) || len(body) < MaxBodySize+10 {
+		t.Errorf("proxied large request was not forwarded in full: %d bytes back", len(body))
+	}
+
+	unreachable, err := NewRecorder("http://127.0.0.1:1", filepath.Join(t.TempDir(), "failed.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	failed := httptest.NewRecorder()
+	unreachable.ServeHTTP(failed, httptest.NewRequest("GET", "/health", nil))
+	unreachable.Close()
+	if failed.Code != http.StatusBadGateway {
+		t.Errorf("unreachable target status = %d, want 502", failed.Code)
+	}
+
+	if err := recorder.Close(); err != nil {
+		t.Fatal(err)
+	}
+	exchanges, err := ReadRecording(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(exchanges) != 2 || len(logged) != 2 || !strings.HasPrefix(logged[0], "#1 POST /users?page=2 → 201 in ") {
+		t.Fatalf("recording = %+v, logged %v", exchanges, logged)
+	}
+	if uploaded := exchanges[1]; len(uploaded.Body) != MaxBodySize || !uploaded.BodyTruncated || uploaded.Block("").BodyType != "none" {
+		t.Errorf("large request recorded %d bytes, truncated = %v", len(uploaded.Body), uploaded.BodyTruncated)
+	}
+	exchange := exchanges[0]
+	if exchange.URL != target.URL+"/users?page=2" || exchange.Body != `{"name":"Ada"}` || exchange.Headers["Authorization"] != "Bearer s3cret" {
+		t.Errorf("exchange = %+v", exchange)
+	}
+	if exchange.Response.Body != `{"path":"/users","body":{"name":"Ada"}}` || exchange.Response.Headers["content-encoding"] != "" {
+		t.Errorf("response = %+v, want the decompressed body", exchange.Response)
+	}
+
+	if _, err := NewRecorder("localhost:3000", path); err == nil {
+		t.Error("NewRecorder() should reject a target without a scheme")
+	}
+	if _, err := ReadRecording(filepath.Join(t.TempDir(), "none.jsonl")); err == nil {
+		t.Error("ReadRecording() should fail without a recording")
+	}
+}
+
+func TestExchangeBlock(t *testing.T) {
+	exchange := Exchange{
+		Method: "POST",
+		URL:    "http://localhost:3000/users/7?tag=a%20b&tag=c",
+		Headers: map[string]string{
+			"Authorization":   "Bearer s3cret",
+			"Content-Type":    "application/json; charset=utf-8",
+			"Cookie":          "session=abc",
+			"Accept":          "*/*",
+			"Sec-Fetch-Mode":  "cors",
+			"X-Tenant":        "acme",
+			"X-Forwarded-For": "127.0.0.1",
+		},
+		Body: `{"name":"Ada"}`,
+		Response: &Response{
+			Status:  201,
+			Headers: map[string]string{"content-type": "application/json", "set-cookie": "session=new"},
+			Body:    strings.Repeat("é", maxExampleBodySize),
+			Cookies: []string{"session=new"},
+			Timing:  Timing{Total: 5},
+		},
+	}
+
+	block := exchange.Block("{{baseUrl}}")
+	if block.URL != "{{baseUrl}}/users/7" || len(block.QueryParams) != 2 || block.QueryParams[0] != (KeyValue{Key: "tag", Value: "a b", Enabled: true}) {
+		t.Errorf("url = %q, queryParams = %+v", block.URL, block.QueryParams)
+	}
+	if len(block.Headers) != 1 || block.Headers[0].Key != "X-Tenant" {
+		t.Errorf("headers = %+v, want only the caller's own headers", block.Headers)
+	}
+	if block.Auth != (Auth{Type: "bearer", Token: "{{token}}"}) || block.BodyType != "json" || block.BodyContent != "{\n  \"name\": \"Ada\"\n}" {
+		t.Errorf("auth = %+v, body = %s %q", block.Auth, block.BodyType, block.BodyContent)
+	}
+	if block.Assertions.Status != "201" || !block.Response.Truncated || len(block.Response.Body) > maxExampleBodySize || block.Response.Headers["set-cookie"] != "" || len(block.Response.Cookies) != 0 {
+		t.Errorf("expectStatus = %q, response = %+v", block.Assertions.Status, block.Response.Headers)
+	}
+	if exchange.Response.Headers["set-cookie"] == "" {
+		t.Error("Block() should not modify the recorded response")
+	}
+
+	mdx, err := block.MDX()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(mdx, "s3cret") || strings.Contains(mdx, "session=") {
+		t.Errorf("MDX() leaks credentials: %s", mdx)
+	}
+	components := core.ParseMDXComponents(mdx, core.ComponentHttpRequest)
+	var response Response
+	if len(components) != 1 || json.Unmarshal([]byte(components[0].Attributes["response"]), &response) != nil || response.Status != 201 {
+		t.Errorf("MDX() response attribute = %q", components[0].Attributes["response"])
+	}
+
+	form := Exchange{
+		Method:  "PUT",
+		URL:     "https://api.example.com/login",
+		Headers: map[string]string{"Content-Type": "application/x-www-form-urlencoded", "Authorization": "Basic YWRhOml0"},
+		Body:    "user=ada&note=a+b",
+	}
+	block = form.Block("")
+	if block.URL != "https://api.example.com/login" || block.BodyType != "x-www-form-urlencoded" || len(block.FormData) != 2 || block.FormData[1].Value != "a b" {
+		t.Errorf("form block = %+v", block.Request)
+	}
+	if block.Auth.Type != "basic" || block.Auth.Password != "{{password}}" || block.Response != nil || block.Assertions.Status != "" {
+		t.Errorf("form block auth = %+v, response = %+v", block.Auth, block.Response)
+	}
+
+	var multipartBody bytes.Buffer
+	multipartBody.WriteString("--xyz\r\nContent-Disposition: form-data; name=\"title\"\r\n\r\nHello\r\n" +
+		"--xyz\r\nContent-Disposition: form-data; name=\"file\"; filename=\"a.txt\"\r\n\r\nfile\r\n--xyz--\r\n")
+	upload := Exchange{Method: "POST", URL: "http://localhost/upload", Headers: map[string]string{"Content-Type": "multipart/form-data; boundary=xyz"}, Body: multipartBody.String()}
+	if block := upload.Block(""); block.BodyType != "form-data" || len(block.FormData) != 1 || block.FormData[0].Value != "Hello" {
+		t.Errorf("multipart block = %+v", block.FormData)
+	}
+
+	keyed := Exchange{
+		Method:  "POST",
+		URL:     "https://api.example.com/search?q=go&api_key=k3y&Access-Token=t0k",
+		Headers: map[string]string{"X-Api-Key": "k3y", "X-Auth-Token": "t0k", "Api-Key": "k3y", "X-Client-Secret": "s3c", "X-Request-Id": "42", "Content-Type": "application/x-www-form-urlencoded"},
+		Body:    "user=ada&password=hunter2",
+	}
+	block = keyed.Block("")
+	if mdx, _ := block.MDX(); strings.Contains(mdx, "k3y") || strings.Contains(mdx, "t0k") || strings.Contains(mdx, "s3c") || strings.Contains(mdx, "hunter2") {
+		t.Errorf("MDX() leaks credential headers, query parameters or form fields: %s", mdx)
+	}
+	if block.QueryParams[0].Value != "go" || block.QueryParams[1].Value != "{{api_key}}" || block.QueryParams[2].Value != "{{access-token}}" {
+		t.Errorf("queryParams = %+v", block.QueryParams)
+	}
+	var headers []string
+	for _, header := range block.Headers {
+		headers = append(headers, header.Key+": "+header.Value)
+	}
+	if got := strings.Join(headers, ", "); got != "Api-Key: {{api-key}}, X-Api-Key: {{x-api-key}}, X-Auth-Token: {{x-auth-token}}, X-Client-Secret: {{x-client-secret}}, X-Request-Id: 42" {
+		t.Errorf("headers = %s", got)
+	}
+	if block.FormData[0].Value != "ada" || block.FormData[1].Value != "{{password}}" {
+		t.Errorf("formData = %+v", block.FormData)
+	}
+
+	text := Exchange{Method: "POST", URL: "http://localhost/notes", Headers: map[string]string{"Content-Type": "text/csv", "Authorization": "Token abc"}, Body: "a,b"}
+	block = text.Block("")
+	if block.BodyType != "raw" || block.BodyContent != "a,b" || len(block.Headers) != 2 || block.Headers[0].Value != "{{authorization}}" || block.Headers[1].Value != "text/csv" {
+		t.Errorf("raw block = %+v", block.Request)
+	}
+}
+
+func TestSelectExchanges(t *testing.T) {
+	exchanges := []Exchange{
+		{ID: 1, Response: &Response{}}, {ID: 2, Error: "refused"}, {ID: 3, Response: &Response{}},
+		{ID: 4, Response: &Response{}}, {ID: 5, Response: &Response{}},
+	}
+
+	ids := func(selected []Exchange) string {
+		var result []string
+		for _, exchange := range selected {
+			result = append(result, string(rune('0'+exchange.ID)))
+		}
+		return strings.Join(result, ",")
+	}
+
+	if selected, err := SelectExchanges(exchanges, ""); err != nil || ids(selected) != "1,3,4,5" {
+		t.Errorf("SelectExchanges(\"\") = %s, %v, want the exchanges with a response", ids(selected), err)
+	}
+	if selected, err := SelectExchanges(exchanges, "5, 3-4,1"); err != nil || ids(selected) != "5,3,4,1" {
+		t.Errorf("SelectExchanges() = %s, %v", ids(selected), err)
+	}
+	for _, pick := range []string{"2", "6", "4-3", "a", "1-"} {
+		if _, err := SelectExchanges(exchanges, pick); err == nil {
+			t.Errorf("SelectExchanges(%q) should fail", pick)
+		}
+	}
+}
+
+func TestInsertExchanges(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "doclific", "api"), 0755)
+	os.WriteFile(filepath.Join(dir, "doclific", "api", "config.json"), []byte(`{"title": "API", "order": 0}`), 0644)
+	os.WriteFile(filepath.Join(dir, "doclific", "api", "content.mdx"), []byte("# API\n\n"), 0644)
+	wd, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	exchanges := []Exchange{
+		{Method: "GET", URL: "http://localhost:3000/users?page=1", Headers: map[string]string{}, Response: &Response{Status: 200, Body: "[]"}},
+		{Method: "DELETE", URL: "http://localhost:3000/users/1", Headers: map[string]string{}, Response: &Response{Status: 204}},
+	}
+	if err := InsertExchanges("api", exchanges, "{{baseUrl}}"); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := core.GetDoc("api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(content, "# API\n\n## GET /users\n\n<HttpRequest method=\"GET\" url=\"{{baseUrl}}/users\"") || !strings.Contains(content, "\n\n## DELETE /users/1\n\n<HttpRequest") || !strings.HasSuffix(content, "</HttpRequest>\n") {
+		t.Errorf("doc = %s", content)
+	}
+
+	components := core.ParseMDXComponents(content, core.ComponentHttpRequest)
+	if len(components) != 2 || components[1].Attributes["expectStatus"] != "204" {
+		t.Errorf("inserted blocks = %+v", components)
+	}
+	if request := ParseComponent(components[0]); len(request.QueryParams) != 1 || request.QueryParams[0].Key != "page" {
+		t.Errorf("first block = %+v", request)
+	}
+}
@@ -0,0 +1,171 @@
+package httpreq
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"doclific/internal/core"
+)
+
+// KeyValue is an entry of an HttpRequest's headers, queryParams or formData
+type KeyValue struct {
+	Key     string `json:"key"`
+	Value   string `json:"value"`
+	Enabled bool   `json:"enabled"`
+}
+
+// Auth is the auth attribute of an HttpRequest
+type Auth struct {
+	Type           string `json:"type"` // none, basic, bearer or apikey
+	Username       string `json:"username,omitempty"`
+	Password       string `json:"password,omitempty"`
+	Token          string `json:"token,omitempty"`
+	APIKeyName     string `json:"apiKeyName,omitempty"`
+	APIKeyValue    string `json:"apiKeyValue,omitempty"`
+	APIKeyLocation string `json:"apiKeyLocation,omitempty"` // header or query
+}
+
+// Request is the content of an <HttpRequest> block
+type Request struct {
+	Method      string     `json:"method"`
+	URL         string     `json:"url"`
+	Headers     []KeyValue `json:"headers"`
+	QueryParams []KeyValue `json:"queryParams"`
+	BodyType    string     `json:"bodyType"` // none, json, raw, form-data or x-www-form-urlencoded
+	BodyContent string     `json:"bodyContent"`
+	FormData    []KeyValue `json:"formData"`
+	Auth        Auth       `json:"auth"`
+}
+
+// ParseComponent parses the attributes of an <HttpRequest> block, ignoring malformed JSON fields
+func ParseComponent(component core.MDXComponent) Request {
+	attrs := component.Attributes
+	request := Request{
+		Method:      attrs["method"],
+		URL:         attrs["url"],
+		BodyType:    attrs["bodyType"],
+		BodyContent: attrs["bodyContent"],
+		Auth:        Auth{Type: "none"},
+	}
+
+	json.Unmarshal([]byte(attrs["headers"]), &request.Headers)
+	json.Unmarshal([]byte(attrs["queryParams"]), &request.QueryParams)
+	json.Unmarshal([]byte(attrs["formData"]), &request.FormData)
+	json.Unmarshal([]byte(attrs["auth"]), &request.Auth)
+
+	request.setDefaults()
+	return request
+}
+
+// setDefaults fills in the method, body type and auth type the editor uses when they are unset
+func (r *Request) setDefaults() {
+	if r.Method == "" {
+		r.Method = "GET"
+	}
+	r.Method = strings.ToUpper(r.Method)
+	if r.BodyType == "" {
+		r.BodyType = "none"
+	}
+	if r.Auth.Type == "" {
+		r.Auth.Type = "none"
+	}
+}
+
+// Enabled returns the enabled key/value pairs
+func Enabled(pairs []KeyValue) []KeyValue {
+	var result []KeyValue
+	for _, pair := range pairs {
+		if pair.Enabled && pair.Key != "" {
+			result = append(result, pair)
+		}
+	}
+	return result
+}
+
+// FullURL returns the request URL with its enabled query parameters (and query API key) appended
+func (r Request) FullURL() string {
+	return r.fullURL(url.QueryEscape)
+}
+
+// fullURL builds the URL like FullURL, escaping each query key and value with escape
+func (r Request) fullURL(escape func(string) string) string {
+	query := url.Values{}
+	for _, param := range Enabled(r.QueryParams) {
+		query.Add(param.Key, param.Value)
+	}
+	if r.Auth.Type == "apikey" && r.Auth.APIKeyLocation == "query" && r.Auth.APIKeyName != "" {
+		query.Add(r.Auth.APIKeyName, r.Auth.APIKeyValue)
+	}
+	if len(query) == 0 {
+		return r.URL
+	}
+
+	// Sorted by key, as url.Values.Encode does
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, key := range keys {
+		for _, value := range query[key] {
+			parts = append(parts, escape(key)+"="+escape(value))
+		}
+	}
+
+	separator := "?"
+	if strings.Contains(r.URL, "?") {
+		separator = "&"
+	}
+	return r.URL + separator + strings.Join(parts, "&")
+}
+
+// Block is an <HttpRequest> block as written to a doc: the request plus the assertions and response stored with it
+type Block struct {
+	Request
+	Assertions Assertions
+	Response   *Response // shown in the editor until the request is sent again, such as a recorded example
+}
+
+// MDX renders the block with the same HTML-encoded JSON attributes as the generate-doclific-http-request skill
+func (b Block) MDX() (string, error) {
+	request := b.Request
+	request.setDefaults()
+
+	var encodeErr error
+	encode := func(name string, value any) string {
+		data, err := json.Marshal(value)
+		if err != nil && encodeErr == nil {
+			encodeErr = fmt.Errorf("failed to encode %s: %w", name, err)
+		}
+		return core.EncodeAttribute(string(data))
+	}
+	pairs := func(values []KeyValue) []KeyValue {
+		if values == nil {
+			return []KeyValue{}
+		}
+		return values
+	}
+
+	mdx := fmt.Sprintf(`<HttpRequest method="%s" url="%s" headers="%s" queryParams="%s" bodyType="%s" bodyContent="%s" formData="%s" auth="%s"`,
+		core.EncodeAttribute(request.Method), core.EncodeAttribute(request.URL),
+		encode("headers", pairs(request.Headers)), encode("queryParams", pairs(request.QueryParams)),
+		core.EncodeAttribute(request.BodyType), core.EncodeAttribute(request.BodyContent),
+		encode("formData", pairs(request.FormData)), encode("auth", request.Auth))
+	if b.Assertions.Status != "" {
+		mdx += fmt.Sprintf(` expectStatus="%s"`, core.EncodeAttribute(b.Assertions.Status))
+	}
+	if len(b.Assertions.JSON) > 0 {
+		mdx += fmt.Sprintf(` expectJson="%s"`, encode("expectJson", b.Assertions.JSON))
+	}
+	if b.Response != nil {
+		mdx += fmt.Sprintf(` response="%s"`, encode("response", b.Response))
+	}
+	if encodeErr != nil {
+		return "", encodeErr
+	}
+	return mdx + "></HttpRequest>", nil
+}
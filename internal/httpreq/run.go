@@ -0,0 +1,189 @@
+package httpreq
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+
+	"doclific/internal/core"
+)
+
+// TestResult is the outcome of running one <HttpRequest> block as a test
+type TestResult struct {
+	FilePath string        `json:"filePath"`
+	Title    string        `json:"title"`
+	Line     int           `json:"line"`
+	Name     string        `json:"name"` // method and URL as written in the doc, before variables are filled in
+	Status   int           `json:"status,omitempty"`
+	Duration time.Duration `json:"duration"`
+	Failures []string      `json:"failures"`          // assertions the response did not satisfy
+	Error    string        `json:"error,omitempty"`   // the request could not be sent or its assertions could not be read
+	Skipped  string        `json:"skipped,omitempty"` // why the block was not run
+}
+
+// Passed reports whether the block ran and satisfied its assertions
+func (r TestResult) Passed() bool {
+	return r.Skipped == "" && r.Error == "" && len(r.Failures) == 0
+}
+
+// RunDocs executes every <HttpRequest> block and checks its assertions, in doc tree order
+// An empty filePath runs every doc; otherwise the doc and the docs nested under it are run
+func RunDocs(ctx context.Context, filePath string, executor *Executor) ([]TestResult, error) {
+	docs, err := core.GetDocs()
+	if err != nil {
+		return nil, err
+	}
+
+	results := []TestResult{}
+	found := filePath == ""
+	err = core.WalkDocs(docs, func(docPath string, doc core.FolderStructure) error {
+		if filePath != "" && docPath != filePath && !strings.HasPrefix(docPath, filePath+"/") {
+			return nil
+		}
+		found = true
+
+		content, err := core.GetDoc(docPath)
+		if err != nil {
+			return err
+		}
+
+		for _, component := range core.ParseMDXComponents(content, core.ComponentHttpRequest) {
+			request := ParseComponent(component)
+			result := TestResult{
+				FilePath: docPath,
+				Title:    doc.Title,
+				Line:     component.Line,
+				Name:     strings.TrimSpace(request.Method + " " + request.URL),
+				Failures: []string{},
+			}
+
+			assertions, err := ParseAssertions(component)
+			switch {
+			case request.URL == "":
+				result.Skipped = "no URL"
+			case err != nil:
+				result.Error = err.Error()
+			default:
+				start := time.Now()
+				response, err := executor.Execute(ctx, request)
+				result.Duration = time.Since(start)
+				if err != nil {
+					result.Error = err.Error()
+				} else {
+					result.Status = response.Status
+					result.Failures = assertions.Check(response)
+				}
+			}
+			results = append(results, result)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("doc %s not found", filePath)
+	}
+
+	return results, nil
+}
+
+// JUnit report elements, in the layout CI servers read
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Errors   int             `xml:"errors,attr"`
+	Skipped  int             `xml:"skipped,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// junitSeconds formats a duration the way JUnit reports expect
+func junitSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+// JUnitReport renders results as JUnit XML with one test suite per doc
+func JUnitReport(results []TestResult) ([]byte, error) {
+	report := junitTestSuites{Name: "doclific"}
+	var total time.Duration
+	suites := map[string]int{}
+	durations := []time.Duration{}
+
+	for _, result := range results {
+		i, ok := suites[result.FilePath]
+		if !ok {
+			i = len(report.Suites)
+			suites[result.FilePath] = i
+			report.Suites = append(report.Suites, junitTestSuite{Name: fmt.Sprintf("%s (%s)", result.Title, result.FilePath)})
+			durations = append(durations, 0)
+		}
+		suite := &report.Suites[i]
+
+		testCase := junitTestCase{
+			Name:      fmt.Sprintf("%s (line %d)", result.Name, result.Line),
+			ClassName: result.FilePath,
+			Time:      junitSeconds(result.Duration),
+		}
+		switch {
+		case result.Skipped != "":
+			testCase.Skipped = &junitMessage{Message: result.Skipped}
+			suite.Skipped++
+		case result.Error != "":
+			testCase.Error = &junitMessage{Message: result.Error}
+			suite.Errors++
+		case len(result.Failures) > 0:
+			testCase.Failure = &junitMessage{Message: result.Failures[0], Text: strings.Join(result.Failures, "\n")}
+			suite.Failures++
+		}
+		suite.Cases = append(suite.Cases, testCase)
+		suite.Tests++
+
+		durations[i] += result.Duration
+		total += result.Duration
+	}
+
+	for i := range report.Suites {
+		suite := &report.Suites[i]
+		suite.Time = junitSeconds(durations[i])
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Errors += suite.Errors
+		report.Skipped += suite.Skipped
+	}
+	report.Time = junitSeconds(total)
+
+	data, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to render JUnit report: %w", err)
+	}
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
@@ -0,0 +1,97 @@
+package httpreq
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunDocs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		w.Write([]byte(`{"path":"` + r.URL.Path + `"}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"doclific/api/config.json": `{"title": "API", "order": 0}`,
+		"doclific/api/content.mdx": `# API
+
+<HttpRequest method="GET" url="{{baseUrl}}/users" expectStatus="200" expectJson='{"$.path": "/users"}' />
+
+<HttpRequest method="GET" url="{{baseUrl}}/missing" />
+`,
+		"doclific/api/orders/config.json": `{"title": "Orders", "order": 0}`,
+		"doclific/api/orders/content.mdx": `<HttpRequest method="POST" url="" />
+
+<HttpRequest method="GET" url="{{baseUrl}}/orders" expectJson='{"$.path": "/nope"}' />
+`,
+		"doclific/other/config.json": `{"title": "Other", "order": 1}`,
+		"doclific/other/content.mdx": `<HttpRequest method="GET" url="{{undefined}}/x" />`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wd, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	executor := &Executor{AllowedHosts: DefaultAllowedHosts, Variables: map[string]string{"baseUrl": server.URL}}
+	results, err := RunDocs(context.Background(), "api", executor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 4 {
+		t.Fatalf("RunDocs(api) ran %d blocks, want the doc and its children: %+v", len(results), results)
+	}
+	if !results[0].Passed() || results[0].Name != "GET {{baseUrl}}/users" || results[0].Line != 3 {
+		t.Errorf("results[0] = %+v", results[0])
+	}
+	if results[1].Passed() || results[1].Failures[0] != "status 404, want below 400" {
+		t.Errorf("results[1] = %+v", results[1])
+	}
+	if results[2].Skipped != "no URL" || results[3].FilePath != "api/orders" || len(results[3].Failures) != 1 {
+		t.Errorf("results[2:] = %+v", results[2:])
+	}
+
+	results, err = RunDocs(context.Background(), "", executor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last := results[len(results)-1]; last.FilePath != "other" || !strings.Contains(last.Error, "undefined variables undefined") {
+		t.Errorf("RunDocs() last result = %+v", last)
+	}
+	if _, err := RunDocs(context.Background(), "nope", executor); err == nil {
+		t.Error("RunDocs() should fail for an unknown doc")
+	}
+
+	report, err := JUnitReport(results)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var parsed junitTestSuites
+	if err := xml.Unmarshal(report, &parsed); err != nil {
+		t.Fatalf("JUnitReport() is not valid XML: %v\n%s", err, report)
+	}
+	if parsed.Tests != 5 || parsed.Failures != 2 || parsed.Errors != 1 || parsed.Skipped != 1 || len(parsed.Suites) != 3 {
+		t.Errorf("JUnitReport() totals = %+v", parsed)
+	}
+	if !strings.Contains(string(report), `<testcase name="GET {{baseUrl}}/users (line 3)" classname="api"`) {
+		t.Errorf("JUnitReport() =\n%s", report)
+	}
+}
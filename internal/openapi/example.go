@@ -0,0 +1,208 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// maxExampleDepth stops example generation for deeply nested schemas
+const maxExampleDepth = 8
+
+// object is a JSON object that keeps its keys in schema order when marshaled
+type object struct {
+	keys   []string
+	values map[string]any
+}
+
+// MarshalJSON writes the keys in order
+func (o *object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(o.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// explicitExample returns the example, default or first enum value a schema declares
+func explicitExample(schema *Schema) (any, bool) {
+	switch {
+	case schema == nil:
+		return nil, false
+	case schema.Example != nil:
+		return schema.Example, true
+	case len(schema.Examples) > 0:
+		return schema.Examples[0], true
+	case schema.Default != nil:
+		return schema.Default, true
+	case len(schema.Enum) > 0:
+		return schema.Enum[0], true
+	}
+	return nil, false
+}
+
+// Example builds an example value for a schema from its examples, or from its types when there are none
+// A schema that refers back to itself is left out the second time, so recursive types stay small
+func (s *Spec) Example(schema *Schema) any {
+	return s.example(schema, 0, map[string]bool{})
+}
+
+// example builds an example; expanding holds the $refs being expanded on the way down
+func (s *Spec) example(schema *Schema, depth int, expanding map[string]bool) any {
+	if schema != nil && schema.Ref != "" {
+		if expanding[schema.Ref] {
+			return nil
+		}
+		expanding[schema.Ref] = true
+		defer delete(expanding, schema.Ref)
+	}
+	schema = s.Schema(schema)
+	if schema == nil || depth > maxExampleDepth {
+		return nil
+	}
+	if value, ok := explicitExample(schema); ok {
+		return value
+	}
+
+	if len(schema.AllOf) > 0 {
+		merged := &object{values: map[string]any{}}
+		for _, part := range schema.AllOf {
+			if partObject, ok := s.example(part, depth+1, expanding).(*object); ok {
+				for _, key := range partObject.keys {
+					if _, exists := merged.values[key]; !exists {
+						merged.keys = append(merged.keys, key)
+					}
+					merged.values[key] = partObject.values[key]
+				}
+			}
+		}
+		return merged
+	}
+	if len(schema.OneOf) > 0 {
+		return s.example(schema.OneOf[0], depth+1, expanding)
+	}
+	if len(schema.AnyOf) > 0 {
+		return s.example(schema.AnyOf[0], depth+1, expanding)
+	}
+
+	switch schema.Type {
+	case "string":
+		switch schema.Format {
+		case "date-time":
+			return "2024-01-01T00:00:00Z"
+		case "date":
+			return "2024-01-01"
+		case "email":
+			return "user@example.com"
+		case "uuid":
+			return "00000000-0000-0000-0000-000000000000"
+		case "uri", "url":
+			return "https://example.com"
+		}
+		return "string"
+	case "integer", "number":
+		return 0
+	case "boolean":
+		return false
+	case "array":
+		if item := s.example(schema.Items, depth+1, expanding); item != nil {
+			return []any{item}
+		}
+		return []any{}
+	}
+
+	if schema.Type == "object" || len(schema.Properties) > 0 {
+		result := &object{values: map[string]any{}}
+		for _, name := range schema.PropertyNames() {
+			value := s.example(schema.Properties[name], depth+1, expanding)
+			if value == nil {
+				continue // a recursive reference or a schema without a type
+			}
+			result.keys = append(result.keys, name)
+			result.values[name] = value
+		}
+		return result
+	}
+	return nil
+}
+
+// mediaTypeExample returns the example of a request or response body, preferring the ones written in the spec
+func (s *Spec) mediaTypeExample(media *MediaType) any {
+	if media.Example != nil {
+		return media.Example
+	}
+	for _, name := range sortedKeys(media.Examples) {
+		if value := media.Examples[name].Value; value != nil {
+			return value
+		}
+	}
+	return s.Example(media.Schema)
+}
+
+// exampleString formats a parameter or form field example as it would appear in a URL or form
+func exampleString(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool, int, int64, float64:
+		return fmt.Sprint(v)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// prettyJSON formats an example body the way the editor's Format button does
+func prettyJSON(value any) string {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// TypeLabel describes a schema for a parameter table, such as "string (uuid)", "User" or "array of integer"
+func (s *Spec) TypeLabel(schema *Schema) string {
+	if schema == nil {
+		return ""
+	}
+	if schema.Ref != "" {
+		return RefName(schema.Ref)
+	}
+	switch {
+	case schema.Type == "array":
+		if item := s.TypeLabel(schema.Items); item != "" {
+			return "array of " + item
+		}
+		return "array"
+	case len(schema.OneOf) > 0 || len(schema.AnyOf) > 0:
+		var labels []string
+		for _, option := range append(append([]*Schema{}, schema.OneOf...), schema.AnyOf...) {
+			labels = append(labels, s.TypeLabel(option))
+		}
+		return strings.Join(labels, " or ")
+	case schema.Format != "":
+		return fmt.Sprintf("%s (%s)", schema.Type, schema.Format)
+	}
+	return string(schema.Type)
+}
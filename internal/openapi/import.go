@@ -0,0 +1,477 @@
+package openapi
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"doclific/internal/core"
+	"doclific/internal/httpreq"
+)
+
+// Page layouts for Import
+const (
+	PerTag       = "tag"       // one page per tag holding each of its operations
+	PerOperation = "operation" // a page per tag with a child page per operation
+)
+
+// untaggedTitle is the page for operations without a tag
+const untaggedTitle = "Other"
+
+// ImportOptions configures Import
+type ImportOptions struct {
+	SpecPath   string
+	ParentPath string // doc folder path to import under; empty imports at the root
+	Per        string // PerTag (default) or PerOperation
+	BaseURL    string // prefix of request URLs; defaults to the first server, or {{baseUrl}} when it is not absolute
+}
+
+// ImportResult summarizes an Import run
+type ImportResult struct {
+	Docs     []core.ImportedDoc `json:"docs"`
+	Requests int                `json:"requests"`
+}
+
+var (
+	pathParamRegex = regexp.MustCompile(`\{([^{}]+)\}`)
+	mdxEscaper     = strings.NewReplacer("{", `\{`, "}", `\}`, "<", `\<`)
+	cellEscaper    = strings.NewReplacer("|", `\|`, "\r\n", " ", "\n", " ")
+)
+
+// sortedKeys returns the keys of a map in order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// mdxText escapes spec prose so braces and angle brackets are not read as MDX expressions or tags
+func mdxText(text string) string {
+	return mdxEscaper.Replace(strings.TrimSpace(text))
+}
+
+// cell formats text for a markdown table cell
+func cell(text string) string {
+	return cellEscaper.Replace(mdxText(text))
+}
+
+// Import generates a doc subtree from an OpenAPI spec: a root page for the API, then pages per tag or
+// operation holding descriptions, parameter tables and a prefilled <HttpRequest> for every operation
+func Import(options ImportOptions) (*ImportResult, error) {
+	spec, err := Load(options.SpecPath)
+	if err != nil {
+		return nil, err
+	}
+	root, requests, err := spec.Pages(options)
+	if err != nil {
+		return nil, err
+	}
+
+	docs, err := core.ImportTree(options.ParentPath, []core.ImportNode{root})
+	if err != nil {
+		return nil, err
+	}
+	return &ImportResult{Docs: docs, Requests: requests}, nil
+}
+
+// Pages builds the doc tree for the spec and counts the requests in it
+func (s *Spec) Pages(options ImportOptions) (core.ImportNode, int, error) {
+	per := options.Per
+	if per == "" {
+		per = PerTag
+	}
+	if per != PerTag && per != PerOperation {
+		return core.ImportNode{}, 0, fmt.Errorf("unknown page layout %q (use %s or %s)", per, PerTag, PerOperation)
+	}
+
+	operations, err := s.Operations()
+	if err != nil {
+		return core.ImportNode{}, 0, err
+	}
+	if len(operations) == 0 {
+		return core.ImportNode{}, 0, fmt.Errorf("no operations found in spec")
+	}
+
+	baseURL := options.BaseURL
+	if baseURL == "" {
+		baseURL = s.BaseURL()
+		if !strings.HasPrefix(baseURL, "http://") && !strings.HasPrefix(baseURL, "https://") {
+			baseURL = "{{baseUrl}}" + baseURL
+		}
+	}
+
+	title := strings.TrimSpace(s.Info.Title)
+	if title == "" {
+		title = "API"
+	}
+	root := core.ImportNode{Title: title, Content: s.overview(title, baseURL), Source: options.SpecPath}
+
+	// Tags keep the order the spec declares them in, then the order operations first use them
+	tags := map[string]*core.ImportNode{}
+	var order []string
+	tagPage := func(name string) *core.ImportNode {
+		if page, ok := tags[name]; ok {
+			return page
+		}
+		page := &core.ImportNode{Title: name}
+		for _, tag := range s.Tags {
+			if tag.Name == name && strings.TrimSpace(tag.Description) != "" {
+				page.Content = fmt.Sprintf("# %s\n\n%s\n", mdxText(name), mdxText(tag.Description))
+			}
+		}
+		tags[name] = page
+		order = append(order, name)
+		return page
+	}
+	for _, tag := range s.Tags {
+		tagPage(tag.Name)
+	}
+
+	for _, operation := range operations {
+		tag := untaggedTitle
+		if len(operation.Tags) > 0 {
+			tag = operation.Tags[0]
+		}
+		page := tagPage(tag)
+
+		if per == PerTag {
+			if page.Content == "" {
+				page.Content = fmt.Sprintf("# %s\n", mdxText(tag))
+			}
+			section, err := s.operationSection(operation, baseURL, "##")
+			if err != nil {
+				return core.ImportNode{}, 0, err
+			}
+			page.Content += "\n" + section
+			continue
+		}
+
+		section, err := s.operationSection(operation, baseURL, "#")
+		if err != nil {
+			return core.ImportNode{}, 0, err
+		}
+		page.Children = append(page.Children, core.ImportNode{Title: operationTitle(operation), Content: section})
+	}
+
+	for _, name := range order {
+		page := tags[name]
+		if page.Content == "" && len(page.Children) == 0 {
+			continue // declared but unused
+		}
+		root.Children = append(root.Children, *page)
+	}
+	return root, len(operations), nil
+}
+
+// overview is the content of the API's root page
+func (s *Spec) overview(title, baseURL string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", mdxText(title))
+	if s.Info.Version != "" {
+		fmt.Fprintf(&b, "Version `%s`\n\n", s.Info.Version)
+	}
+	if description := mdxText(s.Info.Description); description != "" {
+		fmt.Fprintf(&b, "%s\n\n", description)
+	}
+
+	if len(s.Servers) > 0 {
+		b.WriteString("## Servers\n\n")
+		for _, server := range s.Servers {
+			fmt.Fprintf(&b, "-   `%s`", server.URL)
+			if server.Description != "" {
+				fmt.Fprintf(&b, ": %s", mdxText(server.Description))
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+	if strings.Contains(baseURL, "{{baseUrl}}") {
+		fmt.Fprintf(&b, "Requests use the `{{baseUrl}}` variable; set it in an environment under `doclific/%s/`.\n\n", httpreq.EnvironmentsDirName)
+	}
+
+	if len(s.Components.SecuritySchemes) > 0 {
+		b.WriteString("## Authentication\n\n")
+		for _, name := range sortedKeys(s.Components.SecuritySchemes) {
+			scheme := s.SecurityScheme(name)
+			if scheme == nil {
+				continue
+			}
+			fmt.Fprintf(&b, "-   `%s`: %s", name, schemeLabel(scheme))
+			if scheme.Description != "" {
+				fmt.Fprintf(&b, ". %s", mdxText(scheme.Description))
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// schemeLabel describes a security scheme in a few words
+func schemeLabel(scheme *SecurityScheme) string {
+	switch scheme.Type {
+	case "http":
+		return fmt.Sprintf("HTTP %s", scheme.Scheme)
+	case "apiKey":
+		return fmt.Sprintf("API key in the `%s` %s", scheme.Name, scheme.In)
+	case "oauth2":
+		return "OAuth 2.0"
+	case "openIdConnect":
+		return "OpenID Connect"
+	}
+	return scheme.Type
+}
+
+// operationTitle names an operation by its summary, then its ID, then its method and path
+func operationTitle(operation *Operation) string {
+	if summary := strings.TrimSpace(operation.Summary); summary != "" {
+		return summary
+	}
+	if operation.OperationID != "" {
+		return operation.OperationID
+	}
+	return operation.Method + " " + operation.Path
+}
+
+// operationSection documents one operation under a heading of the given level
+func (s *Spec) operationSection(operation *Operation, baseURL string, heading string) (string, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s\n\n", heading, mdxText(operationTitle(operation)))
+	fmt.Fprintf(&b, "`%s %s`\n\n", operation.Method, operation.Path)
+	if operation.Deprecated {
+		b.WriteString("> **Deprecated**\n\n")
+	}
+	if description := mdxText(operation.Description); description != "" {
+		fmt.Fprintf(&b, "%s\n\n", description)
+	}
+
+	if len(operation.Parameters) > 0 {
+		fmt.Fprintf(&b, "%s# Parameters\n\n", heading)
+		b.WriteString("| Name | In | Type | Required | Description |\n| --- | --- | --- | --- | --- |\n")
+		for _, parameter := range operation.Parameters {
+			required := "No"
+			if parameter.Required {
+				required = "Yes"
+			}
+			fmt.Fprintf(&b, "| `%s` | %s | %s | %s | %s |\n", parameter.Name, parameter.In, cell(s.TypeLabel(parameter.Schema)), required, cell(parameter.Description))
+		}
+		b.WriteString("\n")
+	}
+
+	if body := operation.RequestBody; body != nil && len(body.Content) > 0 {
+		fmt.Fprintf(&b, "%s# Request body\n\n", heading)
+		for _, contentType := range sortedKeys(body.Content) {
+			fmt.Fprintf(&b, "-   `%s`", contentType)
+			if label := s.TypeLabel(body.Content[contentType].Schema); label != "" {
+				fmt.Fprintf(&b, ": %s", cell(label))
+			}
+			b.WriteString("\n")
+		}
+		if body.Required {
+			b.WriteString("\nThe body is required.")
+		}
+		if description := mdxText(body.Description); description != "" {
+			fmt.Fprintf(&b, "\n%s", description)
+		}
+		b.WriteString("\n\n")
+	}
+
+	if len(operation.Responses) > 0 {
+		fmt.Fprintf(&b, "%s# Responses\n\n", heading)
+		b.WriteString("| Status | Description |\n| --- | --- |\n")
+		for _, code := range responseCodes(operation.Responses) {
+			description := ""
+			if response := operation.Responses[code]; response != nil {
+				description = response.Description
+			}
+			fmt.Fprintf(&b, "| `%s` | %s |\n", code, cell(description))
+		}
+		b.WriteString("\n")
+	}
+
+	mdx, err := s.Block(operation, baseURL).MDX()
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprintf(&b, "%s\n", mdx)
+	return b.String(), nil
+}
+
+// responseCodes sorts status codes numerically, with ranges such as 4XX after their codes and default last
+func responseCodes(responses map[string]*Response) []string {
+	codes := sortedKeys(responses)
+	rank := func(code string) string {
+		if code == "default" {
+			return "9"
+		}
+		return strings.ToUpper(code)
+	}
+	sort.SliceStable(codes, func(i, j int) bool {
+		return rank(codes[i]) < rank(codes[j])
+	})
+	return codes
+}
+
+// parameterExample returns the example a parameter or its schema declares
+func (s *Spec) parameterExample(parameter *Parameter) (string, bool) {
+	if parameter.Example != nil {
+		return exampleString(parameter.Example), true
+	}
+	if value, ok := explicitExample(s.Schema(parameter.Schema)); ok {
+		return exampleString(value), true
+	}
+	return "", false
+}
+
+// Block builds the prefilled <HttpRequest> for an operation
+// Path parameters use their example, or a {{name}} variable when the spec has none
+func (s *Spec) Block(operation *Operation, baseURL string) httpreq.Block {
+	request := httpreq.Request{
+		Method:      operation.Method,
+		Headers:     []httpreq.KeyValue{},
+		QueryParams: []httpreq.KeyValue{},
+		FormData:    []httpreq.KeyValue{},
+		BodyType:    "none",
+		Auth:        s.auth(operation),
+	}
+
+	pathValues := map[string]string{}
+	for _, parameter := range operation.Parameters {
+		example, hasExample := s.parameterExample(parameter)
+		switch parameter.In {
+		case "path":
+			if hasExample {
+				pathValues[parameter.Name] = url.PathEscape(example)
+			}
+		case "query":
+			request.QueryParams = append(request.QueryParams, httpreq.KeyValue{Key: parameter.Name, Value: example, Enabled: parameter.Required})
+		case "header":
+			// OpenAPI ignores header parameters that describe content negotiation and auth
+			switch strings.ToLower(parameter.Name) {
+			case "accept", "content-type", "authorization":
+				continue
+			}
+			request.Headers = append(request.Headers, httpreq.KeyValue{Key: parameter.Name, Value: example, Enabled: parameter.Required})
+		}
+	}
+	request.URL = baseURL + pathParamRegex.ReplaceAllStringFunc(operation.Path, func(match string) string {
+		name := match[1 : len(match)-1]
+		if value, ok := pathValues[name]; ok {
+			return value
+		}
+		return "{{" + name + "}}"
+	})
+
+	if body := operation.RequestBody; body != nil {
+		s.fillBody(&request, body)
+	}
+
+	block := httpreq.Block{Request: request}
+	block.Assertions.Status = successStatus(operation.Responses)
+	return block
+}
+
+// fillBody sets the request body from the first supported media type, preferring JSON
+func (s *Spec) fillBody(request *httpreq.Request, body *RequestBody) {
+	contentTypes := sortedKeys(body.Content)
+	sort.SliceStable(contentTypes, func(i, j int) bool {
+		return strings.Contains(contentTypes[i], "json") && !strings.Contains(contentTypes[j], "json")
+	})
+
+	for _, contentType := range contentTypes {
+		media := body.Content[contentType]
+		if media == nil {
+			continue
+		}
+		mediaType := strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+
+		switch {
+		case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+			request.BodyType = "json"
+			if example := s.mediaTypeExample(media); example != nil {
+				request.BodyContent = prettyJSON(example)
+			}
+			if mediaType != "application/json" {
+				request.Headers = append(request.Headers, httpreq.KeyValue{Key: "Content-Type", Value: mediaType, Enabled: true})
+			}
+		case mediaType == "application/x-www-form-urlencoded", mediaType == "multipart/form-data":
+			request.BodyType = "x-www-form-urlencoded"
+			if mediaType == "multipart/form-data" {
+				request.BodyType = "form-data"
+			}
+			schema := s.Schema(media.Schema)
+			if schema == nil {
+				continue
+			}
+			required := map[string]bool{}
+			for _, name := range schema.Required {
+				required[name] = true
+			}
+			for _, name := range schema.PropertyNames() {
+				request.FormData = append(request.FormData, httpreq.KeyValue{
+					Key:     name,
+					Value:   exampleString(s.Example(schema.Properties[name])),
+					Enabled: required[name] || len(schema.Required) == 0,
+				})
+			}
+		default:
+			request.BodyType = "raw"
+			if example, ok := s.mediaTypeExample(media).(string); ok {
+				request.BodyContent = example
+			}
+			request.Headers = append(request.Headers, httpreq.KeyValue{Key: "Content-Type", Value: mediaType, Enabled: true})
+		}
+		return
+	}
+}
+
+// auth prefills the block's auth from the operation's (or the spec's) first security requirement
+// Credentials are {{variables}} so they can live in an environment's secrets instead of the doc
+func (s *Spec) auth(operation *Operation) httpreq.Auth {
+	requirements := s.Security
+	if operation.Security != nil {
+		requirements = *operation.Security
+	}
+
+	for _, requirement := range requirements {
+		for _, name := range sortedKeys(requirement) {
+			scheme := s.SecurityScheme(name)
+			if scheme == nil {
+				continue
+			}
+			switch {
+			case scheme.Type == "http" && strings.EqualFold(scheme.Scheme, "basic"):
+				return httpreq.Auth{Type: "basic", Username: "{{username}}", Password: "{{password}}"}
+			case scheme.Type == "http" && strings.EqualFold(scheme.Scheme, "bearer"),
+				scheme.Type == "oauth2", scheme.Type == "openIdConnect":
+				return httpreq.Auth{Type: "bearer", Token: "{{token}}"}
+			case scheme.Type == "apiKey" && (scheme.In == "header" || scheme.In == "query"):
+				return httpreq.Auth{Type: "apikey", APIKeyName: scheme.Name, APIKeyValue: "{{apiKey}}", APIKeyLocation: scheme.In}
+			}
+		}
+	}
+	return httpreq.Auth{Type: "none"}
+}
+
+// successStatus returns the lowest documented 2xx status, or 2xx for a 2XX range
+func successStatus(responses map[string]*Response) string {
+	best := 0
+	for code := range responses {
+		if n, err := strconv.Atoi(code); err == nil && n >= 200 && n < 300 && (best == 0 || n < best) {
+			best = n
+		}
+	}
+	if best != 0 {
+		return strconv.Itoa(best)
+	}
+	if _, ok := responses["2XX"]; ok {
+		return "2xx"
+	}
+	return ""
+}
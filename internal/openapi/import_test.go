@@ -0,0 +1,208 @@
+package openapi
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"doclific/internal/core"
+	"doclific/internal/httpreq"
+)
+
+const testSpec = `
+openapi: 3.1.0
+info:
+  title: Shop
+  version: "2.0"
+servers:
+  - url: /api
+tags:
+  - name: users
+    description: People with {accounts}
+security:
+  - bearer: []
+paths:
+  /users/{id}/orders/{orderId}:
+    parameters:
+      - $ref: '#/components/parameters/UserId'
+    get:
+      tags: [users]
+      summary: Get an order
+      parameters:
+        - {name: expand, in: query, schema: {type: [string, "null"], enum: [items]}}
+        - {name: Accept, in: header, schema: {type: string}}
+        - {name: X-Trace, in: header, required: true, example: abc}
+      responses:
+        '404': {description: Not found}
+        '200': {description: The order}
+  /users:
+    post:
+      tags: [users]
+      operationId: createUser
+      security: []
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/User'}
+      responses:
+        '2XX': {description: Created}
+    put:
+      security:
+        - key: []
+      requestBody:
+        content:
+          application/x-www-form-urlencoded:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name: {type: string, example: Ada}
+                age: {type: integer}
+      responses:
+        default: {description: Anything}
+components:
+  parameters:
+    UserId: {name: id, in: path, required: true, schema: {type: integer, example: 7}}
+  schemas:
+    User:
+      type: object
+      properties:
+        name: {type: string}
+        manager: {$ref: '#/components/schemas/User'}
+        joined: {type: string, format: date-time}
+  securitySchemes:
+    bearer: {type: http, scheme: bearer}
+    key: {type: apiKey, name: api_key, in: query}
+`
+
+func parseTestSpec(t *testing.T) *Spec {
+	t.Helper()
+	spec, err := Parse([]byte(testSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return spec
+}
+
+func TestOperations(t *testing.T) {
+	operations, err := parseTestSpec(t).Operations()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, operation := range operations {
+		names = append(names, operation.Method+" "+operation.Path)
+	}
+	if got := strings.Join(names, ", "); got != "GET /users/{id}/orders/{orderId}, POST /users, PUT /users" {
+		t.Errorf("Operations() = %s, want spec order", got)
+	}
+	if parameters := operations[0].Parameters; len(parameters) != 4 || parameters[0].Name != "id" || parameters[0].In != "path" {
+		t.Errorf("Operations() should merge the path-level $ref parameter first, got %+v", parameters)
+	}
+	if operations[0].Parameters[1].Schema.Type != "string" {
+		t.Errorf("a 3.1 type list should keep its non-null type, got %q", operations[0].Parameters[1].Schema.Type)
+	}
+
+	if _, err := Parse([]byte("swagger: '2.0'\ninfo: {title: Old}\n")); err == nil || !strings.Contains(err.Error(), "swagger 2.0") {
+		t.Errorf("Parse() of a Swagger 2.0 spec error = %v", err)
+	}
+}
+
+func TestBlock(t *testing.T) {
+	spec := parseTestSpec(t)
+	operations, _ := spec.Operations()
+
+	get := spec.Block(operations[0], "{{baseUrl}}/api")
+	if get.URL != "{{baseUrl}}/api/users/7/orders/{{orderId}}" {
+		t.Errorf("GET url = %q, want the example and a variable for the undeclared parameter", get.URL)
+	}
+	if len(get.QueryParams) != 1 || get.QueryParams[0] != (httpreq.KeyValue{Key: "expand", Value: "items"}) {
+		t.Errorf("GET queryParams = %+v", get.QueryParams)
+	}
+	if len(get.Headers) != 1 || get.Headers[0] != (httpreq.KeyValue{Key: "X-Trace", Value: "abc", Enabled: true}) {
+		t.Errorf("GET headers = %+v, want Accept dropped", get.Headers)
+	}
+	if get.Auth.Type != "bearer" || get.Auth.Token != "{{token}}" || get.Assertions.Status != "200" {
+		t.Errorf("GET auth = %+v, expectStatus = %q", get.Auth, get.Assertions.Status)
+	}
+
+	post := spec.Block(operations[1], "http://localhost")
+	if post.Auth.Type != "none" || post.BodyType != "json" || post.Assertions.Status != "2xx" {
+		t.Errorf("POST = %+v", post)
+	}
+	want := "{\n  \"name\": \"string\",\n  \"joined\": \"2024-01-01T00:00:00Z\"\n}"
+	if post.BodyContent != want {
+		t.Errorf("POST body = %s, want properties in spec order without the recursive manager", post.BodyContent)
+	}
+
+	put := spec.Block(operations[2], "http://localhost")
+	if put.BodyType != "x-www-form-urlencoded" || len(put.FormData) != 2 || put.FormData[0] != (httpreq.KeyValue{Key: "name", Value: "Ada", Enabled: true}) || put.FormData[1].Enabled {
+		t.Errorf("PUT form = %+v", put.FormData)
+	}
+	if put.Auth.Type != "apikey" || put.Auth.APIKeyLocation != "query" || put.Auth.APIKeyName != "api_key" || put.Assertions.Status != "" {
+		t.Errorf("PUT auth = %+v, expectStatus = %q", put.Auth, put.Assertions.Status)
+	}
+
+	// The block reads back as the same request
+	mdx, err := get.MDX()
+	if err != nil {
+		t.Fatal(err)
+	}
+	components := core.ParseMDXComponents(mdx, core.ComponentHttpRequest)
+	if len(components) != 1 {
+		t.Fatalf("MDX() = %s", mdx)
+	}
+	parsed := httpreq.ParseComponent(components[0])
+	original, _ := json.Marshal(get.Request)
+	roundTripped, _ := json.Marshal(parsed)
+	if string(original) != string(roundTripped) || components[0].Attributes["expectStatus"] != "200" {
+		t.Errorf("MDX() round trip = %s, want %s", roundTripped, original)
+	}
+}
+
+func TestPages(t *testing.T) {
+	spec := parseTestSpec(t)
+
+	root, requests, err := spec.Pages(ImportOptions{SpecPath: "shop.yaml"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requests != 3 || root.Title != "Shop" || len(root.Children) != 2 || root.Children[0].Title != "users" || root.Children[1].Title != untaggedTitle {
+		t.Fatalf("Pages() = %d requests, %+v", requests, root)
+	}
+	if !strings.Contains(root.Content, "Version `2.0`") || !strings.Contains(root.Content, "`{{baseUrl}}` variable") {
+		t.Errorf("overview = %s", root.Content)
+	}
+
+	users := root.Children[0].Content
+	for _, want := range []string{
+		"# users\n\nPeople with \\{accounts\\}\n",
+		"## Get an order\n\n`GET /users/{id}/orders/{orderId}`",
+		"| `id` | path | integer | Yes |  |",
+		"| `expand` | query | string | No |  |",
+		"| `200` | The order |\n| `404` | Not found |",
+		"## createUser",
+		`url="{{baseUrl}}/api/users/7/orders/{{orderId}}"`,
+	} {
+		if !strings.Contains(users, want) {
+			t.Errorf("users page missing %q:\n%s", want, users)
+		}
+	}
+
+	root, _, err = spec.Pages(ImportOptions{Per: PerOperation, BaseURL: "https://shop.test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	operationPages := root.Children[0].Children
+	if len(operationPages) != 2 || operationPages[0].Title != "Get an order" || !strings.HasPrefix(operationPages[0].Content, "# Get an order\n") {
+		t.Errorf("Pages(operation) users = %+v", operationPages)
+	}
+	if !strings.Contains(operationPages[1].Content, `url="https://shop.test/users"`) {
+		t.Errorf("Pages() should use --base-url:\n%s", operationPages[1].Content)
+	}
+
+	if _, _, err := spec.Pages(ImportOptions{Per: "path"}); err == nil {
+		t.Error("Pages() should reject unknown layouts")
+	}
+}
@@ -0,0 +1,387 @@
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// maxRefDepth bounds $ref chains so a reference cycle cannot loop forever
+const maxRefDepth = 16
+
+// Spec is the subset of an OpenAPI 3 document used to generate docs
+type Spec struct {
+	OpenAPI    string                `yaml:"openapi"`
+	Swagger    string                `yaml:"swagger"`
+	Info       Info                  `yaml:"info"`
+	Servers    []Server              `yaml:"servers"`
+	Paths      yaml.Node             `yaml:"paths"` // decoded by Operations to keep the spec's path order
+	Tags       []Tag                 `yaml:"tags"`
+	Components Components            `yaml:"components"`
+	Security   []SecurityRequirement `yaml:"security"`
+}
+
+// Info is the API title, version and description
+type Info struct {
+	Title       string `yaml:"title"`
+	Version     string `yaml:"version"`
+	Description string `yaml:"description"`
+}
+
+// Server is a base URL of the API, possibly templated with {variables}
+type Server struct {
+	URL         string `yaml:"url"`
+	Description string `yaml:"description"`
+	Variables   map[string]struct {
+		Default string `yaml:"default"`
+	} `yaml:"variables"`
+}
+
+// Tag groups operations
+type Tag struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+}
+
+// Components holds the reusable objects $refs point at
+type Components struct {
+	Schemas         map[string]*Schema         `yaml:"schemas"`
+	Parameters      map[string]*Parameter      `yaml:"parameters"`
+	RequestBodies   map[string]*RequestBody    `yaml:"requestBodies"`
+	Responses       map[string]*Response       `yaml:"responses"`
+	SecuritySchemes map[string]*SecurityScheme `yaml:"securitySchemes"`
+}
+
+// SecurityRequirement maps security scheme names to scopes; all schemes of one requirement apply together
+type SecurityRequirement map[string][]string
+
+// SecurityScheme is a way of authenticating: http (basic, bearer), apiKey, oauth2 or openIdConnect
+type SecurityScheme struct {
+	Ref          string `yaml:"$ref"`
+	Type         string `yaml:"type"`
+	Description  string `yaml:"description"`
+	Scheme       string `yaml:"scheme"`
+	BearerFormat string `yaml:"bearerFormat"`
+	Name         string `yaml:"name"`
+	In           string `yaml:"in"`
+}
+
+// Operation is one method of a path
+type Operation struct {
+	Method      string                 `yaml:"-"`
+	Path        string                 `yaml:"-"`
+	OperationID string                 `yaml:"operationId"`
+	Summary     string                 `yaml:"summary"`
+	Description string                 `yaml:"description"`
+	Tags        []string               `yaml:"tags"`
+	Deprecated  bool                   `yaml:"deprecated"`
+	Parameters  []*Parameter           `yaml:"parameters"`
+	RequestBody *RequestBody           `yaml:"requestBody"`
+	Responses   map[string]*Response   `yaml:"responses"`
+	Security    *[]SecurityRequirement `yaml:"security"` // nil inherits the spec's security; an empty list turns it off
+}
+
+// Parameter is a path, query, header or cookie parameter
+type Parameter struct {
+	Ref         string  `yaml:"$ref"`
+	Name        string  `yaml:"name"`
+	In          string  `yaml:"in"`
+	Description string  `yaml:"description"`
+	Required    bool    `yaml:"required"`
+	Deprecated  bool    `yaml:"deprecated"`
+	Schema      *Schema `yaml:"schema"`
+	Example     any     `yaml:"example"`
+}
+
+// RequestBody is the body an operation accepts, by media type
+type RequestBody struct {
+	Ref         string                `yaml:"$ref"`
+	Description string                `yaml:"description"`
+	Required    bool                  `yaml:"required"`
+	Content     map[string]*MediaType `yaml:"content"`
+}
+
+// Response is a documented response of an operation
+type Response struct {
+	Ref         string                `yaml:"$ref"`
+	Description string                `yaml:"description"`
+	Content     map[string]*MediaType `yaml:"content"`
+}
+
+// MediaType is the schema and examples of a body in one content type
+type MediaType struct {
+	Schema   *Schema `yaml:"schema"`
+	Example  any     `yaml:"example"`
+	Examples map[string]struct {
+		Value any `yaml:"value"`
+	} `yaml:"examples"`
+}
+
+// Schema is the subset of JSON Schema used to describe values and build examples
+type Schema struct {
+	Ref         string             `yaml:"$ref"`
+	Type        SchemaType         `yaml:"type"`
+	Format      string             `yaml:"format"`
+	Description string             `yaml:"description"`
+	Properties  map[string]*Schema `yaml:"properties"`
+	Required    []string           `yaml:"required"`
+	Items       *Schema            `yaml:"items"`
+	Enum        []any              `yaml:"enum"`
+	Example     any                `yaml:"example"`
+	Examples    []any              `yaml:"examples"`
+	Default     any                `yaml:"default"`
+	AllOf       []*Schema          `yaml:"allOf"`
+	OneOf       []*Schema          `yaml:"oneOf"`
+	AnyOf       []*Schema          `yaml:"anyOf"`
+
+	propertyOrder []string // property names in the order the spec lists them
+}
+
+// SchemaType is a schema's type; OpenAPI 3.1 lists such as [string, "null"] keep their first non-null type
+type SchemaType string
+
+// UnmarshalYAML accepts both a single type and a list of types
+func (t *SchemaType) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.SequenceNode {
+		var types []string
+		if err := node.Decode(&types); err != nil {
+			return err
+		}
+		for _, name := range types {
+			if name != "null" {
+				*t = SchemaType(name)
+				return nil
+			}
+		}
+		return nil
+	}
+	var name string
+	if err := node.Decode(&name); err != nil {
+		return err
+	}
+	*t = SchemaType(name)
+	return nil
+}
+
+// UnmarshalYAML decodes a schema and remembers the order of its properties
+func (s *Schema) UnmarshalYAML(node *yaml.Node) error {
+	type plain Schema
+	if err := node.Decode((*plain)(s)); err != nil {
+		return err
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value != "properties" {
+			continue
+		}
+		properties := node.Content[i+1]
+		for j := 0; j+1 < len(properties.Content); j += 2 {
+			s.propertyOrder = append(s.propertyOrder, properties.Content[j].Value)
+		}
+	}
+	return nil
+}
+
+// PropertyNames returns the schema's property names in spec order
+func (s *Schema) PropertyNames() []string {
+	names := append([]string{}, s.propertyOrder...)
+	if len(names) == len(s.Properties) {
+		return names
+	}
+	// Schemas built in code have no recorded order
+	names = names[:0]
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// pathItem is the set of operations on one path
+type pathItem struct {
+	Parameters []*Parameter `yaml:"parameters"`
+	Get        *Operation   `yaml:"get"`
+	Post       *Operation   `yaml:"post"`
+	Put        *Operation   `yaml:"put"`
+	Patch      *Operation   `yaml:"patch"`
+	Delete     *Operation   `yaml:"delete"`
+	Head       *Operation   `yaml:"head"`
+	Options    *Operation   `yaml:"options"`
+}
+
+// Load reads an OpenAPI 3 spec in YAML or JSON
+func Load(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spec: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse parses an OpenAPI 3 spec in YAML or JSON
+func Parse(data []byte) (*Spec, error) {
+	spec := &Spec{}
+	if err := yaml.Unmarshal(data, spec); err != nil {
+		return nil, fmt.Errorf("failed to parse spec: %w", err)
+	}
+	if spec.Swagger != "" {
+		return nil, errors.New("swagger 2.0 specs are not supported; convert the spec to OpenAPI 3 first")
+	}
+	if !strings.HasPrefix(spec.OpenAPI, "3.") {
+		return nil, errors.New("not an OpenAPI 3 spec: missing openapi version")
+	}
+	return spec, nil
+}
+
+// Operations returns every operation in path order, with path-level parameters merged in
+// and $refs to parameters, request bodies and responses resolved
+func (s *Spec) Operations() ([]*Operation, error) {
+	var operations []*Operation
+	if s.Paths.Kind == 0 {
+		return operations, nil
+	}
+	if s.Paths.Kind != yaml.MappingNode {
+		return nil, errors.New("paths must be an object")
+	}
+
+	for i := 0; i+1 < len(s.Paths.Content); i += 2 {
+		path := s.Paths.Content[i].Value
+		var item pathItem
+		if err := s.Paths.Content[i+1].Decode(&item); err != nil {
+			return nil, fmt.Errorf("invalid path %s: %w", path, err)
+		}
+
+		for _, entry := range []struct {
+			method    string
+			operation *Operation
+		}{
+			{"GET", item.Get}, {"POST", item.Post}, {"PUT", item.Put}, {"PATCH", item.Patch},
+			{"DELETE", item.Delete}, {"HEAD", item.Head}, {"OPTIONS", item.Options},
+		} {
+			operation := entry.operation
+			if operation == nil {
+				continue
+			}
+			operation.Method, operation.Path = entry.method, path
+			operation.Parameters = s.mergeParameters(item.Parameters, operation.Parameters)
+			operation.RequestBody = s.requestBody(operation.RequestBody)
+			for code, response := range operation.Responses {
+				operation.Responses[code] = s.response(response)
+			}
+			operations = append(operations, operation)
+		}
+	}
+	return operations, nil
+}
+
+// mergeParameters resolves path-level and operation parameters; an operation parameter replaces
+// a path-level one with the same name and location
+func (s *Spec) mergeParameters(pathParameters, operationParameters []*Parameter) []*Parameter {
+	var merged []*Parameter
+	index := map[string]int{}
+	for _, parameter := range append(append([]*Parameter{}, pathParameters...), operationParameters...) {
+		parameter = s.parameter(parameter)
+		if parameter == nil {
+			continue
+		}
+		key := parameter.In + ":" + parameter.Name
+		if i, ok := index[key]; ok {
+			merged[i] = parameter
+			continue
+		}
+		index[key] = len(merged)
+		merged = append(merged, parameter)
+	}
+	return merged
+}
+
+// componentName returns the name a local #/components/<kind>/ reference points at
+func componentName(ref, kind string) (string, bool) {
+	name, ok := strings.CutPrefix(ref, "#/components/"+kind+"/")
+	if !ok {
+		return "", false
+	}
+	return strings.NewReplacer("~1", "/", "~0", "~").Replace(name), true
+}
+
+// RefName returns the last segment of a $ref, such as User for #/components/schemas/User
+func RefName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+// parameter follows a parameter's $ref; unresolvable references give nil
+func (s *Spec) parameter(p *Parameter) *Parameter {
+	for depth := 0; p != nil && p.Ref != ""; depth++ {
+		name, ok := componentName(p.Ref, "parameters")
+		if !ok || depth == maxRefDepth {
+			return nil
+		}
+		p = s.Components.Parameters[name]
+	}
+	return p
+}
+
+// requestBody follows a request body's $ref
+func (s *Spec) requestBody(b *RequestBody) *RequestBody {
+	for depth := 0; b != nil && b.Ref != ""; depth++ {
+		name, ok := componentName(b.Ref, "requestBodies")
+		if !ok || depth == maxRefDepth {
+			return nil
+		}
+		b = s.Components.RequestBodies[name]
+	}
+	return b
+}
+
+// response follows a response's $ref
+func (s *Spec) response(r *Response) *Response {
+	for depth := 0; r != nil && r.Ref != ""; depth++ {
+		name, ok := componentName(r.Ref, "responses")
+		if !ok || depth == maxRefDepth {
+			return &Response{Description: RefName(r.Ref)}
+		}
+		r = s.Components.Responses[name]
+	}
+	return r
+}
+
+// Schema follows a schema's $ref; unresolvable references give nil
+func (s *Spec) Schema(schema *Schema) *Schema {
+	for depth := 0; schema != nil && schema.Ref != ""; depth++ {
+		name, ok := componentName(schema.Ref, "schemas")
+		if !ok || depth == maxRefDepth {
+			return nil
+		}
+		schema = s.Components.Schemas[name]
+	}
+	return schema
+}
+
+// SecurityScheme follows a security scheme's $ref
+func (s *Spec) SecurityScheme(name string) *SecurityScheme {
+	scheme := s.Components.SecuritySchemes[name]
+	for depth := 0; scheme != nil && scheme.Ref != ""; depth++ {
+		ref, ok := componentName(scheme.Ref, "securitySchemes")
+		if !ok || depth == maxRefDepth {
+			return nil
+		}
+		scheme = s.Components.SecuritySchemes[ref]
+	}
+	return scheme
+}
+
+// BaseURL returns the first server's URL with its variables set to their defaults
+func (s *Spec) BaseURL() string {
+	if len(s.Servers) == 0 {
+		return ""
+	}
+	server := s.Servers[0]
+	url := server.URL
+	for name, variable := range server.Variables {
+		url = strings.ReplaceAll(url, "{"+name+"}", variable.Default)
+	}
+	return strings.TrimSuffix(url, "/")
+}
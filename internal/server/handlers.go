@@ -2,14 +2,16 @@ package server
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strconv"
-	"strings"
 
 	"doclific/internal/config"
 	"doclific/internal/core"
+	"doclific/internal/erd"
+	"doclific/internal/httpreq"
 )
 
 // RegisterRoutes registers all API routes using REST conventions
@@ -22,17 +24,41 @@ func RegisterRoutes(mux *http.ServeMux) {
 
 	// Docs routes
 	mux.HandleFunc("GET /api/docs", handleDocsGetDocs)
+	mux.HandleFunc("GET /api/docs/problems", handleDocsGetProblems)
 	mux.HandleFunc("GET /api/docs/doc", handleDocsGetDoc)
 	mux.HandleFunc("PUT /api/docs/doc", handleDocsUpdateDoc)
 	mux.HandleFunc("POST /api/docs", handleDocsCreateDoc)
 	mux.HandleFunc("DELETE /api/docs/doc", handleDocsDeleteDoc)
 	mux.HandleFunc("PUT /api/docs/order", handleDocsUpdateOrder)
+	mux.HandleFunc("POST /api/docs/duplicate", handleDocsDuplicateDoc)
+	mux.HandleFunc("GET /api/docs/templates", handleDocsGetTemplates)
+	mux.HandleFunc("PUT /api/docs/slug", handleDocsUpdateSlug)
+	mux.HandleFunc("GET /api/docs/slug/resolve", handleDocsResolveSlug)
+	mux.HandleFunc("GET /api/docs/resolve", handleDocsResolveTitlePath)
+	mux.HandleFunc("GET /api/docs/link", handleDocsResolveLink)
+	mux.HandleFunc("GET /api/docs/backlinks", handleDocsGetBacklinks)
+	mux.HandleFunc("PUT /api/docs/metadata", handleDocsUpdateMetadata)
+	mux.HandleFunc("GET /api/docs/filter", handleDocsFilterDocs)
+	mux.HandleFunc("GET /api/docs/stale", handleDocsGetStaleDocs)
 
 	// Codebase routes
 	mux.HandleFunc("GET /api/codebase/folder", handleCodebaseGetFolderContents)
 	mux.HandleFunc("GET /api/codebase/file", handleCodebaseGetFileContents)
 	mux.HandleFunc("GET /api/codebase/snippet", handleCodebaseGetSnippet)
 	mux.HandleFunc("GET /api/codebase/prefix", handleCodebaseGetPrefix)
+
+	// ERD routes
+	mux.HandleFunc("POST /api/erd/from-sql", handleERDFromSQL)
+	mux.HandleFunc("POST /api/erd/from-go", handleERDFromGo)
+	mux.HandleFunc("GET /api/erd/sync", handleERDGetSync)
+	mux.HandleFunc("POST /api/erd/sync", handleERDSync)
+	mux.HandleFunc("GET /api/erd/export", handleERDExport)
+
+	// HTTP routes
+	// They send requests with the active environment's secrets, so only the Doclific UI may call them
+	mux.HandleFunc("POST /api/http/execute", uiOnly(handleHTTPExecute))
+	mux.HandleFunc("GET /api/http/environments", uiOnly(handleHTTPGetEnvironments))
+	mux.HandleFunc("POST /api/http/environments/active", uiOnly(handleHTTPSetActiveEnvironment))
 }
 
 // Git handlers
@@ -86,6 +112,18 @@ func handleDocsGetDocs(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(docs)
 }
 
+// handleDocsGetProblems lists the docs left out of the tree GET /api/docs returns
+func handleDocsGetProblems(w http.ResponseWriter, r *http.Request) {
+	_, problems, err := core.GetDocsWithProblems()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(problems)
+}
+
 func handleDocsGetDoc(w http.ResponseWriter, r *http.Request) {
 	filePath := r.URL.Query().Get("filePath")
 	if filePath == "" {
@@ -131,13 +169,14 @@ func handleDocsCreateDoc(w http.ResponseWriter, r *http.Request) {
 		FilePath string  `json:"filePath"`
 		Title    string  `json:"title"`
 		Icon     *string `json:"icon,omitempty"`
+		Template string  `json:"template,omitempty"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
-	result, err := core.CreateDoc(req.FilePath, req.Title, req.Icon)
+	result, err := core.CreateDoc(req.FilePath, req.Title, req.Icon, req.Template)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -175,6 +214,189 @@ func handleDocsUpdateOrder(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(nil)
 }
 
+func handleDocsDuplicateDoc(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		FilePath string `json:"filePath"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := core.DuplicateDoc(req.FilePath)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+func handleDocsGetTemplates(w http.ResponseWriter, r *http.Request) {
+	templates, err := core.ListTemplates()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(templates)
+}
+
+func handleDocsUpdateSlug(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		FilePath string `json:"filePath"`
+		Slug     string `json:"slug"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := core.SetDocSlug(req.FilePath, req.Slug); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(nil)
+}
+
+func handleDocsResolveSlug(w http.ResponseWriter, r *http.Request) {
+	slugPath := r.URL.Query().Get("path")
+	if slugPath == "" {
+		http.Error(w, "path query parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	result, err := core.ResolveSlugPath(slugPath)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+func handleDocsResolveTitlePath(w http.ResponseWriter, r *http.Request) {
+	titlePath := r.URL.Query().Get("path")
+	if titlePath == "" {
+		http.Error(w, "path query parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	result, err := core.ResolveTitlePath(titlePath)
+	if err != nil {
+		var ambiguous *core.AmbiguousTitleError
+		if errors.As(err, &ambiguous) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+func handleDocsResolveLink(w http.ResponseWriter, r *http.Request) {
+	target := r.URL.Query().Get("target")
+	if target == "" {
+		http.Error(w, "target query parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	filePath, err := core.ResolveDocLink(target)
+	if err != nil {
+		if errors.Is(err, core.ErrBrokenDocLink) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	result := map[string]interface{}{
+		"filePath": filePath,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+func handleDocsGetBacklinks(w http.ResponseWriter, r *http.Request) {
+	filePath := r.URL.Query().Get("filePath")
+	if filePath == "" {
+		http.Error(w, "filePath query parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	backlinks, err := core.GetBacklinks(filePath)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(backlinks)
+}
+
+func handleDocsUpdateMetadata(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		FilePath string `json:"filePath"`
+		core.DocMetadata
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := core.UpdateDocMetadata(req.FilePath, req.DocMetadata); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, core.ErrInvalidMetadata) {
+			status = http.StatusBadRequest
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(nil)
+}
+
+func handleDocsFilterDocs(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := core.DocFilter{
+		Tag:            query.Get("tag"),
+		Owner:          query.Get("owner"),
+		Status:         query.Get("status"),
+		ReviewedBefore: query.Get("reviewedBefore"),
+	}
+
+	docs, err := core.FilterDocs(filter)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(docs)
+}
+
+func handleDocsGetStaleDocs(w http.ResponseWriter, r *http.Request) {
+	staleDocs, err := core.GetStaleDocs()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(staleDocs)
+}
+
 // Codebase handlers
 
 func handleCodebaseGetFolderContents(w http.ResponseWriter, r *http.Request) {
@@ -220,31 +442,6 @@ func handleCodebaseGetFileContents(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(result)
 }
 
-// extractLinesFromContent extracts lines from content between start and end (1-indexed, inclusive)
-func extractLinesFromContent(content string, start, end int) string {
-	lines := strings.Split(content, "\n")
-
-	if start < 1 {
-		start = 1
-	}
-	if end < 1 || end > len(lines) {
-		end = len(lines)
-	}
-	if start > len(lines) {
-		return ""
-	}
-
-	// Convert to 0-indexed
-	startIdx := start - 1
-	endIdx := end
-
-	if endIdx > len(lines) {
-		endIdx = len(lines)
-	}
-
-	return strings.Join(lines[startIdx:endIdx], "\n")
-}
-
 func handleCodebaseGetSnippet(w http.ResponseWriter, r *http.Request) {
 	filePath := r.URL.Query().Get("filePath")
 	if filePath == "" {
@@ -272,160 +469,254 @@ func handleCodebaseGetSnippet(w http.ResponseWriter, r *http.Request) {
 
 	storedHash := r.URL.Query().Get("contentHash")
 
-	// Get full file contents
-	fullContents, err := core.GetFileContents(filePath)
+	result, err := core.ResolveSnippet(filePath, lineStart, lineEnd, storedHash)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	cwd, err := os.Getwd()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+func handleCodebaseGetPrefix(w http.ResponseWriter, r *http.Request) {
+	prefix, err := config.GetConfigValue("DEEPLINK_PREFIX")
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	fullPath := filepath.Join(cwd, filePath)
+	result := map[string]interface{}{
+		"prefix": prefix,
+	}
 
-	// If no stored hash, this is a new/initializing snippet
-	// Don't do diff adjustments - user selected these lines in the working directory as-is
-	if storedHash == "" {
-		snippetContent := extractLinesFromContent(fullContents, lineStart, lineEnd)
-		currentHash := core.HashContent(snippetContent)
-		currentCommit, _ := core.GetCurrentCommit()
-
-		result := map[string]interface{}{
-			"contents":       snippetContent,
-			"fullPath":       fullPath,
-			"lineStart":      lineStart,
-			"lineEnd":        lineEnd,
-			"baseCommit":     currentCommit,
-			"contentHash":    currentHash,
-			"needsReview":    false,
-			"linesAdjusted":  false,
-		}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
 
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(result)
+// ERD handlers
+func handleERDFromSQL(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		SQL   string   `json:"sql,omitempty"`
+		Paths []string `json:"paths,omitempty"` // SQL files or migration directories relative to the repository
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
+	if req.SQL == "" && len(req.Paths) == 0 {
+		http.Error(w, "sql or paths is required", http.StatusBadRequest)
+		return
+	}
+	for _, p := range req.Paths {
+		if !filepath.IsLocal(p) {
+			http.Error(w, "paths must be relative to the repository", http.StatusBadRequest)
+			return
+		}
+	}
 
-	// First, check if the content at the ORIGINAL lines matches the stored hash
-	originalSnippetContent := extractLinesFromContent(fullContents, lineStart, lineEnd)
-	originalHash := core.HashContent(originalSnippetContent)
-	snippetLength := lineEnd - lineStart
+	schema := &erd.Schema{}
+	if len(req.Paths) > 0 {
+		var err error
+		if schema, err = erd.ParseSQLFiles(req.Paths); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if err := schema.ApplySQL(req.SQL); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
-	if storedHash == originalHash {
-		// Content matches at original lines - no adjustment needed
-		currentCommit, _ := core.GetCurrentCommit()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(erd.Build(schema))
+}
 
-		result := map[string]interface{}{
-			"contents":      originalSnippetContent,
-			"fullPath":      fullPath,
-			"lineStart":     lineStart,
-			"lineEnd":       lineEnd,
-			"baseCommit":    currentCommit,
-			"contentHash":   originalHash,
-			"needsReview":   false,
-			"linesAdjusted": false,
+func handleERDFromGo(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Paths []string `json:"paths,omitempty"` // Go files or packages relative to the repository; empty to search the repository
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	for _, p := range req.Paths {
+		if !filepath.IsLocal(p) {
+			http.Error(w, "paths must be relative to the repository", http.StatusBadRequest)
+			return
 		}
+	}
 
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(result)
-		return
-	}
-
-	// Content doesn't match at original lines - search for where it moved
-	// Check line ranges shifted up and down (up to 100 lines in each direction)
-	currentCommit, _ := core.GetCurrentCommit()
-	maxSearchDistance := 100
-	totalLines := len(strings.Split(fullContents, "\n"))
-
-	foundLineStart := 0
-	foundLineEnd := 0
-	foundContent := ""
-	found := false
-
-	for offset := 1; offset <= maxSearchDistance && !found; offset++ {
-		// Check shifted down (lines added above)
-		downStart := lineStart + offset
-		downEnd := downStart + snippetLength
-		if downEnd <= totalLines {
-			downContent := extractLinesFromContent(fullContents, downStart, downEnd)
-			downHash := core.HashContent(downContent)
-			if storedHash == downHash {
-				foundLineStart = downStart
-				foundLineEnd = downEnd
-				foundContent = downContent
-				found = true
-				break
-			}
+	paths := req.Paths
+	if len(paths) == 0 {
+		files, err := erd.GoModelFiles()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
 		}
-
-		// Check shifted up (lines removed above)
-		upStart := lineStart - offset
-		upEnd := upStart + snippetLength
-		if upStart >= 1 {
-			upContent := extractLinesFromContent(fullContents, upStart, upEnd)
-			upHash := core.HashContent(upContent)
-			if storedHash == upHash {
-				foundLineStart = upStart
-				foundLineEnd = upEnd
-				foundContent = upContent
-				found = true
-				break
-			}
+		if len(files) == 0 {
+			http.Error(w, "no Go files using GORM or ent found", http.StatusNotFound)
+			return
 		}
+		paths = files
+	}
+
+	schema, err := erd.ParseGoModels(paths)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(erd.Build(schema))
+}
+
+func handleERDGetSync(w http.ResponseWriter, r *http.Request) {
+	results, err := erd.SyncDocs(r.URL.Query().Get("filePath"), false)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
 	}
 
-	if found {
-		// Found the content at a different position
-		result := map[string]interface{}{
-			"contents":      foundContent,
-			"fullPath":      fullPath,
-			"lineStart":     foundLineStart,
-			"lineEnd":       foundLineEnd,
-			"baseCommit":    currentCommit,
-			"contentHash":   storedHash, // Hash matches, so use stored hash
-			"needsReview":   false,
-			"linesAdjusted": true,
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(results)
+}
+
+func handleERDSync(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		FilePath string `json:"filePath,omitempty"` // doc to update; empty for every doc
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	results, err := erd.SyncDocs(req.FilePath, true)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(results)
+}
+
+func handleERDExport(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filePath := query.Get("filePath")
+	if filePath == "" {
+		http.Error(w, "filePath is required", http.StatusBadRequest)
+		return
+	}
+	index := 0
+	if indexStr := query.Get("index"); indexStr != "" {
+		var err error
+		if index, err = strconv.Atoi(indexStr); err != nil {
+			http.Error(w, "index must be a valid integer", http.StatusBadRequest)
+			return
 		}
+	}
 
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(result)
+	diagram, err := erd.DocDiagram(filePath, index)
+	if errors.Is(err, erd.ErrBlockNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	// Content not found at any nearby position - it has been modified
-	// Return original lines but flag for review
-	result := map[string]interface{}{
-		"contents":      originalSnippetContent,
-		"fullPath":      fullPath,
-		"lineStart":     lineStart,
-		"lineEnd":       lineEnd,
-		"baseCommit":    currentCommit,
-		"contentHash":   originalHash,
-		"needsReview":   true,
-		"linesAdjusted": false,
+	output, err := diagram.Export(query.Get("format"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Write([]byte(output))
+}
+
+// HTTP handlers
+func handleHTTPExecute(w http.ResponseWriter, r *http.Request) {
+	var req httpreq.Request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.URL == "" {
+		http.Error(w, "url is required", http.StatusBadRequest)
+		return
+	}
+
+	hosts, err := httpreq.AllowedHosts()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	variables, err := httpreq.ActiveVariables()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	executor := &httpreq.Executor{AllowedHosts: hosts, Variables: variables}
+	response, err := executor.Execute(r.Context(), req)
+	if errors.Is(err, httpreq.ErrHostNotAllowed) {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	if errors.Is(err, httpreq.ErrInvalidRequest) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(result)
+	json.NewEncoder(w).Encode(response)
 }
 
-func handleCodebaseGetPrefix(w http.ResponseWriter, r *http.Request) {
-	prefix, err := config.GetConfigValue("DEEPLINK_PREFIX")
+func handleHTTPGetEnvironments(w http.ResponseWriter, r *http.Request) {
+	environments, err := httpreq.ListEnvironments()
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	result := map[string]interface{}{
-		"prefix": prefix,
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(environments)
+}
+
+func handleHTTPSetActiveEnvironment(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := httpreq.SetActiveEnvironment(req.Name); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, httpreq.ErrEnvironmentNotFound) {
+			status = http.StatusNotFound
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	environments, err := httpreq.ListEnvironments()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(result)
+	json.NewEncoder(w).Encode(environments)
 }
 
 // Update handlers
@@ -3,7 +3,9 @@ package server
 import (
 	"fmt"
 	"log"
+	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -32,6 +34,46 @@ func corsMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// isUIOrigin reports whether origin is the Doclific UI served by this server: a loopback host on the
+// port the request was sent to. Any other page, including one DNS-rebound to 127.0.0.1, has another origin
+func isUIOrigin(origin string, host string) bool {
+	u, err := url.Parse(origin)
+	if err != nil || u.Scheme != "http" {
+		return false
+	}
+	_, port, err := net.SplitHostPort(host)
+	if err != nil || u.Port() != port {
+		return false
+	}
+	switch u.Hostname() {
+	case "localhost", "127.0.0.1", "::1":
+		return true
+	}
+	return false
+}
+
+// uiOnly rejects requests that a browser sent from any page other than the Doclific UI
+// Routes that act with the user's credentials use it, since CORS lets every page read their responses.
+// Requests without an Origin, such as from curl, come from the user's own machine and are allowed
+func uiOnly(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			switch r.Header.Get("Sec-Fetch-Site") {
+			case "", "same-origin", "none":
+			default:
+				http.Error(w, "Cross-origin requests are not allowed", http.StatusForbidden)
+				return
+			}
+		} else if !isUIOrigin(origin, r.Host) {
+			http.Error(w, "Cross-origin requests are not allowed", http.StatusForbidden)
+			return
+		}
+
+		next(w, r)
+	}
+}
+
 // StartServer starts the HTTP server on the specified port
 func StartServer(port int) error {
 	mux := http.NewServeMux()
@@ -0,0 +1,146 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUIOnly(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Chdir(t.TempDir())
+
+	called := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/http/execute", uiOnly(func(w http.ResponseWriter, r *http.Request) { called++ }))
+	handler := corsMiddleware(mux)
+
+	tests := []struct {
+		name     string
+		origin   string
+		fetch    string
+		wantCode int
+	}{
+		{"doclific UI", "http://localhost:6767", "same-origin", http.StatusOK},
+		{"doclific UI on 127.0.0.1", "http://127.0.0.1:6767", "cross-site", http.StatusOK},
+		{"curl", "", "", http.StatusOK},
+		{"other site", "https://evil.example.com", "cross-site", http.StatusForbidden},
+		{"DNS rebinding", "http://evil.example.com:6767", "same-origin", http.StatusForbidden},
+		{"other local port", "http://localhost:3000", "same-site", http.StatusForbidden},
+		{"opaque origin", "null", "cross-site", http.StatusForbidden},
+		{"no origin from another site", "", "cross-site", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called = 0
+			req := httptest.NewRequest("POST", "http://localhost:6767/api/http/execute", strings.NewReader(`{}`))
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			if tt.fetch != "" {
+				req.Header.Set("Sec-Fetch-Site", tt.fetch)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantCode)
+			}
+			if wantCalled := tt.wantCode == http.StatusOK; (called == 1) != wantCalled {
+				t.Errorf("handler called = %v, want %v", called == 1, wantCalled)
+			}
+		})
+	}
+}
+
+func TestHTTPExecuteRejectsCrossOrigin(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Chdir(t.TempDir())
+
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("internal"))
+	}))
+	defer target.Close()
+
+	mux := http.NewServeMux()
+	RegisterRoutes(mux)
+	handler := corsMiddleware(mux)
+
+	send := func(origin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "http://localhost:6767/api/http/execute", strings.NewReader(`{"method":"GET","url":"`+target.URL+`"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Origin", origin)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := send("https://evil.example.com"); rec.Code != http.StatusForbidden || strings.Contains(rec.Body.String(), "internal") {
+		t.Errorf("cross-origin execute = %d %s, want 403", rec.Code, rec.Body.String())
+	}
+	if rec := send("http://localhost:6767"); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "internal") {
+		t.Errorf("execute from the UI = %d %s, want 200 with the target's body", rec.Code, rec.Body.String())
+	}
+}
+
+func TestDocsResolveLink(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+
+	configs := map[string]string{
+		"guide":          `{"title": "Getting Started", "slug": "start", "order": 0}`,
+		"guide/api-uuid": `{"title": "API Reference", "slug": "api", "order": 0}`,
+	}
+	for dir, config := range configs {
+		fullPath := filepath.Join(tmpDir, "doclific", filepath.FromSlash(dir))
+		if err := os.MkdirAll(fullPath, 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", dir, err)
+		}
+		if err := os.WriteFile(filepath.Join(fullPath, "config.json"), []byte(config), 0644); err != nil {
+			t.Fatalf("failed to write config for %s: %v", dir, err)
+		}
+	}
+
+	mux := http.NewServeMux()
+	RegisterRoutes(mux)
+
+	tests := []struct {
+		target       string
+		wantCode     int
+		wantFilePath string
+	}{
+		{"api-uuid", http.StatusOK, "guide/api-uuid"},
+		{"doc:start/api#usage", http.StatusOK, "guide/api-uuid"},
+		{"Getting Started/API Reference", http.StatusNotFound, ""},
+		{"other/api-uuid", http.StatusNotFound, ""},
+		{"", http.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/api/docs/link?target="+url.QueryEscape(tt.target), nil)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+
+		if rec.Code != tt.wantCode {
+			t.Errorf("link %q = %d %s, want %d", tt.target, rec.Code, rec.Body.String(), tt.wantCode)
+			continue
+		}
+		if tt.wantFilePath == "" {
+			continue
+		}
+		var result struct {
+			FilePath string `json:"filePath"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if result.FilePath != tt.wantFilePath {
+			t.Errorf("link %q = %q, want %q", tt.target, result.FilePath, tt.wantFilePath)
+		}
+	}
+}
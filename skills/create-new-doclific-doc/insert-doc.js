@@ -1,10 +1,11 @@
 #!/usr/bin/env node
 
-const fs = require('fs');
+// Deprecated: use `doclific new --parent <path|title> --title <title> --icon <icon-name>` directly.
+// This wrapper forwards to the CLI so doc creation and icon validation live in one place.
+
 const path = require('path');
-const { randomUUID } = require('crypto');
+const { spawnSync } = require('child_process');
 
-// Get command line arguments
 const args = process.argv.slice(2);
 
 if (args.length !== 3) {
@@ -14,42 +15,18 @@ if (args.length !== 3) {
 
 const [docPath, title, iconName] = args;
 
-// Validate icon name
-const allIconsPath = path.join(__dirname, 'all-icons.json');
-const allIcons = JSON.parse(fs.readFileSync(allIconsPath, 'utf-8'));
-
-if (!allIcons.includes(iconName)) {
-    console.error(`Error: Icon "${iconName}" not found in all-icons.json`);
-    console.error(`Available icons: ${allIcons.slice(0, 10).join(', ')}... (and ${allIcons.length - 10} more)`);
-    process.exit(1);
+// doclific new takes the parent relative to the doclific folder
+let parent = docPath;
+if (path.isAbsolute(parent)) {
+    parent = path.relative(path.join(process.cwd(), 'doclific'), parent);
 }
 
-// Generate random UUID for the directory
-const randomId = randomUUID();
-
-// Create the full directory path
-const fullPath = path.join(docPath, randomId);
-
-// Create the directory
-fs.mkdirSync(fullPath, { recursive: true });
+const result = spawnSync('doclific', ['new', '--parent', parent, '--title', title, '--icon', iconName], {
+    stdio: 'inherit',
+});
 
-// Create content.mdx with initial content (title as heading)
-const contentMdx = `# ${title}
-
-`;
-const contentMdxPath = path.join(fullPath, 'content.mdx');
-fs.writeFileSync(contentMdxPath, contentMdx, 'utf-8');
-
-// Create config.json
-const configJson = {
-    title: title,
-    icon: iconName
-};
-const configJsonPath = path.join(fullPath, 'config.json');
-fs.writeFileSync(configJsonPath, JSON.stringify(configJson, null, 2) + '\n', 'utf-8');
-
-console.log(`Created new document at: ${fullPath}`);
-console.log(`Title: ${title}`);
-console.log(`Icon: ${iconName}`);
-console.log(`ID: ${randomId}`);
-console.log('\nThe generate-doclific-mdx skill can now be used to add content to content.mdx');
+if (result.error) {
+    console.error(`Error: failed to run doclific: ${result.error.message}`);
+    process.exit(1);
+}
+process.exit(result.status ?? 1);
@@ -0,0 +1,9 @@
+// Package skills embeds the assets that the agent skills share with the doclific CLI
+package skills
+
+import _ "embed"
+
+// AllIconsJSON is the list of icon names a doc may use, as read by the create-new-doclific-doc skill
+//
+//go:embed create-new-doclific-doc/all-icons.json
+var AllIconsJSON []byte
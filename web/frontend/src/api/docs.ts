@@ -2,7 +2,7 @@
  * Docs API client functions for TanStack React Query
  */
 
-import type { FolderStructure } from '@/types/docs';
+import type { DocStatus, FolderStructure } from '@/types/docs';
 
 const API_BASE_URL = `http://localhost:${window.env.PORT ?? 6767}/api`;
 
@@ -10,6 +10,7 @@ export interface CreateDocRequest {
 	filePath: string;
 	title: string;
 	icon?: string;
+	template?: string;
 }
 
 export interface CreateDocResponse {
@@ -39,6 +40,42 @@ export async function getDocs(): Promise<FolderStructure[]> {
 	return response.json();
 }
 
+export type DocProblemKind =
+	| 'missing-config'
+	| 'invalid-config'
+	| 'missing-content'
+	| 'orphan-content'
+	| 'duplicate-order'
+	| 'non-uuid-folder'
+	| 'hidden-by-parent';
+
+export interface DocProblem {
+	filePath: string;
+	kind: DocProblemKind;
+	message: string;
+	repaired?: boolean;
+}
+
+/**
+ * Fetch the docs hidden from the tree because their config.json, or a parent's, is missing or invalid
+ * @returns Promise resolving to the list of problems (empty when every doc is in the tree)
+ */
+export async function getDocProblems(): Promise<DocProblem[]> {
+	const response = await fetch(`${API_BASE_URL}/docs/problems`, {
+		method: 'GET',
+		headers: {
+			'Content-Type': 'application/json',
+		},
+	});
+
+	if (!response.ok) {
+		const errorText = await response.text();
+		throw new Error(`Failed to fetch doc problems: ${errorText}`);
+	}
+
+	return response.json();
+}
+
 /**
  * Get a specific document's content
  * @param filePath - The relative path to the document folder
@@ -109,6 +146,56 @@ export async function createDoc(request: CreateDocRequest): Promise<CreateDocRes
 	return response.json();
 }
 
+/**
+ * Duplicate a document and all of its children
+ * @param filePath - The relative path to the document folder to copy
+ * @returns Promise resolving to the created copy
+ */
+export async function duplicateDoc(filePath: string): Promise<CreateDocResponse> {
+	const response = await fetch(`${API_BASE_URL}/docs/duplicate`, {
+		method: 'POST',
+		headers: {
+			'Content-Type': 'application/json',
+		},
+		body: JSON.stringify({ filePath }),
+	});
+
+	if (!response.ok) {
+		const errorText = await response.text();
+		throw new Error(`Failed to duplicate doc: ${errorText}`);
+	}
+
+	return response.json();
+}
+
+export interface DocTemplate {
+	name: string;
+	title: string;
+	description?: string;
+	icon?: string;
+	source: 'repo' | 'user';
+}
+
+/**
+ * Get the templates available for new documents
+ * @returns Promise resolving to the template list
+ */
+export async function getTemplates(): Promise<DocTemplate[]> {
+	const response = await fetch(`${API_BASE_URL}/docs/templates`, {
+		method: 'GET',
+		headers: {
+			'Content-Type': 'application/json',
+		},
+	});
+
+	if (!response.ok) {
+		const errorText = await response.text();
+		throw new Error(`Failed to get templates: ${errorText}`);
+	}
+
+	return response.json();
+}
+
 /**
  * Delete a document
  * @param filePath - The relative path to the document folder
@@ -159,3 +246,209 @@ export async function updateDocOrder(payload: DocOrderNode): Promise<void> {
 		throw new Error(`Failed to update doc order: ${errorText}`);
 	}
 }
+
+export interface SlugResolution {
+	filePath: string;
+	slugPath: string;
+	redirected: boolean;
+}
+
+/**
+ * Set or clear a document's slug
+ * @param filePath - The relative path to the document folder
+ * @param slug - The new slug, or an empty string to clear it
+ * @returns Promise resolving to void
+ */
+export async function updateDocSlug(filePath: string, slug: string): Promise<void> {
+	const response = await fetch(`${API_BASE_URL}/docs/slug`, {
+		method: 'PUT',
+		headers: {
+			'Content-Type': 'application/json',
+		},
+		body: JSON.stringify({ filePath, slug }),
+	});
+
+	if (!response.ok) {
+		const errorText = await response.text();
+		throw new Error(`Failed to update doc slug: ${errorText}`);
+	}
+}
+
+/**
+ * Resolve a slug path such as "architecture/backend" to a document folder path
+ * @param path - The slug path
+ * @returns Promise resolving to the folder path and canonical slug path
+ */
+export async function resolveSlug(path: string): Promise<SlugResolution> {
+	const url = new URL(`${API_BASE_URL}/docs/slug/resolve`);
+	url.searchParams.set('path', path);
+
+	const response = await fetch(url.toString(), {
+		method: 'GET',
+		headers: {
+			'Content-Type': 'application/json',
+		},
+	});
+
+	if (!response.ok) {
+		const errorText = await response.text();
+		throw new Error(`Failed to resolve slug: ${errorText}`);
+	}
+
+	return response.json();
+}
+
+export interface TitleResolution {
+	filePath: string;
+	titlePath: string;
+}
+
+/**
+ * Resolve a title path such as "Architecture Overview/Backend Development" to a document folder path
+ * @param path - Titles from the root down to the document, joined by "/"
+ * @returns Promise resolving to the folder path and canonical title path
+ */
+export async function resolveTitlePath(path: string): Promise<TitleResolution> {
+	const url = new URL(`${API_BASE_URL}/docs/resolve`);
+	url.searchParams.set('path', path);
+
+	const response = await fetch(url.toString(), {
+		method: 'GET',
+		headers: {
+			'Content-Type': 'application/json',
+		},
+	});
+
+	if (!response.ok) {
+		const errorText = await response.text();
+		throw new Error(`Failed to resolve title path: ${errorText}`);
+	}
+
+	return response.json();
+}
+
+/**
+ * Resolve a doc: link target, such as a folder UUID or slug path, to a document folder path
+ * @param target - The link target after the doc: scheme
+ * @returns Promise resolving to the folder path of the linked document
+ */
+export async function resolveDocLink(target: string): Promise<{ filePath: string }> {
+	const url = new URL(`${API_BASE_URL}/docs/link`);
+	url.searchParams.set('target', target);
+
+	const response = await fetch(url.toString(), {
+		method: 'GET',
+		headers: {
+			'Content-Type': 'application/json',
+		},
+	});
+
+	if (!response.ok) {
+		const errorText = await response.text();
+		throw new Error(`Failed to resolve doc link: ${errorText}`);
+	}
+
+	return response.json();
+}
+
+export interface DocLink {
+	source: string;
+	sourceTitle: string;
+	target: string;
+	text: string;
+	line: number;
+	resolved?: string;
+	broken: boolean;
+	reason?: string;
+}
+
+/**
+ * Get the documents linking to a document
+ * @param filePath - The relative path to the document folder
+ * @returns Promise resolving to the links pointing at the document
+ */
+export async function getBacklinks(filePath: string): Promise<DocLink[]> {
+	const url = new URL(`${API_BASE_URL}/docs/backlinks`);
+	url.searchParams.set('filePath', filePath);
+
+	const response = await fetch(url.toString(), {
+		method: 'GET',
+		headers: {
+			'Content-Type': 'application/json',
+		},
+	});
+
+	if (!response.ok) {
+		const errorText = await response.text();
+		throw new Error(`Failed to get backlinks: ${errorText}`);
+	}
+
+	return response.json();
+}
+
+export interface DocMetadata {
+	tags?: string[];
+	owners?: string[];
+	status?: DocStatus;
+	reviewedAt?: string;
+}
+
+export interface DocFilter {
+	tag?: string;
+	owner?: string;
+	status?: DocStatus;
+	reviewedBefore?: string;
+}
+
+export interface DocSummary extends DocMetadata {
+	filePath: string;
+	title: string;
+	icon?: string;
+}
+
+/**
+ * Replace a document's tags, owners, status and review date
+ * @param filePath - The relative path to the document folder
+ * @param metadata - The new metadata
+ * @returns Promise resolving to void
+ */
+export async function updateDocMetadata(filePath: string, metadata: DocMetadata): Promise<void> {
+	const response = await fetch(`${API_BASE_URL}/docs/metadata`, {
+		method: 'PUT',
+		headers: {
+			'Content-Type': 'application/json',
+		},
+		body: JSON.stringify({ filePath, ...metadata }),
+	});
+
+	if (!response.ok) {
+		const errorText = await response.text();
+		throw new Error(`Failed to update doc metadata: ${errorText}`);
+	}
+}
+
+/**
+ * Find documents by metadata, e.g. { owner: 'me' } or { status: 'draft' }
+ * @param filter - The metadata filter; empty fields match every document
+ * @returns Promise resolving to the matching documents
+ */
+export async function filterDocs(filter: DocFilter): Promise<DocSummary[]> {
+	const url = new URL(`${API_BASE_URL}/docs/filter`);
+	for (const [key, value] of Object.entries(filter)) {
+		if (value) url.searchParams.set(key, value);
+	}
+
+	const response = await fetch(url.toString(), {
+		method: 'GET',
+		headers: {
+			'Content-Type': 'application/json',
+		},
+	});
+
+	if (!response.ok) {
+		const errorText = await response.text();
+		throw new Error(`Failed to filter docs: ${errorText}`);
+	}
+
+	return response.json();
+}
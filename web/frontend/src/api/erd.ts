@@ -0,0 +1,159 @@
+/**
+ * ERD API client functions for TanStack React Query
+ */
+
+import type { Column, Relationship } from '@/components/editor/plugins/erd-kit';
+
+const API_BASE_URL = `http://localhost:${window.env.PORT ?? 6767}/api`;
+
+export interface ERDTable {
+	id: string;
+	type: 'tableNode';
+	data: {
+		name: string;
+		columns: Column[];
+	};
+	position: {
+		x: number;
+		y: number;
+	};
+}
+
+export interface ERDDiagram {
+	tables: ERDTable[];
+	relationships: Omit<Relationship, 'cardinality'>[];
+}
+
+export interface ERDFromSQLRequest {
+	sql?: string;
+	paths?: string[]; // SQL files or migration directories relative to the repository root
+}
+
+/**
+ * Generate ERD tables and relationships from PostgreSQL DDL
+ * @param request - Inline SQL and/or repository paths of SQL files or migration directories
+ * @returns Promise resolving to the laid-out diagram
+ */
+export async function generateERDFromSQL(request: ERDFromSQLRequest): Promise<ERDDiagram> {
+	const response = await fetch(`${API_BASE_URL}/erd/from-sql`, {
+		method: 'POST',
+		headers: {
+			'Content-Type': 'application/json',
+		},
+		body: JSON.stringify(request),
+	});
+
+	if (!response.ok) {
+		const errorText = await response.text();
+		throw new Error(`Failed to generate ERD from SQL: ${errorText}`);
+	}
+
+	return response.json();
+}
+
+/**
+ * Generate ERD tables and relationships from GORM models or ent schemas
+ * @param paths - Go files or packages relative to the repository root (suffix "/..." to recurse); empty to search the repository
+ * @returns Promise resolving to the laid-out diagram
+ */
+export async function generateERDFromGo(paths: string[] = []): Promise<ERDDiagram> {
+	const response = await fetch(`${API_BASE_URL}/erd/from-go`, {
+		method: 'POST',
+		headers: {
+			'Content-Type': 'application/json',
+		},
+		body: JSON.stringify({ paths }),
+	});
+
+	if (!response.ok) {
+		const errorText = await response.text();
+		throw new Error(`Failed to generate ERD from Go models: ${errorText}`);
+	}
+
+	return response.json();
+}
+
+export interface ERDChange {
+	kind:
+		| 'table-added'
+		| 'table-removed'
+		| 'column-added'
+		| 'column-removed'
+		| 'column-changed'
+		| 'relationship-added'
+		| 'relationship-removed'
+		| 'relationship-changed';
+	table: string;
+	column?: string;
+	detail?: string;
+}
+
+export interface SourcedERD {
+	filePath: string;
+	title: string;
+	line: number; // line of the block in content.mdx
+	source: string;
+	changes: ERDChange[];
+	error?: string;
+	applied?: boolean;
+}
+
+/**
+ * Compare ERD blocks that declare a source attribute with the schema they are generated from
+ * @param filePath - Doc to check; omit to check every doc
+ * @returns Promise resolving to every sourced ERD block and its differences
+ */
+export async function getERDSync(filePath?: string): Promise<SourcedERD[]> {
+	const query = filePath ? `?filePath=${encodeURIComponent(filePath)}` : '';
+	const response = await fetch(`${API_BASE_URL}/erd/sync${query}`);
+
+	if (!response.ok) {
+		const errorText = await response.text();
+		throw new Error(`Failed to check ERD sources: ${errorText}`);
+	}
+
+	return response.json();
+}
+
+/**
+ * Rewrite out-of-date ERD blocks from their sources, keeping the positions of existing tables
+ * @param filePath - Doc to update; omit to update every doc
+ * @returns Promise resolving to every sourced ERD block and what was applied
+ */
+export async function syncERDs(filePath?: string): Promise<SourcedERD[]> {
+	const response = await fetch(`${API_BASE_URL}/erd/sync`, {
+		method: 'POST',
+		headers: {
+			'Content-Type': 'application/json',
+		},
+		body: JSON.stringify({ filePath }),
+	});
+
+	if (!response.ok) {
+		const errorText = await response.text();
+		throw new Error(`Failed to sync ERD blocks: ${errorText}`);
+	}
+
+	return response.json();
+}
+
+export type ERDExportFormat = 'sql' | 'mermaid' | 'dbml' | 'dot';
+
+/**
+ * Export an ERD block as SQL DDL, a Mermaid erDiagram, DBML or a Graphviz graph
+ * @param filePath - Doc containing the block
+ * @param index - Which ERD block of the doc, counting from 0
+ * @param format - Output format
+ * @returns Promise resolving to the exported text
+ */
+export async function exportERD(filePath: string, index: number, format: ERDExportFormat): Promise<string> {
+	const params = new URLSearchParams({ filePath, index: String(index), format });
+	const response = await fetch(`${API_BASE_URL}/erd/export?${params}`);
+
+	if (!response.ok) {
+		const errorText = await response.text();
+		throw new Error(`Failed to export ERD: ${errorText}`);
+	}
+
+	return response.text();
+}
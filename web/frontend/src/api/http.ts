@@ -0,0 +1,112 @@
+/**
+ * HTTP request proxy API client functions
+ */
+
+import type { HttpAuth, HttpResponse, KeyValuePair } from '@/components/editor/plugins/request-kit';
+
+const API_BASE_URL = `http://localhost:${window.env.PORT ?? 6767}/api`;
+
+export interface ExecuteHttpRequest {
+	method: string;
+	url: string;
+	headers: KeyValuePair[];
+	queryParams: KeyValuePair[];
+	bodyType: string;
+	bodyContent: string;
+	formData: KeyValuePair[];
+	auth: HttpAuth;
+}
+
+export interface ExecuteHttpResponse extends HttpResponse {
+	truncated?: boolean;
+	timing: {
+		dns: number;
+		connect: number;
+		tls: number;
+		firstByte: number;
+		total: number;
+	};
+}
+
+/**
+ * Error returned by the local server, such as 403 for a host outside the allow-list
+ */
+export class HttpProxyError extends Error {
+	status: number;
+
+	constructor(message: string, status: number) {
+		super(message);
+		this.name = 'HttpProxyError';
+		this.status = status;
+	}
+}
+
+/**
+ * Send an HttpRequest block through the local server, avoiding CORS and reaching services only this machine can see
+ * Only hosts on the allow-list (localhost plus HTTP_ALLOWED_HOSTS) can be called; others fail with a 403 HttpProxyError
+ * @param request - The block's method, url, headers, queryParams, body and auth
+ * @returns Promise resolving to the upstream status, headers, body and timing
+ */
+export async function executeHttpRequest(request: ExecuteHttpRequest): Promise<ExecuteHttpResponse> {
+	const response = await fetch(`${API_BASE_URL}/http/execute`, {
+		method: 'POST',
+		headers: {
+			'Content-Type': 'application/json',
+		},
+		body: JSON.stringify(request),
+	});
+
+	if (!response.ok) {
+		const errorText = await response.text();
+		throw new HttpProxyError(errorText.trim() || `Request failed with status ${response.status}`, response.status);
+	}
+
+	return response.json();
+}
+
+export interface HttpEnvironment {
+	name: string;
+	variables: Record<string, string>; // shared values from doclific/.environments/<name>.json
+	secrets: string[]; // names of secrets stored outside the repository
+}
+
+export interface HttpEnvironments {
+	active: string;
+	environments: HttpEnvironment[];
+}
+
+/**
+ * Get the environments {{variables}} in HttpRequest blocks resolve against
+ * @returns Promise resolving to the environments and the active one ('' when none is selected)
+ */
+export async function getHttpEnvironments(): Promise<HttpEnvironments> {
+	const response = await fetch(`${API_BASE_URL}/http/environments`);
+
+	if (!response.ok) {
+		throw new Error(`Failed to fetch environments: ${response.statusText}`);
+	}
+
+	return response.json();
+}
+
+/**
+ * Select the environment requests resolve against
+ * @param name - The environment name, or '' to clear the selection
+ * @returns Promise resolving to the updated environments
+ */
+export async function setActiveHttpEnvironment(name: string): Promise<HttpEnvironments> {
+	const response = await fetch(`${API_BASE_URL}/http/environments/active`, {
+		method: 'POST',
+		headers: {
+			'Content-Type': 'application/json',
+		},
+		body: JSON.stringify({ name }),
+	});
+
+	if (!response.ok) {
+		const errorText = await response.text();
+		throw new Error(errorText.trim() || `Failed to set environment: ${response.statusText}`);
+	}
+
+	return response.json();
+}
@@ -1,4 +1,4 @@
-import { ChevronRight, EllipsisVertical, FileIcon, Plus, UserCircle, Search, X } from "lucide-react";
+import { ChevronRight, EllipsisVertical, FileIcon, Plus, UserCircle, Search, X, TriangleAlert } from "lucide-react";
 import * as Icons from "lucide-react";
 import {
     Sidebar,
@@ -47,7 +47,7 @@ import {
     AlertDialogAction,
 } from "@/components/ui/alert-dialog";
 import { toast } from "sonner";
-import { createDoc, deleteDoc, getDocs, updateDocOrder, type DocOrderNode } from "@/api/docs";
+import { createDoc, deleteDoc, getDocProblems, getDocs, updateDocOrder, type DocOrderNode } from "@/api/docs";
 import { getRepoInfo } from "@/api/git";
 import { queryClient } from "../main"
 
@@ -313,6 +313,12 @@ export function AppSidebar() {
         queryFn: getDocs,
         enabled: true,
     })
+    // Docs with a broken config.json are left out of the tree, so they are listed separately
+    const problemsQuery = useQuery({
+        queryKey: ["docs", "get-doc-problems"],
+        queryFn: getDocProblems,
+        enabled: true,
+    })
     const updateDocOrderMutation = useMutation({
         mutationFn: updateDocOrder,
         onMutate: async (variables) => {
@@ -865,6 +871,26 @@ export function AppSidebar() {
                         </SidebarMenu>
                     </SidebarGroupContent>
                 </SidebarGroup>
+                {problemsQuery.data && problemsQuery.data.length > 0 && (
+                    <SidebarGroup>
+                        <SidebarGroupLabel>Hidden docs</SidebarGroupLabel>
+                        <SidebarGroupContent>
+                            <SidebarMenu>
+                                {problemsQuery.data.map((problem) => (
+                                    <SidebarMenuItem key={problem.filePath}>
+                                        <SidebarMenuButton
+                                            className="text-muted-foreground cursor-default"
+                                            title={`${problem.message}. Run doclific doctor --fix to repair.`}
+                                        >
+                                            <TriangleAlert className="text-yellow-500" />
+                                            <span className="truncate">{problem.filePath}</span>
+                                        </SidebarMenuButton>
+                                    </SidebarMenuItem>
+                                ))}
+                            </SidebarMenu>
+                        </SidebarGroupContent>
+                    </SidebarGroup>
+                )}
             </SidebarContent>
             <SidebarFooter>
                 <SidebarMenu>
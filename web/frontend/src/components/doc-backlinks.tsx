@@ -0,0 +1,39 @@
+import { useQuery } from "@tanstack/react-query"
+import { Link } from "react-router"
+import { CornerDownRight } from "lucide-react"
+import { getBacklinks } from "@/api/docs"
+
+/**
+ * Lists the docs that link to a doc with doc: links, once per linking doc
+ */
+export function DocBacklinks({ filePath }: { filePath: string }) {
+    const backlinksQuery = useQuery({
+        queryKey: ["docs", "backlinks", filePath],
+        queryFn: () => getBacklinks(filePath),
+        refetchOnWindowFocus: true,
+    })
+
+    const sources = new Map<string, string>()
+    for (const link of backlinksQuery.data ?? []) {
+        sources.set(link.source, link.sourceTitle)
+    }
+    if (sources.size === 0) {
+        return null
+    }
+
+    return (
+        <div className="border-t mt-8 pt-4 text-sm">
+            <p className="text-muted-foreground mb-2">Linked from</p>
+            <ul className="space-y-1">
+                {[...sources].map(([source, title]) => (
+                    <li key={source}>
+                        <Link to={`/${source}`} className="inline-flex items-center gap-2 hover:underline">
+                            <CornerDownRight className="size-4 text-muted-foreground" />
+                            {title || "Untitled"}
+                        </Link>
+                    </li>
+                ))}
+            </ul>
+        </div>
+    )
+}
@@ -0,0 +1,98 @@
+import { useEffect, useState } from "react"
+import { useMutation, useQueryClient } from "@tanstack/react-query"
+import { Check, Link } from "lucide-react"
+import { toast } from "sonner"
+import { Input } from "@/components/ui/input"
+import { Button } from "@/components/ui/button"
+import { updateDocSlug } from "@/api/docs"
+import type { FolderStructure } from "@/types/docs"
+
+/**
+ * Finds a doc in the tree by its folder path
+ * @returns The doc and the slug path leading to it, using each ancestor's slug or its folder name when it has none
+ */
+function findDoc(docs: FolderStructure[], filePath: string): { doc: FolderStructure; slugPath: string } | null {
+    const segments: string[] = []
+    let items = docs
+    let doc: FolderStructure | undefined
+    for (const name of filePath.split("/")) {
+        doc = items.find((item) => item.name === name)
+        if (!doc) return null
+        segments.push(doc.slug || doc.name)
+        items = doc.children
+    }
+    return doc ? { doc, slugPath: segments.join("/") } : null
+}
+
+/**
+ * Builds the slug path of a doc, such as "architecture/backend"
+ * @param docs - The doc tree
+ * @param filePath - The doc's folder path
+ * @returns The slug path, or null when the doc is not in the tree
+ */
+export function getSlugPath(docs: FolderStructure[], filePath: string): string | null {
+    return findDoc(docs, filePath)?.slugPath ?? null
+}
+
+/**
+ * Edits the slug of a doc, which gives it a readable link such as /architecture/backend
+ */
+export function DocSlugField({ docs, filePath }: { docs: FolderStructure[]; filePath: string }) {
+    const queryClient = useQueryClient()
+    const found = findDoc(docs, filePath)
+    const slugPath = found?.slugPath
+    const currentSlug = found?.doc.slug ?? ""
+    const [slug, setSlug] = useState(currentSlug)
+
+    useEffect(() => {
+        setSlug(currentSlug)
+    }, [currentSlug])
+
+    const updateSlugMutation = useMutation({
+        mutationFn: (value: string) => updateDocSlug(filePath, value),
+        onSuccess: () => {
+            queryClient.invalidateQueries({ queryKey: ["docs", "get-docs"] })
+            toast.success("Slug updated")
+        },
+        onError: (error) => {
+            setSlug(currentSlug)
+            toast.error(error.message)
+        },
+    })
+
+    const save = () => {
+        const value = slug.trim()
+        if (value !== currentSlug) {
+            updateSlugMutation.mutate(value)
+        }
+    }
+
+    const copyLink = () => {
+        navigator.clipboard.writeText(`${window.location.origin}/${slugPath}`)
+        toast.success("Link copied to clipboard")
+    }
+
+    return (
+        <div className="flex items-center gap-2 text-sm text-muted-foreground mb-2">
+            <span>Slug</span>
+            <Input
+                value={slug}
+                placeholder="e.g. backend-development"
+                onChange={(e) => setSlug(e.target.value)}
+                onBlur={save}
+                onKeyDown={(e) => {
+                    if (e.key === "Enter") save()
+                    if (e.key === "Escape") setSlug(currentSlug)
+                }}
+                className="h-7 max-w-56 text-sm"
+            />
+            {updateSlugMutation.isSuccess && slug === currentSlug && <Check className="size-4 text-green-500" />}
+            {slugPath && (
+                <Button variant="ghost" size="sm" onClick={copyLink} title={`/${slugPath}`}>
+                    <Link className="size-4" />
+                    Copy link
+                </Button>
+            )}
+        </div>
+    )
+}
@@ -127,6 +127,7 @@ export interface ErdNodeType {
     type: typeof ERDType;
     tables: TableNode[];
     relationships: Relationship[];
+    source?: string; // migration directory or Go package the diagram is generated from
     children: [{ text: '' }];
     [key: string]: any;
 }
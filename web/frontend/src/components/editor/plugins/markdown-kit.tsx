@@ -42,13 +42,20 @@ export const MarkdownKit = [
         },
         [ERDType]: {
           serialize: (slateNode) => {
+            const attributes = [
+              { type: 'mdxJsxAttribute', name: 'tables', value: slateNode.tables ? JSON.stringify(slateNode.tables) : '[]' },
+              { type: 'mdxJsxAttribute', name: 'relationships', value: slateNode.relationships ? JSON.stringify(slateNode.relationships) : '[]' },
+            ];
+
+            // Keep the schema source so doclific erd sync can regenerate the diagram
+            if (slateNode.source) {
+              attributes.push({ type: 'mdxJsxAttribute', name: 'source', value: slateNode.source });
+            }
+
             return {
               type: 'mdxJsxFlowElement',
               name: ERDType,
-              attributes: [
-                { type: 'mdxJsxAttribute', name: 'tables', value: slateNode.tables ? JSON.stringify(slateNode.tables) : '[]' },
-                { type: 'mdxJsxAttribute', name: 'relationships', value: slateNode.relationships ? JSON.stringify(slateNode.relationships) : '[]' },
-              ],
+              attributes,
               children: [{ type: 'text', value: '' }],
             };
           },
@@ -62,6 +69,7 @@ export const MarkdownKit = [
               type: ERDType,
               tables: getAttr('tables'),
               relationships: getAttr('relationships'),
+              source: mdastNode.attributes?.find((a: { name: string }) => a.name === 'source')?.value || undefined,
               children: [{ text: '' }],
             }
           }
@@ -114,20 +122,30 @@ export const MarkdownKit = [
         },
         [HttpRequestType]: {
           serialize: (slateNode) => {
+            const attributes = [
+              { type: 'mdxJsxAttribute', name: 'method', value: slateNode.method || 'GET' },
+              { type: 'mdxJsxAttribute', name: 'url', value: slateNode.url || '' },
+              { type: 'mdxJsxAttribute', name: 'headers', value: JSON.stringify(slateNode.headers || []) },
+              { type: 'mdxJsxAttribute', name: 'queryParams', value: JSON.stringify(slateNode.queryParams || []) },
+              { type: 'mdxJsxAttribute', name: 'bodyType', value: slateNode.bodyType || 'none' },
+              { type: 'mdxJsxAttribute', name: 'bodyContent', value: slateNode.bodyContent || '' },
+              { type: 'mdxJsxAttribute', name: 'formData', value: JSON.stringify(slateNode.formData || []) },
+              { type: 'mdxJsxAttribute', name: 'auth', value: JSON.stringify(slateNode.auth || { type: 'none' }) },
+              { type: 'mdxJsxAttribute', name: 'response', value: slateNode.response ? JSON.stringify(slateNode.response) : '' },
+            ];
+
+            // Assertions for `doclific http run`, only written when set
+            if (slateNode.expectStatus) {
+              attributes.push({ type: 'mdxJsxAttribute', name: 'expectStatus', value: slateNode.expectStatus });
+            }
+            if (slateNode.expectJson) {
+              attributes.push({ type: 'mdxJsxAttribute', name: 'expectJson', value: slateNode.expectJson });
+            }
+
             return {
               type: 'mdxJsxFlowElement',
               name: 'HttpRequest',
-              attributes: [
-                { type: 'mdxJsxAttribute', name: 'method', value: slateNode.method || 'GET' },
-                { type: 'mdxJsxAttribute', name: 'url', value: slateNode.url || '' },
-                { type: 'mdxJsxAttribute', name: 'headers', value: JSON.stringify(slateNode.headers || []) },
-                { type: 'mdxJsxAttribute', name: 'queryParams', value: JSON.stringify(slateNode.queryParams || []) },
-                { type: 'mdxJsxAttribute', name: 'bodyType', value: slateNode.bodyType || 'none' },
-                { type: 'mdxJsxAttribute', name: 'bodyContent', value: slateNode.bodyContent || '' },
-                { type: 'mdxJsxAttribute', name: 'formData', value: JSON.stringify(slateNode.formData || []) },
-                { type: 'mdxJsxAttribute', name: 'auth', value: JSON.stringify(slateNode.auth || { type: 'none' }) },
-                { type: 'mdxJsxAttribute', name: 'response', value: slateNode.response ? JSON.stringify(slateNode.response) : '' },
-              ],
+              attributes,
               children: [{ type: 'text', value: '' }],
             };
           },
@@ -158,6 +176,8 @@ export const MarkdownKit = [
               formData: parseJsonAttr('formData', []),
               auth: parseJsonAttr('auth', { type: 'none' }),
               response: parseJsonAttr('response', undefined),
+              expectStatus: getAttr('expectStatus') || undefined,
+              expectJson: getAttr('expectJson') || undefined,
               children: [{ text: '' }],
             };
           }
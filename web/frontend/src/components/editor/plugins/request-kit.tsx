@@ -33,6 +33,7 @@ export interface HttpResponse {
     statusText: string;
     headers: Record<string, string>;
     body: string;
+    encoding?: 'base64'; // set when the body is binary
     time: number; // ms
     size: number; // bytes
     cookies: string[];
@@ -48,6 +49,8 @@ export interface HttpRequestElementType {
     bodyContent: string;
     formData: KeyValuePair[];
     auth: HttpAuth;
+    expectStatus?: string; // checked by `doclific http run`, e.g. "200" or "2xx"
+    expectJson?: string; // JSON object of JSONPath expressions to expected values
     response?: HttpResponse;
     children: [{ text: '' }];
     [key: string]: any;
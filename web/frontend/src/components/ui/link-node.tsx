@@ -6,7 +6,9 @@ import type { PlateElementProps } from 'platejs/react';
 import { getLinkAttributes } from '@platejs/link';
 import { SuggestionPlugin } from '@platejs/suggestion/react';
 import { PlateElement } from 'platejs/react';
+import { useNavigate } from 'react-router';
 
+import { isDocLink, useDocLinkPath } from '@/hooks/use-doc-link';
 import { cn } from '@/lib/utils';
 
 export function LinkElement(props: PlateElementProps<TLinkElement>) {
@@ -16,18 +18,34 @@ export function LinkElement(props: PlateElementProps<TLinkElement>) {
     | TInlineSuggestionData
     | undefined;
 
+  // doc: links point at the linked doc's page; Ctrl/Cmd-click opens it like a regular link
+  const navigate = useNavigate();
+  const docPath = useDocLinkPath(props.element.url);
+  const docLink = isDocLink(props.element.url);
+
   return (
     <PlateElement
       {...props}
       as="a"
       className={cn(
         'font-medium text-primary underline decoration-primary underline-offset-4',
+        docLink && !docPath && 'decoration-destructive decoration-wavy',
         suggestionData?.type === 'remove' && 'bg-red-100 text-red-700',
         suggestionData?.type === 'insert' && 'bg-emerald-100 text-emerald-700'
       )}
       attributes={{
         ...props.attributes,
         ...getLinkAttributes(props.editor, props.element),
+        ...(docLink && {
+          href: docPath,
+          title: docPath ? undefined : 'Linked doc not found',
+          onClick: (e) => {
+            if (docPath && (e.metaKey || e.ctrlKey)) {
+              e.preventDefault();
+              navigate(docPath);
+            }
+          },
+        }),
         onMouseOver: (e) => {
           e.stopPropagation();
         },
@@ -25,9 +25,11 @@ import {
   useFormInputProps,
   usePluginOption,
 } from 'platejs/react';
+import { useNavigate } from 'react-router';
 
 import { buttonVariants } from '@/components/ui/button';
 import { Separator } from '@/components/ui/separator';
+import { isDocLink, useDocLinkPath } from '@/hooks/use-doc-link';
 
 const popoverVariants = cva(
   'z-50 w-auto rounded-md border bg-popover p-1 text-popover-foreground shadow-md outline-hidden'
@@ -172,20 +174,46 @@ function LinkOpenButton() {
   const editor = useEditorRef();
   const selection = useEditorSelection();
 
-  const attributes = React.useMemo(
+  const navigate = useNavigate();
+
+  const element = React.useMemo(
     () => {
       const entry = editor.api.node<TLinkElement>({
         match: { type: editor.getType(KEYS.link) },
       });
-      if (!entry) {
-        return {};
-      }
-      const [element] = entry;
-      return getLinkAttributes(editor, element);
+      return entry?.[0];
     },
     // eslint-disable-next-line react-hooks/exhaustive-deps
     [editor, selection]
   );
+  const attributes = element ? getLinkAttributes(editor, element) : {};
+  const docPath = useDocLinkPath(element?.url);
+
+  // doc: links open the linked doc in place instead of a new tab
+  if (isDocLink(element?.url)) {
+    return (
+      <a
+        href={docPath}
+        className={buttonVariants({
+          size: 'sm',
+          variant: 'ghost',
+        })}
+        onClick={(e) => {
+          e.preventDefault();
+          if (docPath) {
+            navigate(docPath);
+          }
+        }}
+        onMouseOver={(e) => {
+          e.stopPropagation();
+        }}
+        aria-label={docPath ? 'Open linked doc' : 'Linked doc not found'}
+        aria-disabled={!docPath}
+      >
+        <ExternalLink width={18} />
+      </a>
+    );
+  }
 
   return (
     <a
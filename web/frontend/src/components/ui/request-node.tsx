@@ -8,7 +8,7 @@ import type {
     HttpResponse,
     HttpAuth
 } from '@/components/editor/plugins/request-kit';
-import { useEffect, useState, useMemo, useCallback } from 'react';
+import { useEffect, useState, useCallback, useMemo } from 'react';
 import { useTheme } from '@/components/theme-provider';
 import { createHighlighter, type Highlighter } from 'shiki';
 import {
@@ -44,6 +44,13 @@ import {
     SelectValue
 } from './select';
 import { cn } from '@/lib/utils';
+import {
+    executeHttpRequest,
+    getHttpEnvironments,
+    setActiveHttpEnvironment,
+    HttpProxyError,
+    type HttpEnvironments
+} from '@/api/http';
 
 // Singleton highlighter cache
 let highlighterPromise: Promise<Highlighter> | null = null;
@@ -88,7 +95,7 @@ const METHOD_COLORS: Record<HttpMethod, string> = {
     OPTIONS: 'text-cyan-500',
 };
 
-type RequestTab = 'params' | 'headers' | 'body' | 'auth';
+type RequestTab = 'params' | 'headers' | 'body' | 'auth' | 'assertions';
 type ResponseTab = 'body' | 'headers' | 'cookies';
 type BodyViewTab = 'pretty' | 'raw' | 'preview';
 
@@ -256,7 +263,7 @@ function AuthSection({
                         <Input
                             value={auth.token || ''}
                             onChange={(e) => onChange({ ...auth, token: e.target.value })}
-                            placeholder="Enter bearer token"
+                            placeholder="Enter bearer token or {{token}}"
                             className="flex-1"
                         />
                     </div>
@@ -401,7 +408,160 @@ function BodyEditor({
     );
 }
 
+// Assertions Section Component
+function AssertionsSection({
+    expectStatus,
+    expectJson,
+    onExpectStatusChange,
+    onExpectJsonChange
+}: {
+    expectStatus: string;
+    expectJson: string;
+    onExpectStatusChange: (value: string) => void;
+    onExpectJsonChange: (value: string) => void;
+}) {
+    return (
+        <div className="space-y-3">
+            <p className="text-sm text-muted-foreground">
+                Checked when the request runs as a test with <code className="font-mono">doclific http run</code>.
+            </p>
+            <div className="flex items-center gap-3">
+                <Label className="w-32 text-sm">Expected status</Label>
+                <Input
+                    value={expectStatus}
+                    onChange={(e) => onExpectStatusChange(e.target.value)}
+                    placeholder="2xx"
+                    className="w-48 font-mono text-sm"
+                />
+            </div>
+            <div className="space-y-2">
+                <Label className="text-sm">Expected JSON values</Label>
+                <textarea
+                    value={expectJson}
+                    onChange={(e) => onExpectJsonChange(e.target.value)}
+                    placeholder='{"$.user.name": "Ada", "$.items.length": 2}'
+                    className="w-full h-24 p-3 text-sm font-mono bg-muted rounded-md border border-input resize-y"
+                />
+            </div>
+        </div>
+    );
+}
+
 // Response Viewer Component
+// Content types whose bodies are shown as text even when they arrive base64-encoded
+const TEXT_CONTENT_TYPE = /^text\/|json|xml|javascript|x-www-form-urlencoded/;
+
+/**
+ * Decode a base64 body as text in the charset of its content type
+ * @returns The text, or null when the content type is binary
+ */
+function decodeTextBody(body: string, contentType: string): string | null {
+    if (!TEXT_CONTENT_TYPE.test(contentType)) {
+        return null;
+    }
+
+    const charset = /charset=([^;]+)/i.exec(contentType)?.[1]?.trim() || 'utf-8';
+    try {
+        const bytes = Uint8Array.from(atob(body), c => c.charCodeAt(0));
+        return new TextDecoder(charset).decode(bytes);
+    } catch {
+        return null;
+    }
+}
+
+/**
+ * Encode binary response bytes as base64 for storing in an HttpResponse
+ */
+function encodeBase64(bytes: Uint8Array): string {
+    let binary = '';
+    for (let i = 0; i < bytes.length; i += 0x8000) {
+        binary += String.fromCharCode(...bytes.subarray(i, i + 0x8000));
+    }
+    return btoa(binary);
+}
+
+/**
+ * Send a request straight from the browser, as blocks did before the local proxy
+ * Used when the proxy refuses a host that is not on the allow-list; only works for APIs that allow CORS
+ */
+async function sendFromBrowser(element: HttpRequestElementType): Promise<HttpResponse> {
+    const startTime = performance.now();
+
+    const headers: Record<string, string> = {};
+    element.headers?.forEach(h => {
+        if (h.enabled && h.key) {
+            headers[h.key] = h.value;
+        }
+    });
+
+    if (element.auth?.type === 'basic' && element.auth.username) {
+        headers['Authorization'] = `Basic ${btoa(`${element.auth.username}:${element.auth.password || ''}`)}`;
+    } else if (element.auth?.type === 'bearer' && element.auth.token) {
+        headers['Authorization'] = `Bearer ${element.auth.token}`;
+    } else if (element.auth?.type === 'apikey' && element.auth.apiKeyLocation === 'header' && element.auth.apiKeyName && element.auth.apiKeyValue) {
+        headers[element.auth.apiKeyName] = element.auth.apiKeyValue;
+    }
+
+    let body: string | FormData | undefined;
+    if (element.method !== 'GET' && element.method !== 'HEAD') {
+        if (element.bodyType === 'json') {
+            headers['Content-Type'] = 'application/json';
+            body = element.bodyContent;
+        } else if (element.bodyType === 'raw') {
+            body = element.bodyContent;
+        } else if (element.bodyType === 'x-www-form-urlencoded') {
+            headers['Content-Type'] = 'application/x-www-form-urlencoded';
+            const params = new URLSearchParams();
+            element.formData?.forEach(f => {
+                if (f.enabled && f.key) {
+                    params.append(f.key, f.value);
+                }
+            });
+            body = params.toString();
+        } else if (element.bodyType === 'form-data') {
+            const formData = new FormData();
+            element.formData?.forEach(f => {
+                if (f.enabled && f.key) {
+                    formData.append(f.key, f.value);
+                }
+            });
+            body = formData;
+        }
+    }
+
+    const url = new URL(element.url);
+    element.queryParams?.forEach(p => {
+        if (p.enabled && p.key) {
+            url.searchParams.append(p.key, p.value);
+        }
+    });
+    if (element.auth?.type === 'apikey' && element.auth.apiKeyLocation === 'query' && element.auth.apiKeyName && element.auth.apiKeyValue) {
+        url.searchParams.append(element.auth.apiKeyName, element.auth.apiKeyValue);
+    }
+
+    const response = await fetch(url, { method: element.method, headers, body });
+    const bytes = new Uint8Array(await response.arrayBuffer());
+
+    const responseHeaders: Record<string, string> = {};
+    response.headers.forEach((value, key) => {
+        responseHeaders[key] = value;
+    });
+
+    const contentType = responseHeaders['content-type'] || '';
+    const isText = !contentType || TEXT_CONTENT_TYPE.test(contentType);
+
+    return {
+        status: response.status,
+        statusText: response.statusText,
+        headers: responseHeaders,
+        body: isText ? new TextDecoder().decode(bytes) : encodeBase64(bytes),
+        encoding: isText ? undefined : 'base64',
+        time: Math.round(performance.now() - startTime),
+        size: bytes.length,
+        cookies: [], // browsers do not expose Set-Cookie
+    };
+}
+
 function ResponseViewer({
     response,
     onDelete
@@ -426,20 +586,27 @@ function ResponseViewer({
 
     const detectedLang = isJson ? 'json' : isXml ? 'xml' : isHtml ? 'html' : 'text';
 
+    // Binary bodies arrive base64-encoded: textual ones are decoded with their charset, others can only be previewed
+    const isBinary = response.encoding === 'base64';
+    const textBody = useMemo(
+        () => (isBinary ? decodeTextBody(response.body, contentType) : response.body),
+        [isBinary, response.body, contentType]
+    );
+
     useEffect(() => {
         getHighlighter(detectedLang).then(setHighlighter).catch(console.error);
     }, [detectedLang]);
 
     useEffect(() => {
-        if (!highlighter || !response.body) return;
+        if (!highlighter || !textBody) return;
 
         const highlight = async () => {
-            let bodyToHighlight = response.body;
+            let bodyToHighlight = textBody;
 
             // Try to format JSON
             if (isJson) {
                 try {
-                    bodyToHighlight = JSON.stringify(JSON.parse(response.body), null, 2);
+                    bodyToHighlight = JSON.stringify(JSON.parse(textBody), null, 2);
                 } catch {
                     // Keep original if parsing fails
                 }
@@ -452,12 +619,12 @@ function ResponseViewer({
                 });
                 setHighlightedBody(html);
             } catch {
-                setHighlightedBody(`<pre>${response.body}</pre>`);
+                setHighlightedBody(`<pre>${textBody}</pre>`);
             }
         };
 
         highlight();
-    }, [highlighter, response.body, detectedLang, theme, isJson]);
+    }, [highlighter, textBody, detectedLang, theme, isJson]);
 
     const statusColor = response.status >= 200 && response.status < 300
         ? 'text-green-500'
@@ -472,7 +639,7 @@ function ResponseViewer({
     };
 
     const handleCopy = () => {
-        navigator.clipboard.writeText(response.body);
+        navigator.clipboard.writeText(textBody ?? response.body);
         toast.success('Response copied to clipboard');
     };
 
@@ -569,29 +736,36 @@ function ResponseViewer({
                             </div>
 
                             <div className="bg-muted rounded-md overflow-hidden max-h-96 overflow-y-auto">
-                                {bodyViewTab === 'pretty' && (
+                                {bodyViewTab !== 'preview' && textBody === null && (
+                                    <p className="p-4 text-sm text-muted-foreground italic">
+                                        Binary body ({formatSize(response.size)}){isImage ? ', see Preview' : ''}
+                                    </p>
+                                )}
+                                {bodyViewTab === 'pretty' && textBody !== null && (
                                     <div
                                         className="p-4 text-sm font-mono [&_pre]:m-0 [&_pre]:bg-transparent"
                                         dangerouslySetInnerHTML={{ __html: highlightedBody }}
                                     />
                                 )}
-                                {bodyViewTab === 'raw' && (
+                                {bodyViewTab === 'raw' && textBody !== null && (
                                     <pre className="p-4 text-sm font-mono whitespace-pre-wrap break-all">
-                                        {response.body}
+                                        {textBody}
                                     </pre>
                                 )}
                                 {bodyViewTab === 'preview' && (
                                     <div className="p-4">
                                         {isImage && (
                                             <img
-                                                src={`data:${contentType};base64,${response.body}`}
+                                                src={isBinary
+                                                    ? `data:${contentType};base64,${response.body}`
+                                                    : `data:${contentType},${encodeURIComponent(response.body)}`}
                                                 alt="Response preview"
                                                 className="max-w-full"
                                             />
                                         )}
                                         {isHtml && (
                                             <iframe
-                                                srcDoc={response.body}
+                                                srcDoc={textBody ?? ''}
                                                 className="w-full h-64 bg-white rounded"
                                                 sandbox="allow-same-origin"
                                             />
@@ -641,6 +815,9 @@ function ResponseViewer({
     );
 }
 
+// Select value for clearing the active environment; Radix selects cannot use an empty value
+const NO_ENVIRONMENT = '__none__';
+
 // Main Component
 export function HttpRequestElement({
     attributes,
@@ -650,24 +827,25 @@ export function HttpRequestElement({
     const editor = useEditorRef();
     const [requestTab, setRequestTab] = useState<RequestTab>('params');
     const [isLoading, setIsLoading] = useState(false);
+    const [environments, setEnvironments] = useState<HttpEnvironments | null>(null);
 
-    const updateElement = useCallback((updates: Partial<HttpRequestElementType>) => {
-        editor.tf.setNodes(updates, { at: element });
-    }, [editor, element]);
-
-    const buildUrl = useMemo(() => {
-        let url = element.url || '';
-        const enabledParams = element.queryParams?.filter(p => p.enabled && p.key) || [];
+    useEffect(() => {
+        getHttpEnvironments()
+            .then(setEnvironments)
+            .catch(() => setEnvironments(null));
+    }, []);
 
-        if (enabledParams.length > 0) {
-            const queryString = enabledParams
-                .map(p => `${encodeURIComponent(p.key)}=${encodeURIComponent(p.value)}`)
-                .join('&');
-            url += (url.includes('?') ? '&' : '?') + queryString;
+    const changeEnvironment = async (name: string) => {
+        try {
+            setEnvironments(await setActiveHttpEnvironment(name === NO_ENVIRONMENT ? '' : name));
+        } catch (error) {
+            toast.error(error instanceof Error ? error.message : 'Failed to set environment');
         }
+    };
 
-        return url;
-    }, [element.url, element.queryParams]);
+    const updateElement = useCallback((updates: Partial<HttpRequestElementType>) => {
+        editor.tf.setNodes(updates, { at: element });
+    }, [editor, element]);
 
     const sendRequest = async () => {
         if (!element.url) {
@@ -679,97 +857,51 @@ export function HttpRequestElement({
         const startTime = performance.now();
 
         try {
-            // Build headers
-            const headers: Record<string, string> = {};
-            element.headers?.forEach(h => {
-                if (h.enabled && h.key) {
-                    headers[h.key] = h.value;
-                }
-            });
+            let httpResponse: HttpResponse;
+            try {
+                // Sent through the local server so CORS and localhost-only services are not a problem
+                const result = await executeHttpRequest({
+                    method: element.method,
+                    url: element.url,
+                    headers: element.headers || [],
+                    queryParams: element.queryParams || [],
+                    bodyType: element.bodyType || 'none',
+                    bodyContent: element.bodyContent || '',
+                    formData: element.formData || [],
+                    auth: element.auth || { type: 'none' },
+                });
 
-            // Add auth headers
-            if (element.auth?.type === 'basic' && element.auth.username) {
-                const credentials = btoa(`${element.auth.username}:${element.auth.password || ''}`);
-                headers['Authorization'] = `Basic ${credentials}`;
-            } else if (element.auth?.type === 'bearer' && element.auth.token) {
-                headers['Authorization'] = `Bearer ${element.auth.token}`;
-            } else if (element.auth?.type === 'apikey' && element.auth.apiKeyName && element.auth.apiKeyValue) {
-                if (element.auth.apiKeyLocation === 'header') {
-                    headers[element.auth.apiKeyName] = element.auth.apiKeyValue;
+                httpResponse = {
+                    status: result.status,
+                    statusText: result.statusText,
+                    headers: result.headers,
+                    body: result.body,
+                    encoding: result.encoding,
+                    time: result.time,
+                    size: result.size,
+                    cookies: result.cookies,
+                };
+            } catch (proxyError) {
+                // Hosts outside the allow-list can still be called from the browser if they allow CORS,
+                // unless the request uses {{variables}}, which only the server can fill in
+                const { url, headers, queryParams, bodyContent, formData, auth } = element;
+                const usesVariables = JSON.stringify({ url, headers, queryParams, bodyContent, formData, auth }).includes('{{');
+                if (!(proxyError instanceof HttpProxyError) || proxyError.status !== 403 || usesVariables) {
+                    throw proxyError;
                 }
-            }
-
-            // Build body
-            let body: string | FormData | undefined;
-            if (element.method !== 'GET' && element.method !== 'HEAD') {
-                if (element.bodyType === 'json') {
-                    headers['Content-Type'] = 'application/json';
-                    body = element.bodyContent;
-                } else if (element.bodyType === 'raw') {
-                    body = element.bodyContent;
-                } else if (element.bodyType === 'x-www-form-urlencoded') {
-                    headers['Content-Type'] = 'application/x-www-form-urlencoded';
-                    const params = new URLSearchParams();
-                    element.formData?.forEach(f => {
-                        if (f.enabled && f.key) {
-                            params.append(f.key, f.value);
-                        }
-                    });
-                    body = params.toString();
-                } else if (element.bodyType === 'form-data') {
-                    const formData = new FormData();
-                    element.formData?.forEach(f => {
-                        if (f.enabled && f.key) {
-                            formData.append(f.key, f.value);
-                        }
-                    });
-                    body = formData;
+                try {
+                    httpResponse = await sendFromBrowser(element);
+                } catch {
+                    throw proxyError; // its message has the command allowing the host
                 }
             }
 
-            // Build URL with query params (including API key if needed)
-            let requestUrl = buildUrl;
-            if (element.auth?.type === 'apikey' && element.auth.apiKeyLocation === 'query' && element.auth.apiKeyName && element.auth.apiKeyValue) {
-                requestUrl += (requestUrl.includes('?') ? '&' : '?') +
-                    `${encodeURIComponent(element.auth.apiKeyName)}=${encodeURIComponent(element.auth.apiKeyValue)}`;
-            }
-
-            const response = await fetch(requestUrl, {
-                method: element.method,
-                headers,
-                body: body instanceof FormData ? body : body,
-            });
-
-            const endTime = performance.now();
-            const responseText = await response.text();
-
-            // Extract response headers
-            const responseHeaders: Record<string, string> = {};
-            response.headers.forEach((value, key) => {
-                responseHeaders[key] = value;
-            });
-
-            // Extract cookies
-            const cookies = responseHeaders['set-cookie']
-                ? [responseHeaders['set-cookie']]
-                : [];
-
-            const httpResponse: HttpResponse = {
-                status: response.status,
-                statusText: response.statusText,
-                headers: responseHeaders,
-                body: responseText,
-                time: Math.round(endTime - startTime),
-                size: new Blob([responseText]).size,
-                cookies,
-            };
-
             updateElement({ response: httpResponse });
 
-            if (response.ok) {
-                toast.success(`Request completed: ${response.status} ${response.statusText}`);
+            if (httpResponse.status >= 200 && httpResponse.status < 300) {
+                toast.success(`Request completed: ${httpResponse.status} ${httpResponse.statusText}`);
             } else {
-                toast.error(`Request failed: ${response.status} ${response.statusText}`);
+                toast.error(`Request failed: ${httpResponse.status} ${httpResponse.statusText}`);
             }
         } catch (error) {
             const endTime = performance.now();
@@ -835,6 +967,25 @@ export function HttpRequestElement({
                     />
                 </div>
 
+                {environments && environments.environments.length > 0 && (
+                    <Select
+                        value={environments.active || NO_ENVIRONMENT}
+                        onValueChange={changeEnvironment}
+                    >
+                        <SelectTrigger className="w-36" title="Environment for {{variables}}">
+                            <SelectValue />
+                        </SelectTrigger>
+                        <SelectContent>
+                            <SelectItem value={NO_ENVIRONMENT}>No Environment</SelectItem>
+                            {environments.environments.map(environment => (
+                                <SelectItem key={environment.name} value={environment.name}>
+                                    {environment.name}
+                                </SelectItem>
+                            ))}
+                        </SelectContent>
+                    </Select>
+                )}
+
                 <Button
                     onClick={sendRequest}
                     disabled={isLoading}
@@ -884,6 +1035,15 @@ export function HttpRequestElement({
                             <Check className="h-3 w-3 ml-1 text-green-500 inline" />
                         )}
                     </TabButton>
+                    <TabButton
+                        active={requestTab === 'assertions'}
+                        onClick={() => setRequestTab('assertions')}
+                    >
+                        Assertions
+                        {(element.expectStatus || element.expectJson) && (
+                            <Check className="h-3 w-3 ml-1 text-green-500 inline" />
+                        )}
+                    </TabButton>
                 </div>
 
                 <div className="py-4">
@@ -920,6 +1080,15 @@ export function HttpRequestElement({
                             onChange={(auth) => updateElement({ auth })}
                         />
                     )}
+
+                    {requestTab === 'assertions' && (
+                        <AssertionsSection
+                            expectStatus={element.expectStatus || ''}
+                            expectJson={element.expectJson || ''}
+                            onExpectStatusChange={(expectStatus) => updateElement({ expectStatus })}
+                            onExpectJsonChange={(expectJson) => updateElement({ expectJson })}
+                        />
+                    )}
                 </div>
             </div>
 
@@ -0,0 +1,28 @@
+import { useQuery } from '@tanstack/react-query';
+
+import { resolveDocLink } from '@/api/docs';
+
+// Links to other docs use this scheme with the target's folder UUID or slug path, e.g. doc:architecture/backend
+export const DOC_LINK_SCHEME = 'doc:';
+
+export const isDocLink = (url?: string) => !!url?.startsWith(DOC_LINK_SCHEME);
+
+/**
+ * Resolves a doc: link to the in-app path of the doc it points at
+ * @returns The path, such as /<folderPath>#anchor, or undefined while loading, for other links and for broken links
+ */
+export const useDocLinkPath = (url?: string) => {
+  const [target, anchor] = (url ?? '').slice(DOC_LINK_SCHEME.length).split('#', 2);
+
+  const resolveQuery = useQuery({
+    queryKey: ['docs', 'link', target],
+    queryFn: () => resolveDocLink(target),
+    enabled: isDocLink(url) && target !== '',
+    retry: false,
+  });
+
+  if (!resolveQuery.data) {
+    return undefined;
+  }
+  return `/${resolveQuery.data.filePath}${anchor ? `#${anchor}` : ''}`;
+};
@@ -1,17 +1,43 @@
 import { useMutation, useQuery } from "@tanstack/react-query"
-import { useLocation } from "react-router"
+import { useLocation, useNavigate } from "react-router"
 import { useEffect } from "react"
 import RichTextEditor from "@/components/editor-container";
-import { getDoc, updateDoc } from "@/api/docs";
+import { DocSlugField, getSlugPath } from "@/components/doc-slug-field";
+import { DocBacklinks } from "@/components/doc-backlinks";
+import { getDoc, getDocs, resolveSlug, updateDoc } from "@/api/docs";
 
 export default function RTE() {
 
     const { pathname } = useLocation()
+    const navigate = useNavigate()
     const filePath = pathname.slice(1)
+
+    // The path is a folder path when it is in the tree; otherwise it may be a slug path such as a pasted link
+    const docsQuery = useQuery({
+        queryKey: ["docs", "get-docs"],
+        queryFn: getDocs,
+        enabled: true,
+    })
+    const isFolderPath = docsQuery.data ? getSlugPath(docsQuery.data, filePath) !== null : false
+
+    const slugQuery = useQuery({
+        queryKey: ["docs", "resolve-slug", filePath],
+        queryFn: () => resolveSlug(filePath),
+        enabled: docsQuery.isSuccess && !isFolderPath && filePath !== "",
+        retry: false,
+    })
+
+    // Redirect slug paths, including old slugs, to the doc's folder path
+    useEffect(() => {
+        if (slugQuery.data) {
+            navigate(`/${slugQuery.data.filePath}`, { replace: true })
+        }
+    }, [slugQuery.data, navigate])
+
     const docQuery = useQuery({
         queryKey: ["docs", "get-doc", filePath],
         queryFn: () => getDoc(filePath),
-        enabled: true,
+        enabled: isFolderPath,
         refetchOnWindowFocus: true,
         refetchOnMount: true,
     })
@@ -19,7 +45,7 @@ export default function RTE() {
     // Refetch when page becomes visible (user returns to tab/window)
     useEffect(() => {
         const handleVisibilityChange = () => {
-            if (document.visibilityState === 'visible') {
+            if (document.visibilityState === 'visible' && isFolderPath) {
                 docQuery.refetch()
             }
         }
@@ -28,7 +54,7 @@ export default function RTE() {
         return () => {
             document.removeEventListener('visibilitychange', handleVisibilityChange)
         }
-    }, [docQuery])
+    }, [docQuery, isFolderPath])
 
     const updateDocMutation = useMutation({
         mutationKey: ["docs", "update-doc", filePath],
@@ -43,15 +69,22 @@ export default function RTE() {
         <div className="flex-1 relative">
             <div className="absolute inset-0 overflow-y-auto">
                 <div className="max-w-4xl mx-auto w-full relative p-4">
-                    {docQuery.data && (
+                    {slugQuery.isError && (
+                        <p className="text-muted-foreground text-sm">No doc found at /{filePath}</p>
+                    )}
+                    {isFolderPath && docsQuery.data && (
+                        <DocSlugField docs={docsQuery.data} filePath={filePath} />
+                    )}
+                    {isFolderPath && docQuery.data && (
                         <RichTextEditor
                             key={filePath}
                             initialMarkdown={docQuery.data}
                             onUpdate={onUpdate}
                         />
                     )}
+                    {isFolderPath && <DocBacklinks filePath={filePath} />}
                 </div>
             </div>
         </div>
     )
-}
\ No newline at end of file
+}
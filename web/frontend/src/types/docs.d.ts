@@ -1,7 +1,14 @@
+export type DocStatus = 'draft' | 'published' | 'deprecated';
+
 export type FolderStructure = {
 	name: string;
 	title: string;
 	icon?: string;
 	order?: number;
+	slug?: string;
+	tags?: string[];
+	owners?: string[];
+	status?: DocStatus;
+	reviewedAt?: string;
 	children: FolderStructure[];
 };